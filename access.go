@@ -0,0 +1,161 @@
+package teishoku
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// AccessCount holds how often a component type was read and written, as
+// returned by World.AccessProfile.
+type AccessCount struct {
+	Reads  uint64 // pointers handed out by GetComponent, GetComponentN and filters' Get
+	Writes uint64 // values stored by SetComponent, SetComponentN and GetOrAdd
+}
+
+// accessCounters stores the counters behind AccessProfile, per component ID.
+// They are atomic because reads happen under the world's read lock, or with
+// no lock at all in filters.
+type accessCounters [MaxComponentTypes]struct {
+	reads, writes atomic.Uint64
+}
+
+// countRead records a read of the component id. It compiles to nothing
+// unless the ecsprofile build tag is set.
+func (w *World) countRead(id uint8) {
+	if accessProfiling {
+		w.access[id].reads.Add(1)
+	}
+}
+
+// countWrite records a write of the component id. It compiles to nothing
+// unless the ecsprofile build tag is set.
+func (w *World) countWrite(id uint8) {
+	if accessProfiling {
+		w.access[id].writes.Add(1)
+	}
+}
+
+// touchEntity records that the entity id was accessed during the current
+// frame, for AuditLeaks. It compiles to nothing unless the ecsprofile build
+// tag is set.
+func (w *World) touchEntity(id uint32) {
+	if accessProfiling {
+		w.touched[id].Store(w.frame.Load())
+	}
+}
+
+// growTouched extends the per-entity access frames to the world's capacity.
+func (w *World) growTouched() {
+	if !accessProfiling || len(w.touched) >= w.entities.capacity {
+		return
+	}
+	touched := make([]atomic.Uint32, w.entities.capacity)
+	for i := range w.touched {
+		touched[i].Store(w.touched[i].Load())
+	}
+	w.touched = touched
+}
+
+// AdvanceFrame moves the world to its next frame. Frames are only counted for
+// diagnostics such as AuditLeaks; call it once per game-loop iteration, unless
+// the loop already calls Tick, which advances the frame too.
+func (w *World) AdvanceFrame() {
+	w.frame.Add(1)
+}
+
+// Frame returns the number of times AdvanceFrame has been called.
+func (w *World) Frame() uint32 {
+	return w.frame.Load()
+}
+
+// defaultLeakFrames is the AuditLeaks threshold used until SetLeakThreshold
+// is called: ten seconds at 60 frames per second.
+const defaultLeakFrames = 600
+
+// SetLeakThreshold sets after how many frames without any access an entity is
+// reported by AuditLeaks. n <= 0 restores the default of 600 frames.
+//
+// Parameters:
+//   - n: The number of frames.
+func (w *World) SetLeakThreshold(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.leakFrames = max(n, 0)
+}
+
+// AuditLeaks returns the live entities that have not been accessed for at
+// least the number of frames set by SetLeakThreshold, to find entities a
+// long-running world forgot about: spawned and never cleaned up, or whose
+// owner lost track of them. An entity counts as accessed when it is created
+// or when one of its components is read or written through GetComponent,
+// SetComponent, GetOrAdd, their numbered forms or a filter's Get; frames are
+// counted with AdvanceFrame or Tick.
+//
+// Like AccessProfile, the tracking is only compiled in with the ecsprofile
+// build tag; without it AuditLeaks always returns nil.
+//
+// Returns:
+//   - The stale entities in archetype storage order, or nil if there are
+//     none.
+func (w *World) AuditLeaks() []Entity {
+	if !accessProfiling {
+		return nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	threshold := uint32(defaultLeakFrames)
+	if w.leakFrames > 0 {
+		threshold = uint32(w.leakFrames)
+	}
+	now := w.frame.Load()
+	var leaks []Entity
+	for _, a := range w.archetypes.archetypes {
+		for _, e := range a.entityIDs[:a.size] {
+			if now-w.touched[e.ID].Load() >= threshold {
+				leaks = append(leaks, e)
+			}
+		}
+	}
+	return leaks
+}
+
+// AccessProfile returns how often each component type was read and written
+// through the World's accessors, which tells hot components (candidates for
+// SetComponentPriority) from nearly static ones (candidates for
+// RegisterTransient or for being left out of hot filters). Filters count one
+// read per Get call; Apply, EachMut and Column are not counted.
+//
+// Counting is only compiled in with the ecsprofile build tag
+// (go build -tags ecsprofile), so regular builds pay nothing for it; without
+// the tag AccessProfile always returns nil.
+//
+// Returns:
+//   - The counters of every component type accessed at least once, or nil
+//     without the ecsprofile build tag.
+func (w *World) AccessProfile() map[reflect.Type]AccessCount {
+	if !accessProfiling {
+		return nil
+	}
+	profile := make(map[reflect.Type]AccessCount)
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	for id := range int(w.components.nextCompTypeID) {
+		c := AccessCount{Reads: w.access[id].reads.Load(), Writes: w.access[id].writes.Load()}
+		if c != (AccessCount{}) {
+			profile[w.components.compIDToType[id]] = c
+		}
+	}
+	return profile
+}
+
+// ResetAccessProfile sets every counter returned by AccessProfile back to
+// zero.
+func (w *World) ResetAccessProfile() {
+	if !accessProfiling {
+		return
+	}
+	for id := range w.access {
+		w.access[id].reads.Store(0)
+		w.access[id].writes.Store(0)
+	}
+}
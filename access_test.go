@@ -0,0 +1,62 @@
+//go:build ecsprofile
+
+package teishoku
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestAccessProfile(t *testing.T) {
+	w := NewWorld(8)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{})
+	SetComponent2(w, e, Position{}, Velocity{})
+	for range 3 {
+		GetComponent[Position](w, e)
+	}
+	f := NewFilter2[Position, Velocity](w)
+	for f.Next() {
+		f.Get()
+	}
+	prof := w.AccessProfile()
+	pos, vel := prof[reflect.TypeFor[Position]()], prof[reflect.TypeFor[Velocity]()]
+	if pos != (AccessCount{Reads: 4, Writes: 2}) || vel != (AccessCount{Reads: 1, Writes: 1}) {
+		t.Errorf("unexpected profile %+v", prof)
+	}
+	w.ResetAccessProfile()
+	if len(w.AccessProfile()) != 0 {
+		t.Error("expected an empty profile after reset")
+	}
+}
+
+func TestAuditLeaks(t *testing.T) {
+	w := NewWorld(2)
+	w.SetLeakThreshold(3)
+	used := w.CreateEntity()
+	SetComponent(w, used, Position{})
+	forgotten := w.CreateEntity()
+	SetComponent(w, forgotten, Position{})
+	iterated := w.CreateEntity()
+	SetComponent(w, iterated, Velocity{})
+	f := NewFilter[Velocity](w)
+	for range 5 {
+		w.AdvanceFrame()
+		GetComponent[Position](w, used)
+		for f.Reset(); f.Next(); {
+			f.Get()
+		}
+	}
+	fresh := w.CreateEntity()
+	leaks := w.AuditLeaks()
+	if len(leaks) != 1 || leaks[0] != forgotten {
+		t.Fatalf("expected only %v to leak, got %v", forgotten, leaks)
+	}
+	w.AdvanceFrame()
+	w.AdvanceFrame()
+	w.AdvanceFrame()
+	if !slices.Contains(w.AuditLeaks(), fresh) {
+		t.Error("expected the untouched new entity to be reported after the threshold")
+	}
+}
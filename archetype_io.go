@@ -0,0 +1,338 @@
+package teishoku
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// archetypeMagic starts every buffer written by ExportArchetype; the last
+// byte is the format version.
+var archetypeMagic = [4]byte{'T', 'S', 'A', 1}
+
+// ExportArchetype serializes the entities of the archetype with exactly the
+// given components: their IDs and versions, followed by the raw bytes of each
+// component column. It is a narrower and faster path than saving the whole
+// world, meant for incremental synchronization of one kind of entity (e.g.
+// every networked player state) to another World with ImportArchetype.
+//
+// Components are identified by type name and size, so both worlds must use
+// the same Go types. Only plain data can be exported: a component containing
+// pointers, slices, strings, maps or interfaces is rejected, since its raw
+// bytes are meaningless in another process.
+//
+// Parameters:
+//   - mask: The exact component mask of the archetype, e.g. from MaskOf.
+//
+// Returns:
+//   - The encoded archetype. It holds no entity if the archetype does not
+//     exist yet.
+//   - An error if the mask contains an unregistered component or a component
+//     that cannot be exported.
+func (w *World) ExportArchetype(mask Mask) ([]byte, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	specs, bad := w.maskSpecs(mask)
+	if bad >= 0 {
+		return nil, fmt.Errorf("ecs: ExportArchetype mask contains unregistered component ID %d", bad)
+	}
+	for _, sp := range specs {
+		if typeHasPointers(sp.typ) {
+			return nil, fmt.Errorf("ecs: component %v contains pointers and cannot be exported", sp.typ)
+		}
+	}
+	var a *archetype
+	if idx, ok := w.archetypes.maskToArcIndex[mask]; ok {
+		a = w.archetypes.archetypes[idx]
+	}
+	n := 0
+	if a != nil {
+		n = a.size
+	}
+
+	buf := append([]byte(nil), archetypeMagic[:]...)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(specs)))
+	for _, sp := range specs {
+		name := sp.typ.String()
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(name)))
+		buf = append(buf, name...)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(sp.size))
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(n))
+	for i := range n {
+		buf = binary.LittleEndian.AppendUint32(buf, a.entityIDs[i].ID)
+		buf = binary.LittleEndian.AppendUint32(buf, a.entityIDs[i].Version)
+	}
+	for _, sp := range specs {
+		if n > 0 && sp.size > 0 {
+			buf = append(buf, unsafe.Slice((*byte)(a.compPointers[sp.id]), uintptr(n)*sp.size)...)
+		}
+	}
+	return buf, nil
+}
+
+// errTruncated reports an ImportArchetype buffer that ends too early.
+var errTruncated = errors.New("ecs: truncated archetype data")
+
+// archetypeReader decodes the buffer written by ExportArchetype.
+type archetypeReader struct {
+	data []byte
+	err  error
+}
+
+func (r *archetypeReader) next(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *archetypeReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *archetypeReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// ImportArchetype applies data produced by ExportArchetype, possibly by
+// another World. Every exported entity is reconciled with this world:
+//
+//   - If the same handle (ID and version) is alive, its components are
+//     overwritten with the exported values. An entity holding only some of
+//     the components gains the others; components it has beyond the exported
+//     ones are kept.
+//   - Otherwise, if the ID is free, the entity is created under the exported
+//     handle, so a replica fed only by imports keeps the same handles as its
+//     source and later imports update instead of duplicating. IDs are only
+//     claimed below the world's capacity plus the number of imported
+//     entities, so the data cannot make the world grow beyond what it holds.
+//   - Otherwise the ID is taken by an unrelated entity, or too large, and the
+//     entity is created with a new handle.
+//
+// Every component type must be registered in this world with the same size.
+// Nothing is changed when an error is returned.
+//
+// Parameters:
+//   - data: The buffer returned by ExportArchetype.
+//
+// Returns:
+//   - The local entity of each exported entity, in export order.
+//   - An error if the data is malformed, references an unknown component,
+//     or holds more entities than the limit set by SetMaxEntities allows.
+func (w *World) ImportArchetype(data []byte) ([]Entity, error) {
+	r := &archetypeReader{data: data}
+	if magic := r.next(len(archetypeMagic)); r.err != nil || [4]byte(magic) != archetypeMagic {
+		return nil, errors.New("ecs: not an exported archetype")
+	}
+	numComps := int(r.uint16())
+	types := make([]reflect.Type, numComps)
+	byName := make(map[string]reflect.Type)
+	for _, t := range w.RegisteredComponents() {
+		byName[t.String()] = t
+	}
+	var mask bitmask256
+	ids := make([]uint8, numComps)
+	sizes := make([]uintptr, numComps)
+	for i := range numComps {
+		name := string(r.next(int(r.uint16())))
+		size := uintptr(r.uint32())
+		if r.err != nil {
+			return nil, r.err
+		}
+		t, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("ecs: component %s is not registered", name)
+		}
+		if t.Size() != size {
+			return nil, fmt.Errorf("ecs: component %s has %d bytes, data has %d", name, t.Size(), size)
+		}
+		types[i] = t
+		id, _ := w.lookupCompTypeID(t)
+		ids[i], sizes[i] = id, size
+		mask.set(id)
+	}
+	n := int(r.uint32())
+	// Check the count against the data before allocating from it.
+	if r.err == nil && uint64(n)*8 > uint64(len(r.data)) {
+		r.err = errTruncated
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	handles := make([]Entity, n)
+	for i := range handles {
+		handles[i] = Entity{ID: r.uint32(), Version: r.uint32()}
+	}
+	columns := make([][]byte, numComps)
+	for i := range columns {
+		if uint64(n)*uint64(sizes[i]) > uint64(len(r.data)) {
+			return nil, errTruncated
+		}
+		columns[i] = r.next(n * int(sizes[i]))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, errors.New("ecs: trailing bytes after exported archetype")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkOpen()
+	// Classify the entities before changing anything.
+	const (
+		update = iota
+		claim
+		fresh
+	)
+	kinds := make([]uint8, n)
+	claims := make(map[uint32]struct{})
+	need, maxID := 0, -1
+	for i, h := range handles {
+		switch {
+		case w.IsValidNoLock(h):
+			kinds[i] = update
+		case h.Version != 0 && int(h.ID) < w.entities.capacity+n && !w.isLiveIDNoLock(h.ID) && !hasKey(claims, h.ID):
+			kinds[i] = claim
+			claims[h.ID] = struct{}{}
+			maxID = max(maxID, int(h.ID))
+			need++
+		default:
+			kinds[i] = fresh
+			need++
+		}
+	}
+	if w.allowedNoLock(need) < need {
+		return nil, fmt.Errorf("ecs: importing %d entities exceeds the entity limit", need)
+	}
+	if maxID >= w.entities.capacity {
+		if w.maxEntities > 0 && maxID >= w.maxEntities {
+			return nil, fmt.Errorf("ecs: entity ID %d exceeds the entity limit", maxID)
+		}
+		w.expandTo(w.grownCapNoLock(maxID + 1))
+	}
+	w.takeFreeIDsNoLock(claims)
+	w.reserveNoLock(need - len(claims))
+
+	specs, _ := w.maskSpecs(mask)
+	a := w.getOrCreateArchetypeNoLock(mask, specs)
+	w.ensureStorage(a)
+	local := make([]Entity, n)
+	for i, h := range handles {
+		switch kinds[i] {
+		case update:
+			meta := &w.entities.metas[h.ID]
+			if cur := w.archetypes.archetypes[meta.archetypeIndex]; !cur.mask.contains(mask) {
+				w.moveEntityNoLock(meta, w.unionArchetypeNoLock(cur, mask))
+			}
+			local[i] = h
+		case claim:
+			meta := &w.entities.metas[h.ID]
+			meta.archetypeIndex = a.index
+			meta.index = a.size
+			meta.version = h.Version
+			meta.issued = max(meta.issued, h.Version)
+			if h.Version >= w.entities.nextEntityVer {
+				w.entities.nextEntityVer = h.Version + 1
+				if w.entities.nextEntityVer == 0 {
+					w.entities.nextEntityVer = 1
+					w.entities.versionsWrapped = true
+				}
+			}
+			a.entityIDs[a.size] = h
+			a.size++
+			w.touchEntity(h.ID)
+			if len(w.trackedIDs) > 0 {
+				w.markCreated(a, a.size-1, 1)
+			}
+			local[i] = h
+		case fresh:
+			local[i] = w.createEntityNoLock(a)
+		}
+		meta := w.entities.metas[local[i].ID]
+		dst := w.archetypes.archetypes[meta.archetypeIndex]
+		for c, id := range ids {
+			size := sizes[c]
+			memCopy(unsafe.Add(dst.compPointers[id], uintptr(meta.index)*size), unsafe.Pointer(unsafe.SliceData(columns[c][i*int(size):])), size)
+			w.markChanged(id, local[i].ID)
+		}
+	}
+	w.mutationVersion.Add(1)
+	return local, nil
+}
+
+// hasKey reports whether m contains k.
+func hasKey[K comparable, V any](m map[K]V, k K) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// isLiveIDNoLock reports whether the entity ID is currently held by a live
+// entity.
+func (w *World) isLiveIDNoLock(id uint32) bool {
+	return int(id) < len(w.entities.metas) && w.entities.metas[id].archetypeIndex >= 0
+}
+
+// takeFreeIDsNoLock removes the given IDs from the free list with no-lock, so
+// they can be assigned explicitly. Every ID must be free.
+func (w *World) takeFreeIDsNoLock(ids map[uint32]struct{}) {
+	if len(ids) == 0 {
+		return
+	}
+	w.entities.freeIDs = slices.DeleteFunc(w.entities.freeIDs, func(id uint32) bool {
+		return hasKey(ids, id)
+	})
+	if w.entities.deterministic {
+		slices.Sort(w.entities.freeIDs) // a sorted slice is a valid min-heap
+	}
+}
+
+// unionArchetypeNoLock returns, with no-lock, the archetype holding the
+// components of a plus those of mask, creating it if needed.
+func (w *World) unionArchetypeNoLock(a *archetype, mask bitmask256) *archetype {
+	var union bitmask256
+	for i := range union {
+		union[i] = a.mask[i] | mask[i]
+	}
+	if t := w.transitionNoLock(a, union); t != nil {
+		return t
+	}
+	specs, _ := w.maskSpecs(union)
+	return w.getOrCreateArchetypeNoLock(union, specs)
+}
+
+// moveEntityNoLock moves the entity described by meta to the end of dst with
+// no-lock, copying the components both archetypes store. Columns only in dst
+// are zeroed for the caller to fill.
+func (w *World) moveEntityNoLock(meta *entityMeta, dst *archetype) {
+	w.ensureStorage(dst)
+	src := w.archetypes.archetypes[meta.archetypeIndex]
+	newIdx := dst.size
+	dst.entityIDs[newIdx] = src.entityIDs[meta.index]
+	dst.size++
+	for _, cid := range dst.compOrder {
+		if !src.mask.has(cid) {
+			dst.zeroSlot(cid, newIdx)
+			continue
+		}
+		size := src.compSizes[cid]
+		memCopy(unsafe.Add(dst.compPointers[cid], uintptr(newIdx)*size), unsafe.Add(src.compPointers[cid], uintptr(meta.index)*size), size)
+	}
+	w.removeFromArchetype(src, meta)
+	meta.archetypeIndex = dst.index
+	meta.index = newIdx
+}
@@ -0,0 +1,111 @@
+package teishoku
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestExportImportArchetype(t *testing.T) {
+	server := NewWorld(8)
+	b := NewBuilder2[Position, Velocity](server)
+	p1 := b.NewEntityWith(Position{X: 1}, Velocity{DX: 1})
+	p2 := b.NewEntityWith(Position{X: 2}, Velocity{DX: 2})
+	server.CreateEntity() // not part of the export
+	mask := server.MaskOf(reflect.TypeFor[Position](), reflect.TypeFor[Velocity]())
+
+	data, err := server.ExportArchetype(mask)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewWorld(2)
+	NewFilter2[Position, Velocity](client) // register the component types
+	local, err := client.ImportArchetype(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(local) != 2 || local[0] != p1 || local[1] != p2 {
+		t.Fatalf("expected the replica to keep the exported handles, got %v", local)
+	}
+	if p := GetComponent[Position](client, p2); p == nil || p.X != 2 {
+		t.Fatalf("expected imported Position, got %v", p)
+	}
+
+	// A second import updates instead of duplicating, and keeps components
+	// the client added on its own.
+	SetComponent(client, p1, Health{HP: 3})
+	GetComponent[Position](server, p1).X = 10
+	data, _ = server.ExportArchetype(mask)
+	if _, err := client.ImportArchetype(data); err != nil {
+		t.Fatal(err)
+	}
+	if n := NewFilter2[Position, Velocity](client).Count(); n != 2 {
+		t.Fatalf("expected 2 entities after re-import, got %d", n)
+	}
+	if p, h := GetComponent[Position](client, p1), GetComponent[Health](client, p1); p.X != 10 || h == nil || h.HP != 3 {
+		t.Fatalf("expected an updated Position and a kept Health, got %v %v", p, h)
+	}
+
+	// Entities created locally afterwards never reuse an imported handle.
+	if e := client.CreateEntity(); e == p1 || e == p2 {
+		t.Fatalf("new local entity %v collides with an imported one", e)
+	}
+}
+
+func TestImportArchetypeErrors(t *testing.T) {
+	w := NewWorld(2)
+	SetComponent(w, w.CreateEntity(), Position{})
+	data, err := w.ExportArchetype(w.MaskOf(reflect.TypeFor[Position]()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWorld(2).ImportArchetype(data); err == nil {
+		t.Error("expected an error for an unregistered component")
+	}
+	if _, err := w.ImportArchetype(data[:len(data)-1]); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+	// An entity count the data cannot hold is rejected before allocating.
+	huge := slices.Clone(data)
+	countAt := len(huge) - 8 - int(reflect.TypeFor[Position]().Size()) - 4
+	for _, count := range []uint32{1 << 31, 2} {
+		binary.LittleEndian.PutUint32(huge[countAt:], count)
+		if _, err := w.ImportArchetype(huge); !errors.Is(err, errTruncated) {
+			t.Errorf("expected a truncation error for %d entities, got %v", count, err)
+		}
+	}
+	SetComponent(w, w.CreateEntity(), PathBuf{})
+	if _, err := w.ExportArchetype(w.MaskOf(reflect.TypeFor[PathBuf]())); err == nil {
+		t.Error("expected an error for a component with pointers")
+	}
+}
+
+func TestImportArchetypeHugeID(t *testing.T) {
+	src := NewWorld(2)
+	SetComponent(src, src.CreateEntity(), Position{X: 1})
+	data, err := src.ExportArchetype(src.MaskOf(reflect.TypeFor[Position]()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idAt := len(data) - 8 - int(reflect.TypeFor[Position]().Size())
+	binary.LittleEndian.PutUint32(data[idAt:], 1<<31)
+
+	dst := NewWorld(4)
+	RegisterComponent[Position](dst)
+	local, err := dst.ImportArchetype(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dst.entities.capacity > 8 {
+		t.Fatalf("importing a huge entity ID grew the world to %d", dst.entities.capacity)
+	}
+	if len(local) != 1 || local[0].ID == 1<<31 {
+		t.Fatalf("expected the entity to be created with a new handle, got %v", local)
+	}
+	if p := GetComponent[Position](dst, local[0]); p == nil || p.X != 1 {
+		t.Errorf("expected the imported position, got %v", p)
+	}
+}
@@ -0,0 +1,132 @@
+package teishoku
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// ArchetypeView is a read-scoped handle to a single archetype, handed out by
+// EachArchetype. It exposes the archetype's entities and typed component
+// columns so algorithms can work on whole storage blocks at once instead of
+// entity by entity.
+//
+// A view is tied to the world's mutation version at the time it was created.
+// Any structural change (creating, removing or moving entities) invalidates
+// it; Valid reports whether it can still be used, and Column panics on a stale
+// view.
+type ArchetypeView struct {
+	world   *World
+	arch    *archetype
+	version uint32
+}
+
+// Len returns the number of entities stored in the archetype.
+func (v ArchetypeView) Len() int {
+	return v.arch.size
+}
+
+// Entities returns the archetype's entities in storage order. The slice aliases
+// the archetype's internal storage and must not be modified.
+func (v ArchetypeView) Entities() []Entity {
+	return v.arch.entityIDs[:v.arch.size]
+}
+
+// Valid reports whether the view still reflects the archetype, i.e. whether no
+// structural change has happened since it was created.
+func (v ArchetypeView) Valid() bool {
+	return v.world != nil && v.world.mutationVersion.Load() == v.version
+}
+
+// Column returns the archetype's column of component `T` as a slice with one
+// element per entity, in the same order as Entities. Writes through the slice
+// update the components in place.
+//
+// If the archetype does not store `T`, Column returns nil. It panics if the
+// view is no longer valid.
+//
+// Parameters:
+//   - v: The archetype view to read from.
+//
+// Returns:
+//   - A slice over the component column, or nil if `T` is not present.
+func Column[T any](v ArchetypeView) []T {
+	if !v.Valid() {
+		panic("ecs: ArchetypeView used after a structural change")
+	}
+	id, ok := v.world.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return nil
+	}
+	i := id >> 6
+	o := id & 63
+	if (v.arch.mask[i] & (uint64(1) << uint64(o))) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*T)(v.arch.compPointers[id]), v.arch.size)
+}
+
+// EachArchetype calls fn once for every non-empty archetype matching the
+// filter, passing a view over the archetype's storage. This is useful for
+// algorithms that process whole blocks of entities, such as building a
+// per-archetype spatial grid or dumping one archetype's data to disk.
+//
+// fn must not perform structural changes; doing so invalidates the views
+// handed to it.
+//
+// Parameters:
+//   - fn: The function to call for each matching archetype.
+func (c *queryCache) EachArchetype(fn func(a ArchetypeView)) {
+	c.checkWorld()
+	c.world.mu.RLock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	arches := c.matchingArches
+	version := c.world.mutationVersion.Load()
+	c.world.mu.RUnlock()
+	if debugChecks {
+		c.world.mu.beginIteration()
+		defer c.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		if a.size == 0 {
+			continue
+		}
+		fn(ArchetypeView{world: c.world, arch: a, version: version})
+	}
+}
+
+// ForEachComponentColumn calls fn once for every non-empty archetype storing
+// the component type t, passing the archetype's view and the raw layout of
+// its column of t: the address of the first element, the number of elements
+// and the distance in bytes between consecutive elements. It is the untyped
+// counterpart of Column, for save systems, hot-reload and editor tooling that
+// sweep component data without knowing its Go type at compile time.
+//
+// fn must not perform structural changes; doing so invalidates the views and
+// columns handed to it. Nothing is called if t is not a registered component
+// type.
+//
+// Parameters:
+//   - t: The component type whose columns to visit.
+//   - fn: The function to call for each column.
+func (w *World) ForEachComponentColumn(t reflect.Type, fn func(a ArchetypeView, base unsafe.Pointer, count int, stride uintptr)) {
+	id, ok := w.lookupCompTypeID(t)
+	if !ok {
+		return
+	}
+	w.mu.RLock()
+	arches := w.archetypes.archetypes
+	version := w.mutationVersion.Load()
+	w.mu.RUnlock()
+	if debugChecks {
+		w.mu.beginIteration()
+		defer w.mu.endIteration()
+	}
+	for _, a := range arches {
+		if a.size == 0 || !a.mask.has(id) {
+			continue
+		}
+		fn(ArchetypeView{world: w, arch: a, version: version}, a.compPointers[id], a.size, a.compSizes[id])
+	}
+}
@@ -0,0 +1,166 @@
+package teishoku
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestEachArchetype(t *testing.T) {
+	w := NewWorld(TestCap)
+	NewBuilder2[Position, Velocity](w).NewEntitiesWithValueSet(3, Position{X: 1}, Velocity{DX: 2})
+	NewBuilder3[Position, Velocity, Health](w).NewEntitiesWithValueSet(2, Position{X: 1}, Velocity{DX: 2}, Health{HP: 5})
+	NewBuilder[Position](w).NewEntities(4)
+
+	f := NewFilter2[Position, Velocity](w)
+	archetypes, entities, healths := 0, 0, 0
+	f.EachArchetype(func(a ArchetypeView) {
+		archetypes++
+		entities += a.Len()
+		if len(a.Entities()) != a.Len() {
+			t.Errorf("expected %d entities, got %d", a.Len(), len(a.Entities()))
+		}
+		for i := range Column[Velocity](a) {
+			Column[Velocity](a)[i].DX *= 10
+		}
+		healths += len(Column[Health](a))
+		if Column[Dummy1](a) != nil {
+			t.Error("expected nil column for an unregistered component")
+		}
+	})
+	if archetypes != 2 || entities != 5 || healths != 2 {
+		t.Errorf("expected 2 archetypes, 5 entities, 2 healths; got %d, %d, %d", archetypes, entities, healths)
+	}
+	for f.Reset(); f.Next(); {
+		if _, v := f.Get(); v.DX != 20 {
+			t.Errorf("expected column writes to be visible, got %v", v.DX)
+		}
+	}
+}
+
+func TestArchetypeViewInvalidatedByMutation(t *testing.T) {
+	w := NewWorld(TestCap)
+	b := NewBuilder[Position](w)
+	b.NewEntities(2)
+	var view ArchetypeView
+	NewFilter[Position](w).EachArchetype(func(a ArchetypeView) { view = a })
+	if !view.Valid() {
+		t.Fatal("expected fresh view to be valid")
+	}
+	b.NewEntity()
+	if view.Valid() {
+		t.Error("expected view to be invalid after a structural change")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Column on a stale view to panic")
+		}
+	}()
+	Column[Position](view)
+}
+
+func TestForEachComponentColumn(t *testing.T) {
+	w := NewWorld(8)
+	for i := range 3 {
+		SetComponent(w, w.CreateEntity(), Position{X: float32(i)})
+	}
+	SetComponent2(w, w.CreateEntity(), Position{X: 10}, Velocity{})
+	SetComponent(w, w.CreateEntity(), Velocity{})
+
+	var sum float32
+	columns, total := 0, 0
+	w.ForEachComponentColumn(reflect.TypeFor[Position](), func(a ArchetypeView, base unsafe.Pointer, count int, stride uintptr) {
+		if count != a.Len() || stride != unsafe.Sizeof(Position{}) {
+			t.Fatalf("unexpected column layout: count %d for %d entities, stride %d", count, a.Len(), stride)
+		}
+		for i := range count {
+			p := (*Position)(unsafe.Add(base, uintptr(i)*stride))
+			sum += p.X
+			p.Y = 1
+		}
+		columns++
+		total += count
+	})
+	if columns != 2 || total != 4 || sum != 13 {
+		t.Fatalf("expected 2 columns with 4 entities summing to 13, got %d, %d, %v", columns, total, sum)
+	}
+	for f := NewFilter[Position](w); f.Next(); {
+		if f.Get().Y != 1 {
+			t.Fatal("expected writes through the column to update components")
+		}
+	}
+
+	w.ForEachComponentColumn(reflect.TypeFor[Health](), func(ArchetypeView, unsafe.Pointer, int, uintptr) {
+		t.Fatal("expected no call for an unregistered type")
+	})
+}
+
+// inlineBuf is an array component.
+type inlineBuf [16]byte
+
+// paddedRecord's size (10 bytes) exceeds the sum of its fields (8 bytes)
+// because of alignment padding around the uint8 fields.
+type paddedRecord struct {
+	A uint8
+	B [3]uint16
+	C uint8
+}
+
+func TestArrayComponentStrides(t *testing.T) {
+	if unsafe.Sizeof(paddedRecord{}) != 10 {
+		t.Fatalf("test assumes a 10-byte paddedRecord, got %d", unsafe.Sizeof(paddedRecord{}))
+	}
+	w := NewWorld(4)
+	var ents []Entity
+	for i := range 9 { // grows the world past its initial capacity
+		e := w.CreateEntity()
+		var buf inlineBuf
+		for k := range buf {
+			buf[k] = byte(i*16 + k)
+		}
+		SetComponent2(w, e, buf, paddedRecord{A: uint8(i), B: [3]uint16{uint16(i), 2, uint16(i * 3)}, C: ^uint8(i)})
+		ents = append(ents, e)
+	}
+	// A second archetype holding the same columns in another layout.
+	SetComponent(w, ents[8], Position{X: 8})
+
+	check := func(i int, buf inlineBuf, rec paddedRecord) {
+		t.Helper()
+		for k := range buf {
+			if buf[k] != byte(i*16+k) {
+				t.Fatalf("entity %d: buffer byte %d is %d", i, k, buf[k])
+			}
+		}
+		if rec.A != uint8(i) || rec.B != [3]uint16{uint16(i), 2, uint16(i * 3)} || rec.C != ^uint8(i) {
+			t.Fatalf("entity %d: unexpected record %+v", i, rec)
+		}
+	}
+	for i, e := range ents {
+		buf, rec := GetComponent2[inlineBuf, paddedRecord](w, e)
+		check(i, *buf, *rec)
+	}
+
+	bufs, recs := ComponentSlice[inlineBuf](w), ComponentSlice[paddedRecord](w)
+	if len(bufs) != 9 || len(recs) != 9 {
+		t.Fatalf("expected 9 values, got %d and %d", len(bufs), len(recs))
+	}
+	f := NewFilter2[inlineBuf, paddedRecord](w)
+	f.EachArchetype(func(a ArchetypeView) {
+		col := Column[paddedRecord](a)
+		for k, e := range a.Entities() {
+			check(slices.Index(ents, e), Column[inlineBuf](a)[k], col[k])
+		}
+	})
+	w.ForEachComponentColumn(reflect.TypeFor[paddedRecord](), func(a ArchetypeView, base unsafe.Pointer, count int, stride uintptr) {
+		if stride != 10 {
+			t.Errorf("expected a 10-byte stride, got %d", stride)
+		}
+		for k, e := range a.Entities() {
+			rec := *(*paddedRecord)(unsafe.Add(base, uintptr(k)*stride))
+			if rec != *GetComponent[paddedRecord](w, e) {
+				t.Errorf("column element %d does not match %v", k, e)
+			}
+		}
+	})
+}
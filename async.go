@@ -0,0 +1,27 @@
+package teishoku
+
+// enqueueAsync adds a deferred creation request to be applied by FlushAsync.
+// It is safe to call from any goroutine and never takes the world's lock.
+func (w *World) enqueueAsync(op func()) {
+	w.asyncMu.Lock()
+	w.asyncOps = append(w.asyncOps, op)
+	w.asyncMu.Unlock()
+}
+
+// FlushAsync applies the creation requests enqueued through async builders
+// (AsyncBuilder2 and up), in the order they were made. Call it from the
+// goroutine that owns the world, typically once per frame; requests enqueued
+// while the flush runs are kept for the next one.
+//
+// Returns:
+//   - The number of requests applied.
+func (w *World) FlushAsync() int {
+	w.asyncMu.Lock()
+	ops := w.asyncOps
+	w.asyncOps = nil
+	w.asyncMu.Unlock()
+	for _, op := range ops {
+		op()
+	}
+	return len(ops)
+}
@@ -0,0 +1,42 @@
+package teishoku
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAsyncBuilder(t *testing.T) {
+	w := NewWorld(4)
+	b := NewAsyncBuilder2[Position, Velocity](w)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			b.NewEntity()
+			b.NewEntitiesWithValueSet(2, Position{X: float32(i)}, Velocity{DX: 1})
+		})
+	}
+	wg.Wait()
+
+	f := NewFilter2[Position, Velocity](w)
+	if n := f.Count(); n != 0 {
+		t.Fatalf("expected no entities before FlushAsync, got %d", n)
+	}
+	if n := w.FlushAsync(); n != 16 {
+		t.Fatalf("expected 16 applied requests, got %d", n)
+	}
+	f.Reset()
+	total, withValue := 0, 0
+	for f.Next() {
+		total++
+		if _, v := f.Get(); v.DX == 1 {
+			withValue++
+		}
+	}
+	if total != 24 || withValue != 16 {
+		t.Errorf("expected 24 entities, 16 initialized; got %d and %d", total, withValue)
+	}
+	if n := w.FlushAsync(); n != 0 {
+		t.Errorf("expected an empty queue after flushing, got %d", n)
+	}
+}
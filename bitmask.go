@@ -19,6 +19,13 @@ func (m *bitmask256) unset(bit uint8) {
 	m[i] &= ^(uint64(1) << uint64(o))
 }
 
+// has reports whether the bit corresponding to the given component ID is set.
+func (m bitmask256) has(bit uint8) bool {
+	i := bit >> 6
+	o := bit & 63
+	return m[i]&(uint64(1)<<uint64(o)) != 0
+}
+
 // contains checks if all the bits set in the `sub` bitmask are also set in the
 // receiver bitmask `m`. This is used to determine if an archetype's component
 // set is a superset of a filter's required components.
@@ -34,3 +41,11 @@ func (m bitmask256) contains(sub bitmask256) bool {
 		(m[2]&sub[2]) == sub[2] &&
 		(m[3]&sub[3]) == sub[3]
 }
+
+// intersects reports whether the two bitmasks have at least one bit in common.
+func (m bitmask256) intersects(o bitmask256) bool {
+	return (m[0]&o[0]) != 0 ||
+		(m[1]&o[1]) != 0 ||
+		(m[2]&o[2]) != 0 ||
+		(m[3]&o[3]) != 0
+}
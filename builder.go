@@ -30,9 +30,7 @@ type Builder[T any] struct {
 //   - A pointer to the configured `Builder[T]`.
 func NewBuilder[T any](w *World) *Builder[T] {
 	t := reflect.TypeFor[T]()
-	w.components.mu.RLock()
-	id := w.getCompTypeIDNoLock(t)
-	w.components.mu.RUnlock()
+	id := w.getCompTypeID(t)
 	var mask bitmask256
 	mask.set(id)
 	w.components.mu.RLock()
@@ -53,11 +51,46 @@ func (b *Builder[T]) New(w *World) *Builder[T] {
 // collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder[T]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder[T]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with its component of type `T`
+// initialized to comp, under one acquisition of the World's lock.
+//
+// Parameters:
+//   - comp: The initial value for the component `T`.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder[T]) NewEntityWith(comp T) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	*(*T)(unsafe.Add(a.compPointers[b.compID], uintptr(a.size-1)*a.compSizes[b.compID])) = comp
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the component layout
 // defined by the builder. This is the most performant way to create many
 // entities at once, as it minimizes overhead by processing them in a single
@@ -68,32 +101,23 @@ func (b *Builder[T]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder[T]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder[T]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -103,34 +127,38 @@ func (b *Builder[T]) NewEntities(count int) {
 // Parameters:
 //   - count: The number of entities to create.
 //   - comp: The initial value for the component `T`.
-func (b *Builder[T]) NewEntitiesWithValueSet(count int, comp T) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder[T]) NewEntitiesWithValueSet(count int, comp T) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.compID, startSize, n, comp)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		ptr := unsafe.Pointer(uintptr(a.compPointers[b.compID]) + uintptr(startSize+k)*a.compSizes[b.compID])
-		*(*T)(ptr) = comp
-		w.entities.nextEntityVer++
+	return n
+}
+
+// fillColumn sets the n slots starting at start of the archetype's column id
+// to val. The value is written once, then the filled prefix is copied over
+// the rest, doubling at every step: a handful of large copies fill the range
+// contiguously instead of one strided store per entity.
+func fillColumn[T any](a *archetype, id uint8, start, n int, val T) {
+	if n <= 0 {
+		return
+	}
+	col := unsafe.Slice((*T)(unsafe.Add(a.compPointers[id], uintptr(start)*a.compSizes[id])), n)
+	col[0] = val
+	for filled := 1; filled < n; filled *= 2 {
+		copy(col[filled:], col[:filled])
 	}
-	w.mutationVersion.Add(1)
 }
 
 // Get retrieves a pointer to the component of type `T` for the given entity.
@@ -196,8 +224,8 @@ func (b *Builder[T]) Set(e Entity, comp T) {
 	newMask := a.mask
 	newMask.set(id)
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -212,6 +240,7 @@ func (b *Builder[T]) Set(e Entity, comp T) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
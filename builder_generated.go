@@ -28,11 +28,9 @@ func NewBuilder2[T1 any, T2 any](w *World) *Builder2[T1, T2] {
 	t1 := reflect.TypeFor[T1]()
 	t2 := reflect.TypeFor[T2]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 {
 		panic("ecs: duplicate component types in Builder2")
@@ -63,11 +61,50 @@ func (b *Builder2[T1, T2]) New(w *World) *Builder2[T1, T2] {
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder2[T1, T2]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder2[T1, T2]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the 2 components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder2[T1, T2]) NewEntityWith(comp1 T1, comp2 T2) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	*(*T1)(unsafe.Add(a.compPointers[b.id1], idx*a.compSizes[b.id1])) = comp1
+	*(*T2)(unsafe.Add(a.compPointers[b.id2], idx*a.compSizes[b.id2])) = comp2
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the 2 components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -75,32 +112,23 @@ func (b *Builder2[T1, T2]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder2[T1, T2]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder2[T1, T2]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -110,35 +138,64 @@ func (b *Builder2[T1, T2]) NewEntities(count int) {
 //   - count: The number of entities to create.
 //   - comp1: The initial value for the component T1.
 //   - comp2: The initial value for the component T2.
-func (b *Builder2[T1, T2]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder2[T1, T2]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		*(*T1)(unsafe.Pointer(uintptr(a.compPointers[b.id1]) + uintptr(startSize+k)*a.compSizes[b.id1])) = comp1
-		*(*T2)(unsafe.Pointer(uintptr(a.compPointers[b.id2]) + uintptr(startSize+k)*a.compSizes[b.id2])) = comp2
-		
-		w.entities.nextEntityVer++
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.id1, startSize, n, comp1)
+	fillColumn(a, b.id2, startSize, n, comp2)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
+}
+
+// AsyncBuilder2 queues the creation of entities with the components
+// T1, T2 from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder2[T1 any, T2 any] struct {
+	builder *Builder2[T1, T2]
+}
+
+// NewAsyncBuilder2 creates an AsyncBuilder2 for the components
+// T1, T2. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder2`.
+func NewAsyncBuilder2[T1 any, T2 any](w *World) *AsyncBuilder2[T1, T2] {
+	return &AsyncBuilder2[T1, T2]{builder: NewBuilder2[T1, T2](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder2[T1, T2]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+func (b *AsyncBuilder2[T1, T2]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, comp1, comp2)
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -210,8 +267,8 @@ func (b *Builder2[T1, T2]) Set(e Entity, v1 T1, v2 T2) {
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -233,6 +290,7 @@ func (b *Builder2[T1, T2]) Set(e Entity, v1 T1, v2 T2) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -288,12 +346,10 @@ func NewBuilder3[T1 any, T2 any, T3 any](w *World) *Builder3[T1, T2, T3] {
 	t2 := reflect.TypeFor[T2]()
 	t3 := reflect.TypeFor[T3]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 {
 		panic("ecs: duplicate component types in Builder3")
@@ -326,11 +382,52 @@ func (b *Builder3[T1, T2, T3]) New(w *World) *Builder3[T1, T2, T3] {
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder3[T1, T2, T3]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder3[T1, T2, T3]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the 3 components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder3[T1, T2, T3]) NewEntityWith(comp1 T1, comp2 T2, comp3 T3) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	*(*T1)(unsafe.Add(a.compPointers[b.id1], idx*a.compSizes[b.id1])) = comp1
+	*(*T2)(unsafe.Add(a.compPointers[b.id2], idx*a.compSizes[b.id2])) = comp2
+	*(*T3)(unsafe.Add(a.compPointers[b.id3], idx*a.compSizes[b.id3])) = comp3
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the 3 components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -338,32 +435,23 @@ func (b *Builder3[T1, T2, T3]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder3[T1, T2, T3]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder3[T1, T2, T3]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -374,36 +462,66 @@ func (b *Builder3[T1, T2, T3]) NewEntities(count int) {
 //   - comp1: The initial value for the component T1.
 //   - comp2: The initial value for the component T2.
 //   - comp3: The initial value for the component T3.
-func (b *Builder3[T1, T2, T3]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder3[T1, T2, T3]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		*(*T1)(unsafe.Pointer(uintptr(a.compPointers[b.id1]) + uintptr(startSize+k)*a.compSizes[b.id1])) = comp1
-		*(*T2)(unsafe.Pointer(uintptr(a.compPointers[b.id2]) + uintptr(startSize+k)*a.compSizes[b.id2])) = comp2
-		*(*T3)(unsafe.Pointer(uintptr(a.compPointers[b.id3]) + uintptr(startSize+k)*a.compSizes[b.id3])) = comp3
-		
-		w.entities.nextEntityVer++
-	}
-	w.mutationVersion.Add(1)
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.id1, startSize, n, comp1)
+	fillColumn(a, b.id2, startSize, n, comp2)
+	fillColumn(a, b.id3, startSize, n, comp3)
+	if n > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return n
+}
+
+// AsyncBuilder3 queues the creation of entities with the components
+// T1, T2, T3 from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder3[T1 any, T2 any, T3 any] struct {
+	builder *Builder3[T1, T2, T3]
+}
+
+// NewAsyncBuilder3 creates an AsyncBuilder3 for the components
+// T1, T2, T3. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder3`.
+func NewAsyncBuilder3[T1 any, T2 any, T3 any](w *World) *AsyncBuilder3[T1, T2, T3] {
+	return &AsyncBuilder3[T1, T2, T3]{builder: NewBuilder3[T1, T2, T3](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder3[T1, T2, T3]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+func (b *AsyncBuilder3[T1, T2, T3]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, comp1, comp2, comp3)
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -484,8 +602,8 @@ func (b *Builder3[T1, T2, T3]) Set(e Entity, v1 T1, v2 T2, v3 T3) {
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -511,6 +629,7 @@ func (b *Builder3[T1, T2, T3]) Set(e Entity, v1 T1, v2 T2, v3 T3) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -570,13 +689,11 @@ func NewBuilder4[T1 any, T2 any, T3 any, T4 any](w *World) *Builder4[T1, T2, T3,
 	t3 := reflect.TypeFor[T3]()
 	t4 := reflect.TypeFor[T4]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 {
 		panic("ecs: duplicate component types in Builder4")
@@ -611,11 +728,54 @@ func (b *Builder4[T1, T2, T3, T4]) New(w *World) *Builder4[T1, T2, T3, T4] {
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder4[T1, T2, T3, T4]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder4[T1, T2, T3, T4]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the 4 components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder4[T1, T2, T3, T4]) NewEntityWith(comp1 T1, comp2 T2, comp3 T3, comp4 T4) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	*(*T1)(unsafe.Add(a.compPointers[b.id1], idx*a.compSizes[b.id1])) = comp1
+	*(*T2)(unsafe.Add(a.compPointers[b.id2], idx*a.compSizes[b.id2])) = comp2
+	*(*T3)(unsafe.Add(a.compPointers[b.id3], idx*a.compSizes[b.id3])) = comp3
+	*(*T4)(unsafe.Add(a.compPointers[b.id4], idx*a.compSizes[b.id4])) = comp4
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the 4 components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -623,32 +783,23 @@ func (b *Builder4[T1, T2, T3, T4]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder4[T1, T2, T3, T4]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder4[T1, T2, T3, T4]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -660,37 +811,68 @@ func (b *Builder4[T1, T2, T3, T4]) NewEntities(count int) {
 //   - comp2: The initial value for the component T2.
 //   - comp3: The initial value for the component T3.
 //   - comp4: The initial value for the component T4.
-func (b *Builder4[T1, T2, T3, T4]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder4[T1, T2, T3, T4]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		*(*T1)(unsafe.Pointer(uintptr(a.compPointers[b.id1]) + uintptr(startSize+k)*a.compSizes[b.id1])) = comp1
-		*(*T2)(unsafe.Pointer(uintptr(a.compPointers[b.id2]) + uintptr(startSize+k)*a.compSizes[b.id2])) = comp2
-		*(*T3)(unsafe.Pointer(uintptr(a.compPointers[b.id3]) + uintptr(startSize+k)*a.compSizes[b.id3])) = comp3
-		*(*T4)(unsafe.Pointer(uintptr(a.compPointers[b.id4]) + uintptr(startSize+k)*a.compSizes[b.id4])) = comp4
-		
-		w.entities.nextEntityVer++
-	}
-	w.mutationVersion.Add(1)
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.id1, startSize, n, comp1)
+	fillColumn(a, b.id2, startSize, n, comp2)
+	fillColumn(a, b.id3, startSize, n, comp3)
+	fillColumn(a, b.id4, startSize, n, comp4)
+	if n > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return n
+}
+
+// AsyncBuilder4 queues the creation of entities with the components
+// T1, T2, T3, T4 from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder4[T1 any, T2 any, T3 any, T4 any] struct {
+	builder *Builder4[T1, T2, T3, T4]
+}
+
+// NewAsyncBuilder4 creates an AsyncBuilder4 for the components
+// T1, T2, T3, T4. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder4`.
+func NewAsyncBuilder4[T1 any, T2 any, T3 any, T4 any](w *World) *AsyncBuilder4[T1, T2, T3, T4] {
+	return &AsyncBuilder4[T1, T2, T3, T4]{builder: NewBuilder4[T1, T2, T3, T4](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder4[T1, T2, T3, T4]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+func (b *AsyncBuilder4[T1, T2, T3, T4]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, comp1, comp2, comp3, comp4)
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -780,8 +962,8 @@ func (b *Builder4[T1, T2, T3, T4]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4) {
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -811,6 +993,7 @@ func (b *Builder4[T1, T2, T3, T4]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -874,14 +1057,12 @@ func NewBuilder5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *Builder5[T1,
 	t4 := reflect.TypeFor[T4]()
 	t5 := reflect.TypeFor[T5]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 {
 		panic("ecs: duplicate component types in Builder5")
@@ -918,11 +1099,56 @@ func (b *Builder5[T1, T2, T3, T4, T5]) New(w *World) *Builder5[T1, T2, T3, T4, T
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder5[T1, T2, T3, T4, T5]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder5[T1, T2, T3, T4, T5]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the 5 components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+//   - comp5: The initial value for the component T5.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder5[T1, T2, T3, T4, T5]) NewEntityWith(comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	*(*T1)(unsafe.Add(a.compPointers[b.id1], idx*a.compSizes[b.id1])) = comp1
+	*(*T2)(unsafe.Add(a.compPointers[b.id2], idx*a.compSizes[b.id2])) = comp2
+	*(*T3)(unsafe.Add(a.compPointers[b.id3], idx*a.compSizes[b.id3])) = comp3
+	*(*T4)(unsafe.Add(a.compPointers[b.id4], idx*a.compSizes[b.id4])) = comp4
+	*(*T5)(unsafe.Add(a.compPointers[b.id5], idx*a.compSizes[b.id5])) = comp5
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the 5 components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -930,32 +1156,23 @@ func (b *Builder5[T1, T2, T3, T4, T5]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder5[T1, T2, T3, T4, T5]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder5[T1, T2, T3, T4, T5]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -968,38 +1185,70 @@ func (b *Builder5[T1, T2, T3, T4, T5]) NewEntities(count int) {
 //   - comp3: The initial value for the component T3.
 //   - comp4: The initial value for the component T4.
 //   - comp5: The initial value for the component T5.
-func (b *Builder5[T1, T2, T3, T4, T5]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder5[T1, T2, T3, T4, T5]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		*(*T1)(unsafe.Pointer(uintptr(a.compPointers[b.id1]) + uintptr(startSize+k)*a.compSizes[b.id1])) = comp1
-		*(*T2)(unsafe.Pointer(uintptr(a.compPointers[b.id2]) + uintptr(startSize+k)*a.compSizes[b.id2])) = comp2
-		*(*T3)(unsafe.Pointer(uintptr(a.compPointers[b.id3]) + uintptr(startSize+k)*a.compSizes[b.id3])) = comp3
-		*(*T4)(unsafe.Pointer(uintptr(a.compPointers[b.id4]) + uintptr(startSize+k)*a.compSizes[b.id4])) = comp4
-		*(*T5)(unsafe.Pointer(uintptr(a.compPointers[b.id5]) + uintptr(startSize+k)*a.compSizes[b.id5])) = comp5
-		
-		w.entities.nextEntityVer++
-	}
-	w.mutationVersion.Add(1)
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.id1, startSize, n, comp1)
+	fillColumn(a, b.id2, startSize, n, comp2)
+	fillColumn(a, b.id3, startSize, n, comp3)
+	fillColumn(a, b.id4, startSize, n, comp4)
+	fillColumn(a, b.id5, startSize, n, comp5)
+	if n > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return n
+}
+
+// AsyncBuilder5 queues the creation of entities with the components
+// T1, T2, T3, T4, T5 from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
+	builder *Builder5[T1, T2, T3, T4, T5]
+}
+
+// NewAsyncBuilder5 creates an AsyncBuilder5 for the components
+// T1, T2, T3, T4, T5. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder5`.
+func NewAsyncBuilder5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *AsyncBuilder5[T1, T2, T3, T4, T5] {
+	return &AsyncBuilder5[T1, T2, T3, T4, T5]{builder: NewBuilder5[T1, T2, T3, T4, T5](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder5[T1, T2, T3, T4, T5]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+//   - comp5: The initial value for the component T5.
+func (b *AsyncBuilder5[T1, T2, T3, T4, T5]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, comp1, comp2, comp3, comp4, comp5)
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -1098,8 +1347,8 @@ func (b *Builder5[T1, T2, T3, T4, T5]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4,
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -1133,6 +1382,7 @@ func (b *Builder5[T1, T2, T3, T4, T5]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4,
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -1200,15 +1450,13 @@ func NewBuilder6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *Buil
 	t5 := reflect.TypeFor[T5]()
 	t6 := reflect.TypeFor[T6]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
-	id6 := w.getCompTypeIDNoLock(t6)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
+	id6 := w.getCompTypeID(t6)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 || id6 == id1 || id6 == id2 || id6 == id3 || id6 == id4 || id6 == id5 {
 		panic("ecs: duplicate component types in Builder6")
@@ -1247,11 +1495,58 @@ func (b *Builder6[T1, T2, T3, T4, T5, T6]) New(w *World) *Builder6[T1, T2, T3, T
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder6[T1, T2, T3, T4, T5, T6]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the 6 components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+//   - comp5: The initial value for the component T5.
+//   - comp6: The initial value for the component T6.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntityWith(comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5, comp6 T6) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	*(*T1)(unsafe.Add(a.compPointers[b.id1], idx*a.compSizes[b.id1])) = comp1
+	*(*T2)(unsafe.Add(a.compPointers[b.id2], idx*a.compSizes[b.id2])) = comp2
+	*(*T3)(unsafe.Add(a.compPointers[b.id3], idx*a.compSizes[b.id3])) = comp3
+	*(*T4)(unsafe.Add(a.compPointers[b.id4], idx*a.compSizes[b.id4])) = comp4
+	*(*T5)(unsafe.Add(a.compPointers[b.id5], idx*a.compSizes[b.id5])) = comp5
+	*(*T6)(unsafe.Add(a.compPointers[b.id6], idx*a.compSizes[b.id6])) = comp6
+	w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the 6 components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -1259,32 +1554,23 @@ func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -1298,39 +1584,72 @@ func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntities(count int) {
 //   - comp4: The initial value for the component T4.
 //   - comp5: The initial value for the component T5.
 //   - comp6: The initial value for the component T6.
-func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5, comp6 T6) {
+//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder6[T1, T2, T3, T4, T5, T6]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5, comp6 T6) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		*(*T1)(unsafe.Pointer(uintptr(a.compPointers[b.id1]) + uintptr(startSize+k)*a.compSizes[b.id1])) = comp1
-		*(*T2)(unsafe.Pointer(uintptr(a.compPointers[b.id2]) + uintptr(startSize+k)*a.compSizes[b.id2])) = comp2
-		*(*T3)(unsafe.Pointer(uintptr(a.compPointers[b.id3]) + uintptr(startSize+k)*a.compSizes[b.id3])) = comp3
-		*(*T4)(unsafe.Pointer(uintptr(a.compPointers[b.id4]) + uintptr(startSize+k)*a.compSizes[b.id4])) = comp4
-		*(*T5)(unsafe.Pointer(uintptr(a.compPointers[b.id5]) + uintptr(startSize+k)*a.compSizes[b.id5])) = comp5
-		*(*T6)(unsafe.Pointer(uintptr(a.compPointers[b.id6]) + uintptr(startSize+k)*a.compSizes[b.id6])) = comp6
-		
-		w.entities.nextEntityVer++
-	}
-	w.mutationVersion.Add(1)
+	startSize, n := w.createEntitiesNoLock(a, count)
+	fillColumn(a, b.id1, startSize, n, comp1)
+	fillColumn(a, b.id2, startSize, n, comp2)
+	fillColumn(a, b.id3, startSize, n, comp3)
+	fillColumn(a, b.id4, startSize, n, comp4)
+	fillColumn(a, b.id5, startSize, n, comp5)
+	fillColumn(a, b.id6, startSize, n, comp6)
+	if n > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return n
+}
+
+// AsyncBuilder6 queues the creation of entities with the components
+// T1, T2, T3, T4, T5, T6 from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
+	builder *Builder6[T1, T2, T3, T4, T5, T6]
+}
+
+// NewAsyncBuilder6 creates an AsyncBuilder6 for the components
+// T1, T2, T3, T4, T5, T6. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder6`.
+func NewAsyncBuilder6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *AsyncBuilder6[T1, T2, T3, T4, T5, T6] {
+	return &AsyncBuilder6[T1, T2, T3, T4, T5, T6]{builder: NewBuilder6[T1, T2, T3, T4, T5, T6](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder6[T1, T2, T3, T4, T5, T6]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+//   - comp1: The initial value for the component T1.
+//   - comp2: The initial value for the component T2.
+//   - comp3: The initial value for the component T3.
+//   - comp4: The initial value for the component T4.
+//   - comp5: The initial value for the component T5.
+//   - comp6: The initial value for the component T6.
+func (b *AsyncBuilder6[T1, T2, T3, T4, T5, T6]) NewEntitiesWithValueSet(count int, comp1 T1, comp2 T2, comp3 T3, comp4 T4, comp5 T5, comp6 T6) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, comp1, comp2, comp3, comp4, comp5, comp6)
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -1438,8 +1757,8 @@ func (b *Builder6[T1, T2, T3, T4, T5, T6]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -1477,6 +1796,7 @@ func (b *Builder6[T1, T2, T3, T4, T5, T6]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -0,0 +1,205 @@
+package teishoku
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// changeSet records, for one tracked component type, which entity IDs had the
+// component written since the last ClearChanged, and which gained it around
+// the frame boundaries set by BeginFrame. Each set holds one bit per entity ID
+// and grows with the world.
+type changeSet struct {
+	bits    []uint64 // written since the last ClearChanged or EndFrame
+	added   []uint64 // added during the previous frame, reported by Added
+	pending []uint64 // added during the current frame
+}
+
+// TrackChanges enables change detection for component `T` in the world. Once
+// enabled, every write through SetComponent, SetComponentN, the transactional
+// setters or AddComponentToAll marks the entity's `T` as changed, and systems
+// can flag in-place writes done through a filter's Get with MarkChanged.
+// Filters then expose Changed and NextChanged to visit only the entities whose
+// tracked components changed since the last World.ClearChanged.
+//
+// Tracking also records when `T` is added to an entity, be it at creation or
+// by a later SetComponent, which filters report through Added and NextAdded
+// (see World.BeginFrame).
+//
+// Tracking is opt-in per component type because every tracked write costs an
+// extra atomic bit update. Enabling it again for the same type is a no-op.
+//
+// Parameters:
+//   - w: The World in which to track changes.
+func TrackChanges[T any](w *World) {
+	id := w.getCompTypeID(reflect.TypeFor[T]())
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.changes[id] != nil {
+		return
+	}
+	n := (w.entities.capacity + 63) / 64
+	w.changes[id] = &changeSet{
+		bits:    make([]uint64, n),
+		added:   make([]uint64, n),
+		pending: make([]uint64, n),
+	}
+	w.trackedIDs = append(w.trackedIDs, id)
+}
+
+// MarkChanged flags component `T` of the entity as changed. It is meant for
+// code that modified the component in place through a pointer. It does nothing
+// if changes of `T` are not tracked or the entity is invalid.
+//
+// Parameters:
+//   - w: The World containing the entity.
+//   - e: The Entity whose component changed.
+func MarkChanged[T any](w *World, e Entity) {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.IsValidNoLock(e) {
+		w.markChanged(id, e.ID)
+	}
+}
+
+// growChangeSets resizes every tracked change set to the world's capacity. It
+// is called with the write lock held whenever the world expands, so change
+// sets always cover every valid entity ID.
+func (w *World) growChangeSets() {
+	n := (w.entities.capacity + 63) / 64
+	for _, id := range w.trackedIDs {
+		cs := w.changes[id]
+		cs.bits = growBits(cs.bits, n)
+		cs.added = growBits(cs.added, n)
+		cs.pending = growBits(cs.pending, n)
+	}
+}
+
+// growBits returns bits extended to n words.
+func growBits(bits []uint64, n int) []uint64 {
+	if len(bits) >= n {
+		return bits
+	}
+	grown := make([]uint64, n)
+	copy(grown, bits)
+	return grown
+}
+
+// ClearChanged resets the changed state of every tracked component, typically
+// at the end of a frame once all reactive systems have run.
+func (w *World) ClearChanged() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range w.trackedIDs {
+		clear(w.changes[id].bits)
+	}
+}
+
+// markChanged flags component id of the entity ID as changed when the
+// component is tracked, and counts the write when it is versioned. Bits are
+// set atomically so filters iterating from several goroutines may mark
+// entities concurrently; the caller must hold at least the world's read lock
+// or be iterating a filter.
+func (w *World) markChanged(id uint8, entityID uint32) {
+	if vs := w.versions[id]; vs != nil {
+		atomic.AddUint32(&vs.counts[entityID], 1)
+	}
+	cs := w.changes[id]
+	if cs == nil {
+		return
+	}
+	atomic.OrUint64(&cs.bits[entityID>>6], uint64(1)<<(entityID&63))
+}
+
+// isChanged reports whether component id of the entity ID is flagged as
+// changed.
+func (w *World) isChanged(id uint8, entityID uint32) bool {
+	cs := w.changes[id]
+	if cs == nil {
+		return false
+	}
+	word := int(entityID >> 6)
+	if word >= len(cs.bits) {
+		return false
+	}
+	return atomic.LoadUint64(&cs.bits[word])&(uint64(1)<<(entityID&63)) != 0
+}
+
+// forgetChanges clears the changed state of a released entity ID, so an entity
+// that later reuses the ID does not start out as changed.
+func (w *World) forgetChanges(entityID uint32) {
+	word := int(entityID >> 6)
+	for _, id := range w.trackedIDs {
+		cs := w.changes[id]
+		if word < len(cs.bits) {
+			mask := uint64(1) << (entityID & 63)
+			cs.bits[word] &^= mask
+			cs.added[word] &^= mask
+			cs.pending[word] &^= mask
+		}
+	}
+}
+
+// BeginFrame starts a new frame for the purpose of Added. Components added to
+// entities during the previous frame become visible through the filters'
+// Added and NextAdded until the next BeginFrame, while additions made from now
+// on are collected for the following frame. Calling BeginFrame once per frame
+// therefore lets an initialization system see every newly spawned entity
+// exactly once, whether it was spawned before or after the system ran.
+//
+// BeginFrame also discards events queued with Emit that no system polled
+// during the previous frame.
+func (w *World) BeginFrame() {
+	w.resetEvents()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range w.trackedIDs {
+		cs := w.changes[id]
+		cs.added, cs.pending = cs.pending, cs.added
+		clear(cs.pending)
+	}
+}
+
+// EndFrame ends the current frame. It resets the changed state of every
+// tracked component, like ClearChanged, so Changed reports the writes of one
+// frame at a time.
+func (w *World) EndFrame() {
+	w.ClearChanged()
+}
+
+// markAdded records that the entity ID gained component id when the
+// component is tracked. The caller must hold the world's write lock.
+func (w *World) markAdded(id uint8, entityID uint32) {
+	if cs := w.changes[id]; cs != nil {
+		cs.pending[entityID>>6] |= uint64(1) << (entityID & 63)
+	}
+}
+
+// markCreated records the tracked components of count entities created in
+// the archetype starting at index start as added. The caller must hold the
+// world's write lock.
+func (w *World) markCreated(a *archetype, start, count int) {
+	for _, id := range w.trackedIDs {
+		if !a.mask.has(id) {
+			continue
+		}
+		for _, e := range a.entityIDs[start : start+count] {
+			w.markAdded(id, e.ID)
+		}
+	}
+}
+
+// isAdded reports whether component id of the entity ID was added during the
+// previous frame.
+func (w *World) isAdded(id uint8, entityID uint32) bool {
+	cs := w.changes[id]
+	if cs == nil {
+		return false
+	}
+	word := int(entityID >> 6)
+	return word < len(cs.added) && cs.added[word]&(uint64(1)<<(entityID&63)) != 0
+}
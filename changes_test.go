@@ -0,0 +1,108 @@
+package teishoku
+
+import "testing"
+
+func TestTrackChanges(t *testing.T) {
+	w := NewWorld(2)
+	TrackChanges[Position](w)
+	var ents []Entity
+	for range 4 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{}, Velocity{})
+		ents = append(ents, e)
+	}
+	f := NewFilter2[Position, Velocity](w)
+	n := 0
+	for f.NextChanged() {
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 changed entities after creation, got %d", n)
+	}
+
+	w.ClearChanged()
+	f.Reset()
+	if f.NextChanged() {
+		t.Fatal("expected no changed entities after ClearChanged")
+	}
+
+	// Untracked components never report changes.
+	SetComponent(w, ents[0], Velocity{DX: 1})
+	// Writes through SetComponent and MarkChanged are tracked.
+	SetComponent(w, ents[1], Position{X: 1})
+	f1 := NewFilter[Position](w)
+	for f1.Next() {
+		if f1.Entity() == ents[2] {
+			f1.Get().X = 2
+			f1.MarkChanged()
+		}
+	}
+	MarkChanged[Position](w, ents[3])
+	var changed []Entity
+	f.Reset()
+	for f.NextChanged() {
+		changed = append(changed, f.Entity())
+	}
+	if len(changed) != 3 {
+		t.Fatalf("expected 3 changed entities, got %v", changed)
+	}
+	for _, e := range changed {
+		if e == ents[0] {
+			t.Error("untracked component write reported as changed")
+		}
+	}
+
+	// Recycled IDs start out unchanged.
+	w.RemoveEntity(ents[1])
+	e := w.CreateEntity()
+	if e.ID != ents[1].ID {
+		t.Fatal("expected the ID to be reused")
+	}
+	if w.isChanged(f.ids[0], e.ID) {
+		t.Error("recycled entity inherited the changed flag")
+	}
+}
+
+func TestFilterAdded(t *testing.T) {
+	w := NewWorld(4)
+	TrackChanges[Position](w)
+	f := NewFilter2[Position, Velocity](w)
+	countAdded := func() int {
+		f.Reset()
+		n := 0
+		for f.NextAdded() {
+			n++
+		}
+		return n
+	}
+
+	b := NewBuilder2[Position, Velocity](w)
+	b.NewEntities(3)
+	if n := countAdded(); n != 0 {
+		t.Errorf("additions must not be visible before the next frame, got %d", n)
+	}
+	w.BeginFrame()
+	if n := countAdded(); n != 3 {
+		t.Errorf("expected 3 added entities, got %d", n)
+	}
+	// An entity gaining the tracked component mid-frame shows up next frame.
+	e := w.CreateEntity()
+	SetComponent(w, e, Velocity{})
+	SetComponent(w, e, Position{})
+	w.EndFrame()
+	w.BeginFrame()
+	f.Reset()
+	var got []Entity
+	for f.NextAdded() {
+		got = append(got, f.Entity())
+	}
+	if len(got) != 1 || got[0] != e {
+		t.Errorf("expected only %v to be added, got %v", e, got)
+	}
+	// Updating an existing component is not an addition.
+	SetComponent(w, e, Position{X: 1})
+	w.BeginFrame()
+	if n := countAdded(); n != 0 {
+		t.Errorf("expected no additions, got %d", n)
+	}
+}
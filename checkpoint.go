@@ -0,0 +1,242 @@
+package teishoku
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Checkpoint is an in-memory copy of a World's entities and component data,
+// taken by World.Checkpoint and applied back by World.Restore. It is meant
+// for rollback networking, where the simulation state is saved every frame
+// and restored when a late input arrives: unlike serialization it copies raw
+// column memory, without reflection, and its buffers are reused by later
+// checkpoints taken into it (see CheckpointRing).
+//
+// The copy is shallow: a component holding a slice, map, pointer or string is
+// saved as that reference, not as a copy of the data it points to. Restore
+// brings back the reference, so replacing such a field is rolled back, but
+// data changed in place through it after the checkpoint (inv.Items[0] = 9) is
+// shared with the checkpoint and is not. Components meant to be rolled back
+// should hold plain values, or be replaced rather than edited in place.
+//
+// A checkpoint belongs to the World it was taken from. It captures the
+// entities, their components, their user data, owners and Link relations, and
+// the ID allocator; it does not capture resources, transient components or
+// change tracking.
+type Checkpoint struct {
+	world         *World
+	arches        []archetypeCheckpoint
+	metas         []entityMeta
+	freeIDs       []uint32
+	owned         map[uint32][]Entity
+	relations     map[uint8]relationIndex
+	nextEntityVer uint32
+}
+
+// archetypeCheckpoint holds the rows of one archetype.
+type archetypeCheckpoint struct {
+	size      int
+	entityIDs []Entity
+	columns   []checkpointColumn // parallel to the archetype's compOrder
+}
+
+// checkpointColumn is a typed buffer holding a copy of a component column, so
+// that pointers in the copied components stay visible to the garbage
+// collector.
+type checkpointColumn struct {
+	ptr unsafe.Pointer
+	cap int
+}
+
+// Checkpoint copies the world's entities and component data into a new
+// checkpoint. Components are copied shallowly; see Checkpoint for what that
+// means for slices, maps and pointers. See CheckpointRing to reuse the buffers of earlier checkpoints
+// instead of allocating new ones every frame.
+//
+// Returns:
+//   - The new checkpoint, to be passed to Restore.
+func (w *World) Checkpoint() *Checkpoint {
+	c := &Checkpoint{}
+	w.checkpointInto(c)
+	return c
+}
+
+// checkpointInto copies the world's state into c, reusing its buffers.
+func (w *World) checkpointInto(c *Checkpoint) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	w.checkOpen()
+	c.world = w
+	c.metas = append(c.metas[:0], w.entities.metas...)
+	c.freeIDs = append(c.freeIDs[:0], w.entities.freeIDs...)
+	c.nextEntityVer = w.entities.nextEntityVer
+	clear(c.owned)
+	for id, list := range w.owned {
+		if c.owned == nil {
+			c.owned = make(map[uint32][]Entity, len(w.owned))
+		}
+		c.owned[id] = append([]Entity(nil), list...)
+	}
+	copyRelations(&c.relations, w.relations)
+	arches := w.archetypes.archetypes
+	if cap(c.arches) < len(arches) {
+		c.arches = append(c.arches[:cap(c.arches)], make([]archetypeCheckpoint, len(arches)-cap(c.arches))...)
+	}
+	c.arches = c.arches[:len(arches)]
+	for i, a := range arches {
+		ac := &c.arches[i]
+		ac.size = a.size
+		if a.size == 0 {
+			continue
+		}
+		ac.entityIDs = append(ac.entityIDs[:0], a.entityIDs[:a.size]...)
+		if len(ac.columns) < len(a.compOrder) {
+			ac.columns = append(ac.columns, make([]checkpointColumn, len(a.compOrder)-len(ac.columns))...)
+		}
+		for j, cid := range a.compOrder {
+			size := a.compSizes[cid]
+			if size == 0 {
+				continue
+			}
+			col := &ac.columns[j]
+			if col.cap < a.size {
+				col.cap = max(a.size, 2*col.cap)
+				col.ptr = reflect.MakeSlice(reflect.SliceOf(a.compTypes[j]), col.cap, col.cap).UnsafePointer()
+			}
+			memCopy(col.ptr, a.compPointers[cid], uintptr(a.size)*size)
+		}
+	}
+}
+
+// Restore brings the world back to the state recorded in c: every entity
+// alive at the checkpoint is alive again under the same handle and with the
+// same component values, and every entity created since is gone. The ID
+// allocator is restored too, so replaying the same operations after a
+// restore creates the same handles. Archetypes created since the checkpoint
+// are kept, empty. Filters and views observe the restore as a structural
+// change.
+//
+// Parameters:
+//   - c: A checkpoint taken from this world.
+//
+// It panics if c was taken from another World.
+func (w *World) Restore(c *Checkpoint) {
+	if c.world != w {
+		panic("ecs: Restore called with a checkpoint of another World")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkOpen()
+	w.checkStructural()
+	for i, a := range w.archetypes.archetypes {
+		size := 0
+		if i < len(c.arches) {
+			size = c.arches[i].size
+		}
+		if a.size > size {
+			a.clearSlots(size, a.size-size)
+		}
+		if size > 0 {
+			ac := &c.arches[i]
+			w.ensureStorage(a)
+			copy(a.entityIDs, ac.entityIDs[:size])
+			for j, cid := range a.compOrder {
+				if a.compSizes[cid] > 0 {
+					memCopy(a.compPointers[cid], ac.columns[j].ptr, uintptr(size)*a.compSizes[cid])
+				}
+			}
+		}
+		a.size = size
+	}
+	metas := w.entities.metas
+	copy(metas, c.metas)
+	for i := len(c.metas); i < len(metas); i++ {
+		metas[i] = entityMeta{archetypeIndex: -1, index: -1}
+	}
+	// IDs past the checkpoint's capacity were added by a later expansion; put
+	// them where that expansion did, so they are handed out in the same order.
+	free := w.entities.freeIDs[:0]
+	if w.entities.deterministic {
+		free = append(free, c.freeIDs...)
+		for id := len(c.metas); id < len(metas); id++ {
+			free = append(free, uint32(id))
+		}
+	} else {
+		for id := len(metas) - 1; id >= len(c.metas); id-- {
+			free = append(free, uint32(id))
+		}
+		free = append(free, c.freeIDs...)
+	}
+	w.entities.freeIDs = free
+	w.entities.nextEntityVer = c.nextEntityVer
+	clear(w.owned)
+	for id, list := range c.owned {
+		if w.owned == nil {
+			w.owned = make(map[uint32][]Entity, len(c.owned))
+		}
+		w.owned[id] = append([]Entity(nil), list...)
+	}
+	copyRelations(&w.relations, c.relations)
+	w.mutationVersion.Add(1)
+}
+
+// CheckpointRing keeps the last N checkpoints of a World, for rollback over
+// several frames. Saving into the ring overwrites its oldest checkpoint and
+// reuses that checkpoint's buffers, so a ring that has warmed up saves
+// without allocating as long as the world does not grow.
+type CheckpointRing struct {
+	world *World
+	slots []Checkpoint
+	next  int // slot the next Save writes to
+	count int // number of checkpoints held
+}
+
+// NewCheckpointRing creates a ring holding up to n checkpoints of w.
+//
+// Parameters:
+//   - w: The World to checkpoint.
+//   - n: The number of checkpoints to keep. It must be at least 1.
+//
+// Returns:
+//   - The new, empty ring.
+func NewCheckpointRing(w *World, n int) *CheckpointRing {
+	if n < 1 {
+		panic("ecs: NewCheckpointRing needs at least one slot")
+	}
+	return &CheckpointRing{world: w, slots: make([]Checkpoint, n)}
+}
+
+// Save checkpoints the world into the ring, evicting the oldest checkpoint if
+// the ring is full.
+func (r *CheckpointRing) Save() {
+	r.world.checkpointInto(&r.slots[r.next])
+	r.next = (r.next + 1) % len(r.slots)
+	r.count = min(r.count+1, len(r.slots))
+}
+
+// Restore restores the world to the checkpoint saved back saves ago: 0 is the
+// most recent one. The checkpoints newer than the restored one are dropped,
+// so the next Save follows it.
+//
+// Parameters:
+//   - back: The age of the checkpoint to restore.
+//
+// Returns:
+//   - false if the ring holds no checkpoint that old, in which case the world
+//     is unchanged.
+func (r *CheckpointRing) Restore(back int) bool {
+	if back < 0 || back >= r.count {
+		return false
+	}
+	n := len(r.slots)
+	i := (r.next - 1 - back + 2*n) % n
+	r.world.Restore(&r.slots[i])
+	r.next = (i + 1) % n
+	r.count -= back
+	return true
+}
+
+// Len returns the number of checkpoints held by the ring.
+func (r *CheckpointRing) Len() int {
+	return r.count
+}
@@ -0,0 +1,112 @@
+package teishoku
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	w := NewWorld(4)
+	var ents []Entity
+	for i := range 3 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Inventory{Items: []int{7}})
+		ents = append(ents, e)
+	}
+	w.SetUserData(ents[0], 42)
+	w.SetOwner(ents[1], ents[0])
+	c := w.Checkpoint()
+
+	// Diverge: mutate, remove, grow the world and create a new archetype.
+	GetComponent[Position](w, ents[1]).X = 100
+	GetComponent[Inventory](w, ents[0]).Items[0] = 9 // in place: shared
+	GetComponent[Inventory](w, ents[1]).Items = []int{1, 2}
+	w.SetOwner(ents[1], Entity{})
+	w.RemoveEntity(ents[2])
+	var later []Entity
+	for range 10 {
+		e := w.CreateEntity()
+		SetComponent(w, e, Health{HP: 1})
+		later = append(later, e)
+	}
+
+	w.Restore(c)
+	for i, e := range ents {
+		if p := GetComponent[Position](w, e); p == nil || p.X != float32(i) {
+			t.Errorf("entity %v: expected X %d after restore, got %v", e, i, p)
+		}
+		want := []int{7}
+		if i == 0 {
+			want = []int{9} // the copy is shallow: in-place edits are not rolled back
+		}
+		if inv := GetComponent[Inventory](w, e); inv == nil || !slices.Equal(inv.Items, want) {
+			t.Errorf("entity %v: expected items %v after restore, got %v", e, want, inv)
+		}
+	}
+	if w.GetUserData(ents[0]) != 42 {
+		t.Error("user data not restored")
+	}
+	if owned := w.Owned(ents[0]); !slices.Equal(owned, ents[1:2]) {
+		t.Errorf("ownership not restored: %v", owned)
+	}
+	for _, e := range later {
+		if w.IsValid(e) {
+			t.Errorf("entity %v created after the checkpoint is still alive", e)
+		}
+	}
+	if n := NewFilter[Health](w).Count(); n != 0 {
+		t.Errorf("expected no Health entity after restore, got %d", n)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the same operations hands out the same handles.
+	w.SetOwner(ents[1], Entity{})
+	w.RemoveEntity(ents[2])
+	var replay []Entity
+	for range 10 {
+		replay = append(replay, w.CreateEntity())
+	}
+	if !slices.Equal(replay, later) {
+		t.Errorf("replayed handles differ:\n got %v\nwant %v", replay, later)
+	}
+}
+
+func TestCheckpointRing(t *testing.T) {
+	w := NewWorld(8)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{})
+	r := NewCheckpointRing(w, 3)
+	for frame := range 5 {
+		GetComponent[Position](w, e).X = float32(frame)
+		r.Save()
+	}
+	if r.Len() != 3 {
+		t.Fatalf("expected 3 checkpoints, got %d", r.Len())
+	}
+	if r.Restore(3) {
+		t.Error("restored a checkpoint older than the ring")
+	}
+	if !r.Restore(1) {
+		t.Fatal("expected to restore the checkpoint before the last one")
+	}
+	if x := GetComponent[Position](w, e).X; x != 3 {
+		t.Errorf("expected frame 3, got %v", x)
+	}
+	if r.Len() != 2 {
+		t.Errorf("expected the newer checkpoint to be dropped, got %d left", r.Len())
+	}
+	r.Save()
+	if !r.Restore(2) || GetComponent[Position](w, e).X != 2 {
+		t.Error("expected to reach frame 2 after a new save")
+	}
+
+	other := NewWorld(1)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic restoring a checkpoint of another world")
+		}
+	}()
+	other.Restore(w.Checkpoint())
+}
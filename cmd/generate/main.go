@@ -109,14 +109,24 @@ func main() {
 		maxN = n
 	}
 
-	// List of templates to process.
-	templates := []string{"builder_generated.go.tpl", "functions_generated.go.tpl", "filter_generated.go.tpl"}
+	// List of templates to process, with the packages each generated file imports.
+	defaultImports := []string{"reflect", "unsafe"}
+	templates := []struct {
+		file    string
+		imports []string
+	}{
+		{"builder_generated.go.tpl", defaultImports},
+		{"functions_generated.go.tpl", defaultImports},
+		{"filter_generated.go.tpl", []string{"io", "reflect", "unsafe"}},
+		{"view_generated.go.tpl", nil},
+	}
 	templateDir := "templates"
 	outputDir := "." // Write to the package root.
 
 	fmt.Printf("Generating code for N=2 to %d...\n", maxN)
 
-	for _, tplFile := range templates {
+	for _, tpl := range templates {
+		tplFile := tpl.file
 		tplPath := filepath.Join(templateDir, tplFile)
 		outPath := filepath.Join(outputDir, strings.TrimSuffix(tplFile, ".tpl"))
 
@@ -130,10 +140,16 @@ func main() {
 		// Write the standard "do not edit" header to the generated file.
 		//_, _ = outFile.WriteString("// Code generated by go generate; DO NOT EDIT.\n")
 		_, _ = outFile.WriteString("package teishoku\n\n")
-		_, _ = outFile.WriteString("import (\n\t\"reflect\"\n\t\"unsafe\"\n)\n\n")
+		if len(tpl.imports) > 0 {
+			_, _ = outFile.WriteString("import (\n")
+			for _, imp := range tpl.imports {
+				_, _ = outFile.WriteString("\t\"" + imp + "\"\n")
+			}
+			_, _ = outFile.WriteString(")\n\n")
+		}
 
 		// Parse the template file.
-		tpl, err := template.ParseFiles(tplPath)
+		t, err := template.ParseFiles(tplPath)
 		if err != nil {
 			panic(err)
 		}
@@ -142,7 +158,7 @@ func main() {
 		for i := 2; i <= maxN; i++ {
 			data := buildTemplateData(i)
 			var buf bytes.Buffer
-			err := tpl.Execute(&buf, data)
+			err := t.Execute(&buf, data)
 			if err != nil {
 				panic(fmt.Sprintf("Error executing template %s for N=%d: %v", tplFile, i, err))
 			}
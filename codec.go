@@ -0,0 +1,42 @@
+package teishoku
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// Codec writes matching entities to a stream, for debugging dumps and simple
+// save files produced by a filter's Encode. Implementations decide the format
+// (JSON, gob, a custom binary packer, ...).
+type Codec interface {
+	// Encode writes one entity. components holds a pointer to each of the
+	// filter's components, in the filter's type order; the pointers are only
+	// valid for the duration of the call.
+	Encode(w io.Writer, e Entity, components []any) error
+}
+
+// JSONCodec is a Codec writing one JSON object per line, mapping each
+// component's type name to its value:
+//
+//	{"id":3,"version":7,"components":{"main.Position":{"X":1,"Y":2}}}
+//
+// Components are marshaled with encoding/json, so only exported fields are
+// written.
+type JSONCodec struct{}
+
+// jsonEntity is the line written by JSONCodec.
+type jsonEntity struct {
+	ID         uint32         `json:"id"`
+	Version    uint32         `json:"version"`
+	Components map[string]any `json:"components"`
+}
+
+// Encode implements Codec.
+func (JSONCodec) Encode(w io.Writer, e Entity, components []any) error {
+	line := jsonEntity{ID: e.ID, Version: e.Version, Components: make(map[string]any, len(components))}
+	for _, c := range components {
+		line.Components[reflect.TypeOf(c).Elem().String()] = c
+	}
+	return json.NewEncoder(w).Encode(line)
+}
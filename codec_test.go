@@ -0,0 +1,69 @@
+package teishoku
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+type failingCodec struct{ calls int }
+
+func (c *failingCodec) Encode(w io.Writer, e Entity, components []any) error {
+	c.calls++
+	return errors.New("boom")
+}
+
+func TestFilterEncodeJSON(t *testing.T) {
+	w := NewWorld(8)
+	e1 := w.CreateEntity()
+	SetComponent2(w, e1, Position{X: 1, Y: 2}, Velocity{DX: 3, DY: 4})
+	e2 := w.CreateEntity()
+	SetComponent2(w, e2, Position{X: 5, Y: 6}, Velocity{DX: 7, DY: 8})
+	SetComponent(w, w.CreateEntity(), Position{}) // not matched
+
+	var buf bytes.Buffer
+	f := NewFilter2[Position, Velocity](w)
+	if err := f.Encode(&buf, JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	type line struct {
+		ID         uint32
+		Version    uint32
+		Components struct {
+			Position Position `json:"teishoku.Position"`
+			Velocity Velocity `json:"teishoku.Velocity"`
+		}
+	}
+	got := map[uint32]line{}
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var l line
+		if err := json.Unmarshal(sc.Bytes(), &l); err != nil {
+			t.Fatal(err)
+		}
+		got[l.ID] = l
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(got))
+	}
+	l := got[e2.ID]
+	if l.Version != e2.Version || l.Components.Position != (Position{X: 5, Y: 6}) || l.Components.Velocity != (Velocity{DX: 7, DY: 8}) {
+		t.Errorf("unexpected line for e2: %+v", l)
+	}
+
+	buf.Reset()
+	if err := NewFilter[Position](w).Encode(&buf, JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 3 {
+		t.Errorf("expected 3 lines, got %d", n)
+	}
+
+	c := &failingCodec{}
+	if err := f.Encode(io.Discard, c); err == nil || c.calls != 1 {
+		t.Errorf("expected Encode to stop at the first error, got %v after %d calls", err, c.calls)
+	}
+}
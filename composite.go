@@ -0,0 +1,163 @@
+package teishoku
+
+// CompositeFilter iterates over the entities matched by a combination of other
+// filters, created with Intersect or Union. It yields entities only; use the
+// generic accessors (GetComponent, ...) or the source filters to reach their
+// components.
+//
+// A composite keeps its own archetype list and staleness tracking, so it stays
+// valid as the world changes and does not depend on the source filters being
+// reset or iterated.
+type CompositeFilter struct {
+	curEntityIDs []Entity
+	queryCache
+	curMatchIdx int // index into matchingArches
+	curIdx      int // index into the current archetype's entity array
+	curOffset   int // number of entities in the archetypes already iterated
+	curArchSize int
+}
+
+// Intersect returns a filter over the entities matched by both the receiver
+// and other. For two plain filters this is the union of their component
+// requirements; exact filters keep their exactness, so intersecting two exact
+// filters with different masks matches nothing.
+//
+// Parameters:
+//   - other: The filter to combine with. It must be bound to the same World.
+//
+// Returns:
+//   - A new CompositeFilter over the intersection.
+func (c *queryCache) Intersect(other AnyFilter) *CompositeFilter {
+	return newCompositeFilter(c, other.cache(), false)
+}
+
+// Union returns a filter over the entities matched by the receiver, other or
+// both. Each matching archetype is visited once, so an entity matched by both
+// sources is yielded a single time.
+//
+// Parameters:
+//   - other: The filter to combine with. It must be bound to the same World.
+//
+// Returns:
+//   - A new CompositeFilter over the union.
+func (c *queryCache) Union(other AnyFilter) *CompositeFilter {
+	return newCompositeFilter(c, other.cache(), true)
+}
+
+func newCompositeFilter(a, b *queryCache, union bool) *CompositeFilter {
+	a.checkWorld()
+	b.checkWorld()
+	if a.world != b.world {
+		panic("ecs: cannot combine filters bound to different Worlds")
+	}
+	w := a.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	f := &CompositeFilter{
+		queryCache: newQueryCache(w, bitmask256{}),
+		curIdx:     -1,
+	}
+	f.parts = []*queryCache{a.matcher(), b.matcher()}
+	f.union = union
+	if !union {
+		for i := range f.mask {
+			f.mask[i] = a.mask[i] | b.mask[i]
+		}
+	}
+	f.updateMatching()
+	f.updateCachedEntities()
+	f.doReset()
+	return f
+}
+
+// matcher returns a copy of the cache's matching criteria, without its cached
+// archetypes and entities, for use as a composite part.
+func (c *queryCache) matcher() *queryCache {
+	return &queryCache{mask: c.mask, exact: c.exact, parts: c.parts, union: c.union}
+}
+
+// Reset rewinds the filter's iterator to the beginning. It must be called
+// before re-iterating over a filter (e.g., in a loop). The filter will also
+// automatically detect if new archetypes have been created since the last
+// iteration and update its internal list accordingly.
+func (f *CompositeFilter) Reset() {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+}
+
+func (f *CompositeFilter) doReset() {
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	f.sortBySize()
+	f.curMatchIdx = 0
+	f.curIdx = -1
+	f.curOffset = 0
+	if len(f.matchingArches) > 0 {
+		a := f.matchingArches[0]
+		f.curEntityIDs = a.entityIDs
+		f.curArchSize = a.size
+	} else {
+		f.curArchSize = 0
+	}
+}
+
+// Next advances the filter to the next matching entity. It returns true if an
+// entity was found, and false if the iteration is complete.
+//
+// Returns:
+//   - true if another matching entity was found, false otherwise.
+func (f *CompositeFilter) Next() bool {
+	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
+	if f.curIdx < f.curArchSize {
+		return true
+	}
+	return f.nextArchetype()
+}
+
+func (f *CompositeFilter) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
+	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
+	if f.curMatchIdx >= len(f.matchingArches) {
+		return false
+	}
+	a := f.matchingArches[f.curMatchIdx]
+	f.curEntityIDs = a.entityIDs
+	f.curArchSize = a.size
+	f.curIdx = 0
+	return true
+}
+
+// Index returns the position of the current entity in the whole iteration,
+// in the same order as Entities. This should only be called after `Next()`
+// has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *CompositeFilter) Index() int {
+	return f.curOffset + f.curIdx
+}
+
+// Entity returns the current `Entity` in the iteration. This should only be
+// called after `Next()` has returned true.
+//
+// Returns:
+//   - The current Entity.
+func (f *CompositeFilter) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return f.curEntityIDs[f.curIdx]
+}
@@ -0,0 +1,75 @@
+package teishoku
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompositeFilter(t *testing.T) {
+	w := NewWorld(8)
+	p := w.CreateEntity()
+	SetComponent(w, p, Position{})
+	v := w.CreateEntity()
+	SetComponent(w, v, Velocity{})
+	pv := w.CreateEntity()
+	SetComponent2(w, pv, Position{}, Velocity{})
+
+	fp := NewFilter[Position](w)
+	fv := NewFilter[Velocity](w)
+	collect := func(f *CompositeFilter) []Entity {
+		var out []Entity
+		f.Reset()
+		for f.Next() {
+			out = append(out, f.Entity())
+		}
+		return out
+	}
+
+	inter := fp.Intersect(fv)
+	if got := collect(inter); !slices.Equal(got, []Entity{pv}) {
+		t.Fatalf("Intersect: expected [%v], got %v", pv, got)
+	}
+	union := fp.Union(fv)
+	got := collect(union)
+	if len(got) != 3 || !slices.Contains(got, p) || !slices.Contains(got, v) || !slices.Contains(got, pv) {
+		t.Fatalf("Union: expected each entity once, got %v", got)
+	}
+	if union.Count() != 3 {
+		t.Fatalf("Union Count: expected 3, got %d", union.Count())
+	}
+
+	// Staleness: new archetypes and entities show up after Reset.
+	h := w.CreateEntity()
+	SetComponent3(w, h, Position{}, Velocity{}, Health{})
+	if !inter.IsStale() {
+		t.Fatal("expected the intersection to be stale after a change")
+	}
+	if got := collect(inter); len(got) != 2 || !slices.Contains(got, h) {
+		t.Fatalf("Intersect after change: got %v", got)
+	}
+	if len(union.Entities()) != 4 {
+		t.Fatalf("Union Entities after change: expected 4, got %d", len(union.Entities()))
+	}
+
+	// Exactness of the sources is preserved.
+	exact := NewFilterExact[Position](w)
+	if got := collect(exact.Union(NewFilterExact[Velocity](w))); len(got) != 2 || slices.Contains(got, pv) {
+		t.Fatalf("Union of exact filters: got %v", got)
+	}
+	if got := collect(exact.Intersect(fv)); len(got) != 0 {
+		t.Fatalf("Intersect of exact and superset filters: got %v", got)
+	}
+	// Composites combine further.
+	if got := collect(inter.Intersect(NewFilter[Health](w))); !slices.Equal(got, []Entity{h}) {
+		t.Fatalf("nested Intersect: got %v", got)
+	}
+}
+
+func TestCompositeFilterDifferentWorlds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic combining filters from different worlds")
+		}
+	}()
+	NewFilter[Position](NewWorld(1)).Union(NewFilter[Position](NewWorld(1)))
+}
@@ -0,0 +1,8 @@
+//go:build ecsdebug
+
+package teishoku
+
+// debugChecks enables development-time assertions. It is true when building
+// with the ecsdebug build tag (go build -tags ecsdebug) and false otherwise, in
+// which case the compiler removes the checks entirely.
+const debugChecks = true
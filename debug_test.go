@@ -0,0 +1,137 @@
+//go:build ecsdebug
+
+package teishoku
+
+import "testing"
+
+func expectCursorPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected a panic without a current entity", name)
+		}
+	}()
+	fn()
+}
+
+func TestDebugCursorChecks(t *testing.T) {
+	w := NewWorld(2)
+	SetComponent2(w, w.CreateEntity(), Position{}, Velocity{})
+	f := NewFilter[Position](w)
+	f2 := NewFilter2[Position, Velocity](w)
+	expectCursorPanic(t, "Filter.Get", func() { f.Get() })
+	expectCursorPanic(t, "Filter.Entity", func() { f.Entity() })
+	expectCursorPanic(t, "Filter2.GetValues", func() { f2.GetValues() })
+	q := f2.Query()
+	expectCursorPanic(t, "Query2.Get", func() { q.Get() })
+
+	for f.Next() {
+		f.Get()
+	}
+	expectCursorPanic(t, "Filter.Get after the last Next", func() { f.Get() })
+}
+
+func expectPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected a panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestDebugReentrancyChecks(t *testing.T) {
+	w := NewWorld(8)
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{}, Velocity{})
+	f := NewFilter[Position](w)
+	f2 := NewFilter2[Position, Velocity](w)
+
+	expectPanic(t, "CreateEntity in Apply", func() {
+		f.Apply(func(*Position) { w.CreateEntity() })
+	})
+	expectPanic(t, "RemoveComponent in Filter2.Apply", func() {
+		f2.Apply(func(*Position, *Velocity) { RemoveComponent[Velocity](w, e) })
+	})
+	expectPanic(t, "RemoveEntity in EachMut", func() {
+		f.EachMut(func(e Entity, _ *Position) Action {
+			w.RemoveEntity(e)
+			return ActionKeep
+		})
+	})
+	expectPanic(t, "SetComponent in EachArchetype", func() {
+		f.EachArchetype(func(ArchetypeView) { SetComponent(w, e, Health{}) })
+	})
+	expectPanic(t, "World call inside Mutate", func() {
+		w.Mutate(func(tx *Txn) { GetComponent[Position](w, e) })
+	})
+	if !w.IsValid(e) || GetComponent[Velocity](w, e) == nil {
+		t.Fatal("the rejected changes must not have been applied")
+	}
+
+	// Reads, in-place writes and the deferred removal of EachMut are allowed.
+	f.Apply(func(p *Position) {
+		GetComponent[Velocity](w, e).DX = 1
+		SetComponent(w, e, Velocity{DX: 2})
+		p.X = 3
+	})
+	f.EachMut(func(Entity, *Position) Action { return ActionRemove })
+	if w.IsValid(e) {
+		t.Fatal("expected EachMut to remove the entity after the loop")
+	}
+	// The guard is released after a callback panicked.
+	w.CreateEntity()
+	// Other goroutines are not affected by an iteration in progress.
+	SetComponent(w, w.CreateEntity(), Position{})
+	f.Apply(func(*Position) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			w.CreateEntity()
+		}()
+		<-done
+	})
+}
+
+func TestDebugStructuralChangeDuringIteration(t *testing.T) {
+	w := NewWorld(8)
+	for range 4 {
+		SetComponent2(w, w.CreateEntity(), Position{}, Velocity{})
+	}
+	f := NewFilter[Position](w)
+	f2 := NewFilter2[Position, Velocity](w)
+
+	// A mutation from another goroutine in the middle of the loop. The channel
+	// orders it between two Next calls, so the race detector stays quiet and
+	// only the guard can report it.
+	mutate := func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			SetComponent(w, w.CreateEntity(), Position{})
+		}()
+		<-done
+	}
+	f.Reset()
+	f.Next()
+	mutate()
+	expectPanic(t, "Filter.Next", func() { f.Next() })
+	f2.Reset()
+	f2.Next()
+	mutate()
+	expectPanic(t, "Filter2.Next", func() { f2.Next() })
+
+	// Changes before the first Next and non-structural writes are allowed.
+	f2.Reset()
+	mutate()
+	n := 0
+	for f2.Next() {
+		p, v := f2.Get()
+		p.X += v.DX
+		n++
+	}
+	if n != 4 {
+		t.Errorf("expected 4 entities, got %d", n)
+	}
+}
@@ -3,6 +3,7 @@ package teishoku
 import (
 	"fmt"
 	"testing"
+	"unsafe"
 )
 
 // World Creation Benchmarks
@@ -152,6 +153,29 @@ func BenchmarkBuilderNewEntitiesWithValueSet(b *testing.B) {
 	}
 }
 
+// BenchmarkColumnFill compares the doubling column fill used by
+// NewEntitiesWithValueSet with a per-entity store loop over 100K slots.
+func BenchmarkColumnFill(b *testing.B) {
+	const size = 100000
+	w := NewWorld(size)
+	builder := NewBuilder[Position](w)
+	builder.NewEntities(size)
+	a, id := builder.arch, builder.compID
+	val := Position{1, 2}
+	b.Run("Loop", func(b *testing.B) {
+		for b.Loop() {
+			for k := range size {
+				*(*Position)(unsafe.Add(a.compPointers[id], uintptr(k)*a.compSizes[id])) = val
+			}
+		}
+	})
+	b.Run("Fill", func(b *testing.B) {
+		for b.Loop() {
+			fillColumn(a, id, 0, size, val)
+		}
+	})
+}
+
 func BenchmarkBuilderNewEntitiesWithValueSet2(b *testing.B) {
 	sizes := []int{1000, 10000, 100000, 1000000}
 	for _, size := range sizes {
@@ -315,6 +339,28 @@ func BenchmarkFunctionsGetComponent2(b *testing.B) {
 	}
 }
 
+func BenchmarkFunctionsGetComponent2SingleThreaded(b *testing.B) {
+	sizes := []int{1000, 10000, 100000, 1000000}
+	for _, size := range sizes {
+		name := fmt.Sprintf("%dK", size/1000)
+		if size == 1000000 {
+			name = "1M"
+		}
+		b.Run(name, func(b *testing.B) {
+			w := NewWorld(size, WithSingleThreaded())
+			builder := NewBuilder2[Position, Velocity](w)
+			builder.NewEntities(size)
+			ents := builder.arch.entityIDs[:size]
+			for b.Loop() {
+				for j := range size {
+					GetComponent2[Position, Velocity](w, ents[j%size])
+				}
+			}
+			b.ReportAllocs()
+		})
+	}
+}
+
 func BenchmarkFunctionsSetComponentExisting(b *testing.B) {
 	sizes := []int{1000, 10000, 100000, 1000000}
 	for _, size := range sizes {
@@ -556,6 +602,57 @@ func BenchmarkFilter2Iterate(b *testing.B) {
 	}
 }
 
+// BenchmarkFilter2ResetLoop mirrors the profile/query loop, comparing Reset
+// with ResetUnlocked over a small archetype iterated many times.
+func BenchmarkFilter2ResetLoop(b *testing.B) {
+	for _, unlocked := range []bool{false, true} {
+		name := "Reset"
+		if unlocked {
+			name = "ResetUnlocked"
+		}
+		b.Run(name, func(b *testing.B) {
+			w := NewWorld(1000)
+			builder2 := NewBuilder2[Position, Velocity](w)
+			builder2.NewEntities(1000)
+			filter2 := NewFilter2[Position, Velocity](w)
+			for b.Loop() {
+				if unlocked {
+					filter2.ResetUnlocked()
+				} else {
+					filter2.Reset()
+				}
+				for filter2.Next() {
+					p, v := filter2.Get()
+					p.X += v.DX
+				}
+			}
+			b.ReportAllocs()
+		})
+	}
+}
+
+func BenchmarkFilter2Apply(b *testing.B) {
+	sizes := []int{1000, 10000, 100000, 1000000}
+	for _, size := range sizes {
+		name := fmt.Sprintf("%dK", size/1000)
+		if size == 1000000 {
+			name = "1M"
+		}
+		b.Run(name, func(b *testing.B) {
+			w := NewWorld(size)
+			builder2 := NewBuilder2[Position, Velocity](w)
+			builder2.NewEntities(size)
+			filter2 := NewFilter2[Position, Velocity](w)
+			for b.Loop() {
+				filter2.Apply(func(p *Position, v *Velocity) {
+					p.X += v.DX
+				})
+			}
+			b.ReportAllocs()
+		})
+	}
+}
+
 func BenchmarkFilter3Iterate(b *testing.B) {
 	sizes := []int{1000, 10000, 100000, 1000000}
 	for _, size := range sizes {
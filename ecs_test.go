@@ -1,8 +1,14 @@
 package teishoku
 
 import (
+	"math"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 	"unsafe"
 )
 
@@ -323,3 +329,2452 @@ func TestFilterDataIntegrity(t *testing.T) {
 		t.Errorf("Expected %d entities after removal, but got %d", count-1, entityCount)
 	}
 }
+
+func TestFilterNewBindsToGivenWorld(t *testing.T) {
+	w1 := NewWorld(TestCap)
+	w2 := NewWorld(TestCap)
+	NewBuilder2[Position, Velocity](w1).NewEntities(3)
+	NewBuilder2[Position, Velocity](w2).NewEntities(7)
+
+	f1 := NewFilter2[Position, Velocity](w1)
+	f2 := f1.New(w2)
+	if f1.World() != w1 || f2.World() != w2 {
+		t.Fatal("expected each filter to be bound to the world it was created for")
+	}
+	count := func(f *Filter2[Position, Velocity]) int {
+		n := 0
+		f.Reset()
+		for f.Next() {
+			n++
+		}
+		return n
+	}
+	if n := count(f1); n != 3 {
+		t.Errorf("expected original filter to still see 3 entities, got %d", n)
+	}
+	if n := count(f2); n != 7 {
+		t.Errorf("expected new filter to see 7 entities, got %d", n)
+	}
+}
+
+func TestFilterZeroValuePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when resetting an unbound filter")
+		}
+	}()
+	var f Filter2[Position, Velocity]
+	f.Reset()
+}
+
+func TestGetOrAdd(t *testing.T) {
+	w := NewWorld(TestCap)
+	ent := NewBuilder[Position](w).NewEntity()
+
+	vel := GetOrAdd(w, ent, Velocity{DX: 1, DY: 2})
+	if vel == nil || *vel != (Velocity{DX: 1, DY: 2}) {
+		t.Fatalf("expected added velocity {1 2}, got %v", vel)
+	}
+	vel.DX = 5
+	again := GetOrAdd(w, ent, Velocity{DX: 9, DY: 9})
+	if again.DX != 5 {
+		t.Errorf("expected existing velocity to be returned, got %+v", *again)
+	}
+	if GetComponent[Position](w, ent) == nil {
+		t.Error("expected position to survive the archetype move")
+	}
+
+	w.RemoveEntity(ent)
+	if GetOrAdd(w, ent, Velocity{}) != nil {
+		t.Error("expected nil for an invalid entity")
+	}
+}
+
+type Scale struct {
+	X, Y, Z float32
+}
+
+func (s *Scale) DefaultComponent() {
+	*s = Scale{X: 1, Y: 1, Z: 1}
+}
+
+func TestLazyColumns(t *testing.T) {
+	w := NewWorld(2, WithLazyColumns())
+	Prewarm2[Position, Velocity](w)
+	a := w.archetypes.archetypes[w.archetypes.maskToArcIndex[w.MaskOf(reflect.TypeFor[Position](), reflect.TypeFor[Velocity]())]]
+	if a.allocated || a.compPointers[a.compOrder[0]] != nil {
+		t.Fatal("expected a prewarmed archetype to have no storage yet")
+	}
+	f := NewFilter2[Position, Velocity](w)
+	if f.Next() {
+		t.Fatal("expected no entity in an unallocated archetype")
+	}
+
+	// Grow the world while the archetype is still unallocated.
+	NewBuilder[Health](w).NewEntities(8)
+	if a.allocated {
+		t.Fatal("expected growth to skip unallocated archetypes")
+	}
+
+	// Read before any write: the components are zero values.
+	e := NewBuilder2[Position, Velocity](w).NewEntity()
+	if p, v := GetComponent2[Position, Velocity](w, e); p == nil || *p != (Position{}) || *v != (Velocity{}) {
+		t.Fatalf("expected zero components on first read, got %v %v", p, v)
+	}
+	if !a.allocated || len(a.entityIDs) != w.entities.capacity {
+		t.Fatal("expected storage at the world's capacity once the archetype holds an entity")
+	}
+
+	// Moving into a never-used archetype allocates it as well.
+	SetComponent(w, e, Health{HP: 4})
+	if p, h := GetComponent[Position](w, e), GetComponent[Health](w, e); p == nil || h.HP != 4 {
+		t.Fatal("expected the entity to move into a lazily allocated archetype")
+	}
+}
+
+func TestDefaulterComponents(t *testing.T) {
+	w := NewWorld(4)
+	one := Scale{X: 1, Y: 1, Z: 1}
+
+	ent := NewBuilder[Scale](w).NewEntity()
+	if s := GetComponent[Scale](w, ent); *s != one {
+		t.Errorf("expected NewEntity to apply defaults, got %+v", *s)
+	}
+
+	// Larger than the initial capacity so the batch path also expands.
+	b2 := NewBuilder2[Position, Scale](w)
+	b2.NewEntities(10)
+	f := NewFilter2[Position, Scale](w)
+	n := 0
+	for f.Next() {
+		if _, s := f.Get(); *s != one {
+			t.Errorf("expected NewEntities to apply defaults, got %+v", *s)
+		}
+		n++
+	}
+	if n != 10 {
+		t.Errorf("expected 10 entities, got %d", n)
+	}
+
+	explicit := Scale{X: 2, Y: 3, Z: 4}
+	b := NewBuilder[Scale](w)
+	b.NewEntitiesWithValueSet(3, explicit)
+	count := 0
+	for q := NewFilter[Scale](w); q.Next(); {
+		if *q.Get() == explicit {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("expected explicit values to override defaults, got %d matches", count)
+	}
+}
+
+func TestNewEntitiesBeyondCapacity(t *testing.T) {
+	w := NewWorld(1)
+	b := NewBuilder[Position](w)
+	if n := b.NewEntities(1000); n != 1000 {
+		t.Fatalf("expected 1000 entities in one call, got %d", n)
+	}
+	if got := NewFilter[Position](w).Count(); got != 1000 {
+		t.Fatalf("expected 1000 live entities, got %d", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic when exceeding the entity ID space")
+		}
+	}()
+	b.NewEntities(maxEntityIDs)
+}
+
+type PathBuf struct {
+	Points []int
+}
+
+func (p *PathBuf) Reset() {
+	p.Points = p.Points[:0]
+}
+
+func TestRecyclerComponents(t *testing.T) {
+	w := NewWorld(4)
+	b := NewBuilder2[Position, PathBuf](w)
+
+	a := b.NewEntity()
+	keep := b.NewEntity()
+	GetComponent[PathBuf](w, a).Points = append(make([]int, 0, 64), 1, 2, 3)
+	GetComponent[PathBuf](w, keep).Points = []int{7}
+
+	w.RemoveEntity(a)
+	if got := GetComponent[PathBuf](w, keep).Points; len(got) != 1 || got[0] != 7 {
+		t.Fatalf("expected surviving entity to keep its buffer, got %v", got)
+	}
+	reused := b.NewEntity()
+	p := GetComponent[PathBuf](w, reused)
+	if len(p.Points) != 0 || cap(p.Points) != 64 {
+		t.Errorf("expected reset buffer with retained capacity, got len %d cap %d", len(p.Points), cap(p.Points))
+	}
+
+	// Bulk destruction keeps the buffers as well.
+	p.Points = append(p.Points, 4)
+	w.ClearEntities()
+	b.NewEntities(2)
+	var moved Entity
+	for f := NewFilter[PathBuf](w); f.Next(); {
+		if got := f.Get().Points; len(got) != 0 {
+			t.Errorf("expected reset buffer after ClearEntities, got %v", got)
+		}
+		moved = f.Entity()
+	}
+
+	// Moving an entity out of the archetype must not leave a shared buffer
+	// behind for the next entity created there.
+	GetComponent[PathBuf](w, moved).Points = append(GetComponent[PathBuf](w, moved).Points, 9)
+	SetComponent(w, moved, Velocity{})
+	b.NewEntity()
+	if got := GetComponent[PathBuf](w, moved).Points; len(got) != 1 || got[0] != 9 {
+		t.Errorf("expected moved entity's buffer to be untouched, got %v", got)
+	}
+}
+
+func TestBuilderNewEntityWith(t *testing.T) {
+	w := NewWorld(1)
+	e := NewBuilder2[Position, Velocity](w).NewEntityWith(Position{X: 1}, Velocity{DX: 2})
+	if p, v := GetComponent[Position](w, e), GetComponent[Velocity](w, e); p.X != 1 || v.DX != 2 {
+		t.Fatalf("expected initialized components, got %+v %+v", *p, *v)
+	}
+	single := NewBuilder[Health](w).NewEntityWith(Health{HP: 7})
+	if h := GetComponent[Health](w, single); h == nil || h.HP != 7 {
+		t.Fatal("expected Builder.NewEntityWith to initialize the component")
+	}
+
+	w.SetMaxEntities(2)
+	if e := NewBuilder[Health](w).NewEntityWith(Health{HP: 1}); e != (Entity{}) {
+		t.Errorf("expected the zero Entity past the entity limit, got %v", e)
+	}
+}
+
+func TestFilterRefreshAndOnStale(t *testing.T) {
+	w := NewWorld(TestCap)
+	b := NewBuilder2[Position, Velocity](w)
+	b.NewEntities(2)
+	f := NewFilter2[Position, Velocity](w)
+	calls := 0
+	f.OnStale(func() { calls++ })
+
+	if f.Refresh() {
+		t.Error("expected fresh filter not to be stale")
+	}
+	b.NewEntities(3)
+	if !f.IsStale() {
+		t.Error("expected filter to be stale after creating entities")
+	}
+	if !f.Refresh() {
+		t.Error("expected Refresh to report the change")
+	}
+	if f.IsStale() {
+		t.Error("expected filter to be up to date after Refresh")
+	}
+	if len(f.Entities()) != 5 {
+		t.Errorf("expected 5 entities, got %d", len(f.Entities()))
+	}
+	if calls != 1 {
+		t.Errorf("expected OnStale to fire once, got %d", calls)
+	}
+
+	b.NewEntity()
+	f.Reset()
+	if calls != 2 {
+		t.Errorf("expected Reset to fire OnStale, got %d calls", calls)
+	}
+}
+
+func TestQuerySkipAndRemaining(t *testing.T) {
+	w := NewWorld(TestCap)
+	NewBuilder2[Position, Velocity](w).NewEntities(4)
+	NewBuilder3[Position, Velocity, Health](w).NewEntities(3)
+	NewBuilder3[Position, Velocity, Dummy1](w).NewEntities(5)
+	f := NewFilter2[Position, Velocity](w)
+	if f.Count() != 12 {
+		t.Fatalf("expected count 12, got %d", f.Count())
+	}
+	all := append([]Entity(nil), f.Entities()...)
+
+	q := f.Query()
+	if q.Remaining() != 12 {
+		t.Errorf("expected 12 remaining before iterating, got %d", q.Remaining())
+	}
+	q.Skip(2)
+	if !q.Next() || q.Entity() != all[2] {
+		t.Errorf("expected third entity after skipping 2")
+	}
+	if p, v := q.Get(); p == nil || v == nil {
+		t.Error("expected non-nil components from query")
+	}
+	// Crosses from the first archetype (4) into the third (starting at 7).
+	q.Skip(5)
+	if !q.Next() || q.Entity() != all[8] {
+		t.Errorf("expected ninth entity after skipping across archetypes")
+	}
+	if q.Remaining() != 3 {
+		t.Errorf("expected 3 remaining, got %d", q.Remaining())
+	}
+	q.Skip(10)
+	if q.Next() {
+		t.Error("expected query to be exhausted")
+	}
+	q.Next()
+	if q.Remaining() != 0 {
+		t.Errorf("expected 0 remaining once exhausted, got %d", q.Remaining())
+	}
+	q1 := NewFilter[Position](w).Query()
+	for q1.Next() {
+	}
+	q1.Next()
+	if q1.Remaining() != 0 {
+		t.Errorf("expected 0 remaining after the loop, got %d", q1.Remaining())
+	}
+
+	// Budgeted processing: 5 entities per "frame".
+	seen := 0
+	for frame := 0; frame < 3; frame++ {
+		q := f.Query()
+		q.Skip(frame * 5)
+		for i := 0; i < 5 && q.Next(); i++ {
+			seen++
+		}
+	}
+	if seen != 12 {
+		t.Errorf("expected to visit 12 entities across frames, got %d", seen)
+	}
+}
+
+func TestWorldClose(t *testing.T) {
+	w := NewWorld(TestCap)
+	b := NewBuilder2[Position, Velocity](w)
+	b.NewEntities(10)
+	ent := b.NewEntity()
+	f := NewFilter2[Position, Velocity](w)
+	w.Close()
+	w.Close() // idempotent
+
+	if w.IsValid(ent) {
+		t.Error("expected entities to be invalid after Close")
+	}
+	if GetComponent[Position](w, ent) != nil {
+		t.Error("expected nil component after Close")
+	}
+	for _, a := range w.archetypes.archetypes {
+		t.Errorf("expected no archetypes after Close, found one of size %d", a.size)
+	}
+	mustPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected %s to panic on a closed world", name)
+			}
+		}()
+		fn()
+	}
+	mustPanic("Filter.Reset", f.Reset)
+	mustPanic("Builder.NewEntity", func() { b.NewEntity() })
+	mustPanic("NewFilter", func() { NewFilter[Position](w) })
+	mustPanic("CreateEntity", func() { w.CreateEntity() })
+}
+
+func TestComponentSlice(t *testing.T) {
+	w := NewWorld(TestCap)
+	if ComponentSlice[Position](w) != nil {
+		t.Error("expected nil for an unregistered component")
+	}
+	NewBuilder[Position](w).NewEntitiesWithValueSet(2, Position{X: 1})
+	NewBuilder2[Position, Velocity](w).NewEntitiesWithValueSet(3, Position{X: 2}, Velocity{})
+	NewBuilder[Velocity](w).NewEntities(4)
+
+	got := ComponentSlice[Position](w)
+	if len(got) != 5 {
+		t.Fatalf("expected 5 positions, got %d", len(got))
+	}
+	sum := float32(0)
+	for _, p := range got {
+		sum += p.X
+	}
+	if sum != 8 {
+		t.Errorf("expected X sum 8, got %v", sum)
+	}
+	got[0].X = 100
+	for f := NewFilter[Position](w); f.Next(); {
+		if f.Get().X == 100 {
+			t.Error("expected ComponentSlice to return a copy")
+		}
+	}
+}
+
+func TestMaxComponentTypesOverflow(t *testing.T) {
+	w := NewWorld(16)
+	types, _ := generateDistinctTypesAndRes(MaxComponentTypes + 1)
+	for i := 0; i < MaxComponentTypes; i++ {
+		if id := w.getCompTypeID(types[i]); int(id) != i {
+			t.Fatalf("expected id %d, got %d", i, id)
+		}
+	}
+	// The last valid id must still be usable in a mask.
+	var m bitmask256
+	m.set(MaxComponentTypes - 1)
+	if m[3] != uint64(1)<<63 {
+		t.Errorf("expected bit 255 to be set, got %x", m)
+	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic when exceeding MaxComponentTypes")
+		}
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "exceeded MaxComponentTypes") {
+			t.Errorf("unexpected panic message: %v", r)
+		}
+	}()
+	w.getCompTypeID(types[MaxComponentTypes])
+}
+
+func TestFilterGetValues(t *testing.T) {
+	w := NewWorld(16)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{X: 1, Y: 2})
+	SetComponent(w, e, Velocity{DX: 3, DY: 4})
+
+	f := NewFilter2[Position, Velocity](w)
+	var pos Position
+	var vel Velocity
+	for f.Next() {
+		pos, vel = f.GetValues()
+	}
+	if pos != (Position{X: 1, Y: 2}) || vel != (Velocity{DX: 3, DY: 4}) {
+		t.Fatalf("unexpected values %v %v", pos, vel)
+	}
+	// Copies must not alias storage.
+	pos.X = 100
+	if GetComponent[Position](w, e).X != 1 {
+		t.Error("GetValues result aliases archetype storage")
+	}
+
+	f1 := NewFilter[Velocity](w)
+	for f1.Next() {
+		vel = f1.GetValues()
+	}
+	if vel != (Velocity{DX: 3, DY: 4}) {
+		t.Errorf("unexpected value %v", vel)
+	}
+}
+
+func TestFreezeComponents(t *testing.T) {
+	w := NewWorld(8)
+	RegisterComponent[Position](w)
+	RegisterComponent[Velocity](w)
+	if n := len(w.RegisteredComponents()); n != 2 {
+		t.Fatalf("expected 2 registered components, got %d", n)
+	}
+	w.FreezeComponents()
+	w.FreezeComponents()
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{X: 1}, Velocity{DX: 2})
+	f := NewFilter2[Position, Velocity](w)
+	if f.Count() != 1 {
+		t.Errorf("expected 1 match on a frozen registry, got %d", f.Count())
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic registering a type after FreezeComponents")
+		}
+	}()
+	SetComponent(w, e, Health{})
+}
+
+func TestFreezeComponentsConcurrentLookups(t *testing.T) {
+	for range 20 {
+		w := NewWorld(8)
+		RegisterComponent[Position](w)
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 200 {
+					w.lookupCompTypeID(reflect.TypeFor[Position]())
+				}
+			}()
+		}
+		w.FreezeComponents()
+		wg.Wait()
+		if !w.components.mu.mu.TryLock() {
+			t.Fatal("registry read lock left held after FreezeComponents")
+		}
+		w.components.mu.mu.Unlock()
+		done := make(chan any)
+		go func() {
+			defer func() { done <- recover() }()
+			w.FreezeComponents()
+			RegisterComponent[Velocity](w)
+		}()
+		select {
+		case r := <-done:
+			if r == nil {
+				t.Fatal("expected a panic registering a type after FreezeComponents")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("registry lock left held by a reader queued behind FreezeComponents")
+		}
+	}
+}
+
+func TestRegistryLockReaderQueuedBehindFreeze(t *testing.T) {
+	var l registryLock
+	l.Lock()
+	released := make(chan struct{})
+	go func() {
+		l.RLock() // queues behind the write lock, having seen frozen unset
+		l.RUnlock()
+		close(released)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	l.frozen.Store(true)
+	l.Unlock()
+	<-released
+	if !l.mu.TryLock() {
+		t.Fatal("read lock of a reader queued behind the freeze was never released")
+	}
+}
+
+func TestSharedComponentRegistry(t *testing.T) {
+	reg := NewComponentRegistry()
+	a := NewWorldWithRegistry(reg, 16)
+	b := NewWorldWithRegistry(reg, 16)
+
+	// Register in opposite orders; IDs must still agree.
+	ea := a.CreateEntity()
+	SetComponent(a, ea, Position{})
+	SetComponent(a, ea, Velocity{})
+	eb := b.CreateEntity()
+	SetComponent(b, eb, Velocity{})
+	SetComponent(b, eb, Position{})
+
+	for _, typ := range []reflect.Type{reflect.TypeFor[Position](), reflect.TypeFor[Velocity]()} {
+		if a.getCompTypeID(typ) != b.getCompTypeID(typ) {
+			t.Errorf("component %v has different ids across worlds", typ)
+		}
+	}
+	if a.archetypes.archetypes[a.entities.metas[ea.ID].archetypeIndex].mask !=
+		b.archetypes.archetypes[b.entities.metas[eb.ID].archetypeIndex].mask {
+		t.Error("expected identical archetype masks across worlds")
+	}
+
+	// Private registries assign ids independently.
+	c := NewWorld(16)
+	ec := c.CreateEntity()
+	SetComponent(c, ec, Velocity{})
+	if c.getCompTypeID(reflect.TypeFor[Velocity]()) != 0 {
+		t.Error("expected private registry to start at id 0")
+	}
+}
+
+func TestFilterIncrementalMatching(t *testing.T) {
+	w := NewWorld(16)
+	f := NewFilter[Position](w)
+	if f.scanned != len(w.archetypes.archetypes) {
+		t.Fatalf("expected %d scanned archetypes, got %d", len(w.archetypes.archetypes), f.scanned)
+	}
+	e1 := w.CreateEntity()
+	SetComponent(w, e1, Position{X: 1})
+	e2 := w.CreateEntity()
+	SetComponent(w, e2, Position{X: 2})
+	SetComponent(w, e2, Velocity{})
+	f.Reset()
+	if f.scanned != len(w.archetypes.archetypes) {
+		t.Errorf("expected %d scanned archetypes, got %d", len(w.archetypes.archetypes), f.scanned)
+	}
+	if len(f.matchingArches) != 2 {
+		t.Fatalf("expected 2 matching archetypes, got %d", len(f.matchingArches))
+	}
+
+	// Emptying an archetype and repopulating it must not hide its entities,
+	// even though no new archetype is created in between.
+	RemoveComponent[Velocity](w, e2)
+	SetComponent(w, w.CreateEntity(), Health{})
+	f.Reset()
+	count := 0
+	for f.Next() {
+		if f.Entity() != e1 && f.Entity() != e2 {
+			t.Errorf("unexpected entity %v", f.Entity())
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 entities, got %d", count)
+	}
+	SetComponent(w, e1, Velocity{})
+	f.Reset()
+	q := f.Query()
+	count = 0
+	for q.Next() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 entities from query, got %d", count)
+	}
+}
+
+func TestUserData(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	if w.GetUserData(e) != 0 {
+		t.Error("expected zero user data on a new entity")
+	}
+	w.SetUserData(e, 42)
+	version := w.mutationVersion.Load()
+	SetComponent(w, e, Position{})
+	if got := w.GetUserData(e); got != 42 {
+		t.Errorf("expected user data to survive archetype moves, got %d", got)
+	}
+	w.SetUserData(e, 7)
+	if w.mutationVersion.Load() != version+1 {
+		t.Error("SetUserData must not be a structural change")
+	}
+
+	w.RemoveEntity(e)
+	if w.GetUserData(e) != 0 {
+		t.Error("expected zero user data for a removed entity")
+	}
+	w.SetUserData(e, 99) // ignored
+	e2 := w.CreateEntity()
+	if e2.ID != e.ID {
+		t.Fatalf("expected ID %d to be recycled, got %d", e.ID, e2.ID)
+	}
+	if w.GetUserData(e2) != 0 {
+		t.Error("recycled entity inherited user data")
+	}
+
+	f := NewFilter0(w)
+	w.SetUserData(e2, 5)
+	f.RemoveEntities()
+	if e3 := w.CreateEntity(); w.GetUserData(e3) != 0 {
+		t.Error("user data not cleared by Filter.RemoveEntities")
+	}
+}
+
+type Inventory struct {
+	Items []int
+	Owner *Position
+}
+
+func TestRemovedSlotsAreZeroed(t *testing.T) {
+	w := NewWorld(8)
+	b := NewBuilder2[Inventory, Position](w)
+	b.NewEntities(4)
+	f := NewFilter[Inventory](w)
+	for f.Next() {
+		inv := f.Get()
+		inv.Items = make([]int, 16)
+		inv.Owner = &Position{X: 1}
+	}
+	a := f.matchingArches[0]
+	cols := unsafe.Slice((*Inventory)(a.compPointers[f.compID]), w.entities.capacity)
+
+	// Removing one entity clears the vacated last slot.
+	w.RemoveEntity(f.Entities()[0])
+	if cols[3].Items != nil || cols[3].Owner != nil {
+		t.Error("expected vacated slot to be zeroed after RemoveEntity")
+	}
+
+	f.RemoveEntities()
+	for i := range 3 {
+		if cols[i].Items != nil || cols[i].Owner != nil {
+			t.Errorf("slot %d retains pointers after RemoveEntities", i)
+		}
+	}
+
+	// Entities reusing the slots start from zero values.
+	b.NewEntities(2)
+	f.Reset()
+	for f.Next() {
+		if f.Get().Items != nil {
+			t.Error("new entity inherited a dead entity's data")
+		}
+	}
+
+	if typeHasPointers(reflect.TypeFor[Position]()) {
+		t.Error("Position has no pointers")
+	}
+	if !typeHasPointers(reflect.TypeFor[[2]Inventory]()) {
+		t.Error("[2]Inventory has pointers")
+	}
+}
+
+func TestWorldMerge(t *testing.T) {
+	reg := NewComponentRegistry()
+	main := NewWorldWithRegistry(reg, 4)
+	chunk := NewWorldWithRegistry(reg, 4)
+
+	existing := main.CreateEntity()
+	SetComponent(main, existing, Position{X: -1})
+
+	var src []Entity
+	for i := range 6 {
+		e := chunk.CreateEntity()
+		SetComponent(chunk, e, Position{X: float32(i)})
+		if i%2 == 0 {
+			SetComponent(chunk, e, Velocity{DX: float32(i)})
+		}
+		chunk.SetUserData(e, uint64(i))
+		src = append(src, e)
+	}
+	chunk.CreateEntity() // entity without components
+
+	remap := main.Merge(chunk)
+	if len(remap) != 7 {
+		t.Fatalf("expected 7 remapped entities, got %d", len(remap))
+	}
+	for i, e := range src {
+		ne, ok := remap[e]
+		if !ok || !main.IsValid(ne) {
+			t.Fatalf("entity %v was not imported", e)
+		}
+		if ne == existing {
+			t.Fatal("imported entity collides with an existing one")
+		}
+		if p := GetComponent[Position](main, ne); p == nil || p.X != float32(i) {
+			t.Errorf("unexpected position for %v: %v", ne, p)
+		}
+		v := GetComponent[Velocity](main, ne)
+		if (i%2 == 0) != (v != nil) {
+			t.Errorf("unexpected velocity presence for %v", ne)
+		} else if v != nil && v.DX != float32(i) {
+			t.Errorf("unexpected velocity for %v: %v", ne, v)
+		}
+		if main.GetUserData(ne) != uint64(i) {
+			t.Errorf("user data not imported for %v", ne)
+		}
+	}
+	if p := GetComponent[Position](main, existing); p == nil || p.X != -1 {
+		t.Error("existing entity was modified by Merge")
+	}
+	if GetComponent[Position](chunk, src[0]) == nil {
+		t.Error("Merge must leave the source world unchanged")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when merging worlds with different registries")
+		}
+	}()
+	main.Merge(NewWorld(4))
+}
+
+func TestPrewarm(t *testing.T) {
+	w := NewWorld(16)
+	f := NewFilter2[Position, Velocity](w)
+	base := len(w.archetypes.archetypes)
+
+	Prewarm2[Position, Velocity](w)
+	Prewarm2[Position, Velocity](w)
+	Prewarm[Health](w)
+	w.PrewarmArchetype(reflect.TypeFor[Velocity](), reflect.TypeFor[Position](), reflect.TypeFor[Position]())
+	if got := len(w.archetypes.archetypes); got != base+2 {
+		t.Fatalf("expected %d archetypes, got %d", base+2, got)
+	}
+	if w.mutationVersion.Load() != 0 {
+		t.Error("prewarming must not create entities")
+	}
+
+	w.PrewarmFilter(f)
+	if f.isArchetypeStale() || len(f.matchingArches) != 1 {
+		t.Fatalf("expected filter to be prewarmed with 1 archetype, got %d", len(f.matchingArches))
+	}
+
+	// Entities created afterwards reuse the prewarmed archetype.
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{}, Velocity{})
+	if len(w.archetypes.archetypes) != base+2 {
+		t.Error("expected the prewarmed archetype to be reused")
+	}
+	f.Reset()
+	if !f.Next() || f.Entity() != e {
+		t.Error("expected prewarmed filter to yield the new entity")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a filter bound to another world")
+		}
+	}()
+	w.PrewarmFilter(NewFilter[Position](NewWorld(4)))
+}
+
+func TestFilterIndex(t *testing.T) {
+	w := NewWorld(16)
+	for i := range 5 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Velocity{})
+		if i%2 == 0 {
+			SetComponent(w, e, Health{})
+		}
+	}
+	f := NewFilter2[Position, Velocity](w)
+	ents := f.Entities()
+	n := 0
+	for f.Next() {
+		if f.Index() != n {
+			t.Fatalf("expected index %d, got %d", n, f.Index())
+		}
+		if ents[f.Index()] != f.Entity() {
+			t.Errorf("index %d does not line up with Entities", n)
+		}
+		n++
+	}
+	f.Reset()
+	if !f.Next() || f.Index() != 0 {
+		t.Error("expected Reset to restart the index")
+	}
+
+	q := f.Query()
+	n = 0
+	for q.Next() {
+		if q.Index() != n {
+			t.Fatalf("expected query index %d, got %d", n, q.Index())
+		}
+		n++
+	}
+	q = f.Query()
+	q.Skip(3)
+	if !q.Next() || q.Index() != 3 {
+		t.Errorf("expected index 3 after Skip, got %d", q.Index())
+	}
+}
+
+func TestSwapComponents(t *testing.T) {
+	w := NewWorld(8)
+	a := w.CreateEntity()
+	SetComponent2(w, a, Position{X: 1}, Velocity{DX: 10})
+	b := w.CreateEntity()
+	SetComponent2(w, b, Position{X: 2}, Velocity{DX: 20})
+	c := w.CreateEntity()
+	SetComponent(w, c, Position{X: 3})
+
+	version := w.mutationVersion.Load()
+	if !w.SwapComponents(a, b) {
+		t.Fatal("expected entities in the same archetype to swap")
+	}
+	if w.mutationVersion.Load() == version {
+		t.Error("expected SwapComponents to bump the mutation version")
+	}
+	if p := GetComponent[Position](w, a); p.X != 1 {
+		t.Errorf("entity a lost its data: %v", p)
+	}
+	if v := GetComponent[Velocity](w, b); v.DX != 20 {
+		t.Errorf("entity b lost its data: %v", v)
+	}
+	f := NewFilter2[Position, Velocity](w)
+	var order []Entity
+	for f.Next() {
+		order = append(order, f.Entity())
+	}
+	if len(order) != 2 || order[0] != b || order[1] != a {
+		t.Errorf("expected storage order [b a], got %v", order)
+	}
+	if ents := f.Entities(); ents[0] != b || ents[1] != a {
+		t.Errorf("expected cached entities [b a], got %v", ents)
+	}
+
+	if w.SwapComponents(a, c) {
+		t.Error("expected swap across archetypes to fail")
+	}
+	w.RemoveEntity(b)
+	if w.SwapComponents(a, b) {
+		t.Error("expected swap with an invalid entity to fail")
+	}
+}
+
+func TestArchetypeGraph(t *testing.T) {
+	w := NewWorld(8)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{})
+	SetComponent(w, e, Velocity{})
+	e2 := w.CreateEntity()
+	SetComponent(w, e2, Velocity{})
+
+	graph := w.ArchetypeGraph()
+	if len(graph) != 4 {
+		t.Fatalf("expected 4 archetypes, got %d", len(graph))
+	}
+	if edges := graph[Mask{}]; len(edges) != 2 {
+		t.Errorf("expected the empty archetype to have 2 neighbours, got %d", len(edges))
+	}
+
+	names := w.ArchetypeGraphNames()
+	full := "[teishoku.Position teishoku.Velocity]"
+	edges, ok := names[full]
+	if !ok {
+		t.Fatalf("missing archetype %s in %v", full, names)
+	}
+	if len(edges) != 2 {
+		t.Errorf("expected %s to have 2 neighbours, got %v", full, edges)
+	}
+	if len(names["[]"]) != 2 {
+		t.Errorf("expected [] to have 2 neighbours, got %v", names["[]"])
+	}
+}
+
+func TestAddComponentToAll(t *testing.T) {
+	w := NewWorld(16)
+	var plain, hurt []Entity
+	for i := range 4 {
+		e := w.CreateEntity()
+		SetComponent(w, e, Position{X: float32(i)})
+		plain = append(plain, e)
+	}
+	for range 2 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{}, Health{HP: 1})
+		hurt = append(hurt, e)
+	}
+	other := w.CreateEntity()
+	SetComponent(w, other, Velocity{})
+
+	version := w.mutationVersion.Load()
+	AddComponentToAll(NewFilter[Position](w), Health{HP: 50})
+	if w.mutationVersion.Load() != version+1 {
+		t.Errorf("expected a single version bump, got %d", w.mutationVersion.Load()-version)
+	}
+	for i, e := range plain {
+		if h := GetComponent[Health](w, e); h == nil || h.HP != 50 {
+			t.Errorf("entity %v: expected Health 50, got %v", e, h)
+		}
+		if p := GetComponent[Position](w, e); p == nil || p.X != float32(i) {
+			t.Errorf("entity %v lost its position: %v", e, p)
+		}
+	}
+	for _, e := range hurt {
+		if h := GetComponent[Health](w, e); h.HP != 50 {
+			t.Errorf("entity %v: expected Health updated in place, got %v", e, h)
+		}
+	}
+	if GetComponent[Health](w, other) != nil {
+		t.Error("unmatched entity must not receive the component")
+	}
+	if n := NewFilter2[Position, Health](w).Count(); n != 6 {
+		t.Errorf("expected 6 entities with Position and Health, got %d", n)
+	}
+}
+
+func TestModifyComponents(t *testing.T) {
+	w := NewWorld(8)
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{X: 3}, Velocity{DX: 1})
+	version := w.mutationVersion.Load()
+	ModifyComponents[Health, Velocity](w, e, Health{HP: 7})
+	if w.mutationVersion.Load() != version+1 {
+		t.Errorf("expected a single structural change, got %d", w.mutationVersion.Load()-version)
+	}
+	if h := GetComponent[Health](w, e); h == nil || h.HP != 7 {
+		t.Errorf("expected Health 7, got %v", h)
+	}
+	if GetComponent[Velocity](w, e) != nil {
+		t.Error("Velocity was not removed")
+	}
+	if p := GetComponent[Position](w, e); p == nil || p.X != 3 {
+		t.Errorf("Position was not kept: %v", p)
+	}
+
+	// The entity already has Health and lacks Velocity: an in-place update.
+	version = w.mutationVersion.Load()
+	ModifyComponents[Health, Velocity](w, e, Health{HP: 9})
+	if w.mutationVersion.Load() != version {
+		t.Error("an in-place update bumped the mutation version")
+	}
+	if h := GetComponent[Health](w, e); h == nil || h.HP != 9 {
+		t.Errorf("expected Health 9, got %v", h)
+	}
+
+	ModifyComponents[Velocity, Scale](w, e, Velocity{DX: 2})
+	if v := GetComponent[Velocity](w, e); v == nil || v.DX != 2 {
+		t.Errorf("expected Velocity 2, got %v", v)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModifyComponentsUnregisteredRemoveAfterFreeze(t *testing.T) {
+	type neverAdded struct{}
+	w := NewWorld(4)
+	RegisterComponent[Position](w)
+	w.FreezeComponents()
+	e := w.CreateEntity()
+	ModifyComponents[Position, neverAdded](w, e, Position{X: 4}) // must not register neverAdded
+	if p := GetComponent[Position](w, e); p == nil || p.X != 4 {
+		t.Errorf("expected Position 4, got %v", p)
+	}
+}
+
+func TestMoveEntities(t *testing.T) {
+	w := NewWorld(16)
+	TrackChanges[Velocity](w)
+	var batch []Entity
+	for i := range 6 {
+		e := w.CreateEntity()
+		if i%2 == 0 {
+			SetComponent2(w, e, Position{X: float32(i)}, Health{HP: i})
+		} else {
+			SetComponent(w, e, Position{X: float32(i)})
+		}
+		batch = append(batch, e)
+	}
+	dead := w.CreateEntity()
+	w.RemoveEntity(dead)
+	batch = append(batch, batch[1], dead)
+
+	version := w.mutationVersion.Load()
+	w.MoveEntities(batch, []reflect.Type{reflect.TypeFor[Velocity]()}, []reflect.Type{reflect.TypeFor[Health]()})
+	if w.mutationVersion.Load() != version+1 {
+		t.Errorf("expected a single version bump, got %d", w.mutationVersion.Load()-version)
+	}
+	for i, e := range batch[:6] {
+		if GetComponent[Health](w, e) != nil {
+			t.Errorf("entity %v still has Health", e)
+		}
+		if v := GetComponent[Velocity](w, e); v == nil || *v != (Velocity{}) {
+			t.Errorf("entity %v has no zero Velocity: %v", e, v)
+		}
+		if p := GetComponent[Position](w, e); p == nil || p.X != float32(i) {
+			t.Errorf("entity %v lost its position: %v", e, p)
+		}
+	}
+	if n := NewFilter2[Position, Velocity](w).Count(); n != 6 {
+		t.Errorf("expected 6 moved entities, got %d", n)
+	}
+	w.BeginFrame()
+	added := NewFilter[Velocity](w)
+	n := 0
+	for added.NextAdded() {
+		n++
+	}
+	if n != 6 {
+		t.Errorf("expected 6 entities to have Velocity added, got %d", n)
+	}
+	version = w.mutationVersion.Load()
+	w.MoveEntities(batch, []reflect.Type{reflect.TypeFor[Velocity]()}, nil)
+	if w.mutationVersion.Load() != version {
+		t.Error("a no-op move bumped the mutation version")
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveEntitiesZeroesReusedSlots(t *testing.T) {
+	w := NewWorld(8)
+	old := w.CreateEntity()
+	SetComponent2(w, old, Position{}, Velocity{DX: 5})
+	w.RemoveEntity(old) // leaves its Velocity bytes in the freed slot
+	b := w.CreateEntity()
+	SetComponent(w, b, Position{X: 1})
+	w.MoveEntities([]Entity{b}, []reflect.Type{reflect.TypeFor[Velocity]()}, nil)
+	if v := GetComponent[Velocity](w, b); v == nil || *v != (Velocity{}) {
+		t.Errorf("expected a zero Velocity in the reused slot, got %v", v)
+	}
+}
+
+func TestRemoveComponentBatch(t *testing.T) {
+	w := NewWorld(16)
+	var batch []Entity
+	for i := range 6 {
+		e := w.CreateEntity()
+		if i%2 == 0 {
+			SetComponent2(w, e, Position{X: float32(i)}, Health{HP: i})
+		} else {
+			SetComponent2(w, e, Velocity{DX: float32(i)}, Health{HP: i})
+		}
+		batch = append(batch, e)
+	}
+	kept := w.CreateEntity()
+	SetComponent2(w, kept, Position{}, Health{HP: 99})
+	dead := w.CreateEntity()
+	w.RemoveEntity(dead)
+	batch = append(batch, batch[0], dead) // duplicates and invalid entities are ignored
+
+	version := w.mutationVersion.Load()
+	RemoveComponentBatch[Health](w, batch)
+	if w.mutationVersion.Load() != version+1 {
+		t.Errorf("expected a single version bump, got %d", w.mutationVersion.Load()-version)
+	}
+	for i, e := range batch[:6] {
+		if GetComponent[Health](w, e) != nil {
+			t.Errorf("entity %v still has Health", e)
+		}
+		if i%2 == 0 {
+			if p := GetComponent[Position](w, e); p == nil || p.X != float32(i) {
+				t.Errorf("entity %v lost its position: %v", e, p)
+			}
+		} else if v := GetComponent[Velocity](w, e); v == nil || v.DX != float32(i) {
+			t.Errorf("entity %v lost its velocity: %v", e, v)
+		}
+	}
+	if h := GetComponent[Health](w, kept); h == nil || h.HP != 99 {
+		t.Errorf("unlisted entity changed: %v", h)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	version = w.mutationVersion.Load()
+	RemoveComponentBatch[Health](w, batch)
+	if w.mutationVersion.Load() != version {
+		t.Error("expected no version bump when nothing was removed")
+	}
+}
+
+func TestRemoveComponentFromAll(t *testing.T) {
+	w := NewWorld(16)
+	var tagged []Entity
+	for i := range 3 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Health{})
+		tagged = append(tagged, e)
+	}
+	e := w.CreateEntity()
+	SetComponent2(w, e, Velocity{}, Health{})
+	tagged = append(tagged, e)
+	untouched := w.CreateEntity()
+	SetComponent(w, untouched, Position{})
+
+	version := w.mutationVersion.Load()
+	RemoveComponentFromAll[Health](NewFilter[Health](w))
+	if w.mutationVersion.Load() != version+1 {
+		t.Errorf("expected a single version bump, got %d", w.mutationVersion.Load()-version)
+	}
+	for i, e := range tagged {
+		if GetComponent[Health](w, e) != nil {
+			t.Errorf("entity %v still has Health", e)
+		}
+		if i < 3 {
+			if p := GetComponent[Position](w, e); p == nil || p.X != float32(i) {
+				t.Errorf("entity %v lost its position: %v", e, p)
+			}
+		}
+	}
+	if GetComponent[Velocity](w, tagged[3]) == nil {
+		t.Error("expected other components to be kept")
+	}
+	if NewFilter[Health](w).Count() != 0 {
+		t.Error("expected no entity with Health left")
+	}
+
+	version = w.mutationVersion.Load()
+	RemoveComponentFromAll[Health](NewFilter[Position](w))
+	if w.mutationVersion.Load() != version {
+		t.Error("expected no version bump when nothing was removed")
+	}
+}
+
+func TestComponentSchema(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{}, Health{})
+
+	schema := w.ComponentSchema()
+	if len(schema) != 2 {
+		t.Fatalf("expected 2 schema entries, got %v", schema)
+	}
+	if schema["teishoku.Position"] != int(unsafe.Sizeof(Position{})) {
+		t.Errorf("unexpected Position size %d", schema["teishoku.Position"])
+	}
+	if err := w.CheckComponentSchema(schema); err != nil {
+		t.Errorf("expected matching schema, got %v", err)
+	}
+
+	schema["teishoku.Position"] = 4
+	schema["teishoku.Unknown"] = 12
+	err := w.CheckComponentSchema(schema)
+	if err == nil || !strings.Contains(err.Error(), "teishoku.Position") {
+		t.Errorf("expected layout mismatch error for Position, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Unknown") {
+		t.Errorf("unregistered types must be ignored: %v", err)
+	}
+}
+
+func TestSetComponentPriority(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	SetComponent(w, e, Health{})
+	SetComponent(w, e, Position{})
+	old := w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex]
+
+	w.SetComponentPriority(reflect.TypeFor[Velocity](), reflect.TypeFor[Position]())
+	e2 := w.CreateEntity()
+	SetComponent3(w, e2, Health{}, Position{}, Velocity{})
+	a := w.archetypes.archetypes[w.entities.metas[e2.ID].archetypeIndex]
+	want := []uint8{
+		w.getCompTypeID(reflect.TypeFor[Velocity]()),
+		w.getCompTypeID(reflect.TypeFor[Position]()),
+		w.getCompTypeID(reflect.TypeFor[Health]()),
+	}
+	if !slices.Equal(a.compOrder, want) {
+		t.Errorf("expected column order %v, got %v", want, a.compOrder)
+	}
+	if hp := w.getCompTypeID(reflect.TypeFor[Health]()); old.compOrder[0] != hp {
+		t.Error("existing archetypes must keep their layout")
+	}
+	if GetComponent[Velocity](w, e2) == nil || GetComponent[Health](w, e2) == nil {
+		t.Error("expected components to be reachable in the reordered archetype")
+	}
+}
+
+func TestEntityState(t *testing.T) {
+	w := NewWorld(4)
+	if s := w.EntityState(Entity{}); s != EntityInvalid {
+		t.Errorf("expected zero entity to be Invalid, got %v", s)
+	}
+	e := w.CreateEntity()
+	if s := w.EntityState(e); s != EntityAlive {
+		t.Errorf("expected Alive, got %v", s)
+	}
+	if s := w.EntityState(Entity{ID: e.ID, Version: e.Version + 100}); s != EntityInvalid {
+		t.Errorf("expected unissued version to be Invalid, got %v", s)
+	}
+	if s := w.EntityState(Entity{ID: 1000, Version: e.Version}); s != EntityInvalid {
+		t.Errorf("expected out-of-range ID to be Invalid, got %v", s)
+	}
+	w.RemoveEntity(e)
+	if s := w.EntityState(e); s != EntityRecycled {
+		t.Errorf("expected Recycled, got %v", s)
+	}
+	e2 := w.CreateEntity()
+	if e2.ID != e.ID {
+		t.Fatal("expected the ID to be reused")
+	}
+	if s := w.EntityState(e); s != EntityRecycled {
+		t.Errorf("expected stale handle to stay Recycled after reuse, got %v", s)
+	}
+	if EntityRecycled.String() != "Recycled" {
+		t.Errorf("unexpected String %q", EntityRecycled.String())
+	}
+}
+
+func TestEntityStateAfterVersionWrap(t *testing.T) {
+	w := NewWorld(4)
+	w.entities.nextEntityVer = math.MaxUint32
+	old := w.CreateEntity()
+	w.RemoveEntity(old)
+	fresh := w.CreateEntity() // the version counter wrapped to 1
+	if fresh.Version != 1 {
+		t.Fatalf("expected the version counter to wrap, got %d", fresh.Version)
+	}
+	w.RemoveEntity(fresh)
+	if s := w.EntityState(old); s != EntityRecycled {
+		t.Errorf("expected a handle issued before the wrap to be Recycled, got %v", s)
+	}
+	if s := w.EntityState(Entity{ID: 3, Version: 1}); s != EntityInvalid {
+		t.Errorf("expected a never-used ID to be Invalid, got %v", s)
+	}
+}
+
+func TestSingleThreadedWorld(t *testing.T) {
+	w := NewWorld(2, WithSingleThreaded())
+	if !w.mu.disabled {
+		t.Fatal("expected the world lock to be disabled")
+	}
+	for i := range 5 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Velocity{})
+	}
+	f := NewFilter2[Position, Velocity](w)
+	n := 0
+	for f.Next() {
+		n++
+	}
+	if n != 5 {
+		t.Errorf("expected 5 entities, got %d", n)
+	}
+	// Nested lock acquisitions would deadlock on a locked world but are
+	// harmless when locking is disabled.
+	w.mu.Lock()
+	w.mu.Lock()
+	w.mu.Unlock()
+	w.mu.Unlock()
+	if NewWorld(1).mu.disabled {
+		t.Error("worlds must be locked by default")
+	}
+}
+
+func TestSpawnBatch(t *testing.T) {
+	w := NewWorld(2)
+	pos := reflect.TypeFor[Position]()
+	vel := reflect.TypeFor[Velocity]()
+	version := w.mutationVersion.Load()
+	ents := w.SpawnBatch([]SpawnGroup{
+		{Types: []reflect.Type{pos}, Count: 10},
+		{Types: []reflect.Type{pos, vel}, Count: 5},
+		{Types: nil, Count: 3},
+		{Types: []reflect.Type{vel, pos}, Count: 2},
+	})
+	if len(ents) != 20 {
+		t.Fatalf("expected 20 entities, got %d", len(ents))
+	}
+	if w.mutationVersion.Load() != version+1 {
+		t.Error("expected a single version bump")
+	}
+	if w.entities.capacity != 32 {
+		t.Errorf("expected capacity 32, got %d", w.entities.capacity)
+	}
+	seen := make(map[Entity]bool)
+	for _, e := range ents {
+		if !w.IsValid(e) || seen[e] {
+			t.Fatalf("invalid or duplicate entity %v", e)
+		}
+		seen[e] = true
+	}
+	if n := NewFilter[Position](w).Count(); n != 17 {
+		t.Errorf("expected 17 entities with Position, got %d", n)
+	}
+	if n := NewFilter2[Position, Velocity](w).Count(); n != 7 {
+		t.Errorf("expected 7 entities with Position and Velocity, got %d", n)
+	}
+	if GetComponent[Velocity](w, ents[9]) != nil || GetComponent[Velocity](w, ents[10]) == nil {
+		t.Error("entities were not created in group order")
+	}
+	if w.SpawnBatch(nil) != nil {
+		t.Error("expected nil for an empty batch")
+	}
+}
+
+func TestSpawnBatchNegativeCounts(t *testing.T) {
+	w := NewWorld(2)
+	pos := reflect.TypeFor[Position]()
+	for _, neg := range []int{-5, -10} {
+		ents := w.SpawnBatch([]SpawnGroup{
+			{Types: []reflect.Type{pos}, Count: 5},
+			{Types: []reflect.Type{pos}, Count: neg},
+		})
+		if len(ents) != 5 {
+			t.Errorf("expected the valid group to create 5 entities next to a count of %d, got %d", neg, len(ents))
+		}
+	}
+	if ents := w.SpawnBatch([]SpawnGroup{{Count: -1}}); ents != nil {
+		t.Errorf("expected no entities, got %v", ents)
+	}
+}
+
+func TestQueryCursorSeekTo(t *testing.T) {
+	w := NewWorld(16)
+	for i := range 6 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Velocity{})
+		if i >= 3 {
+			SetComponent(w, e, Health{})
+		}
+	}
+	f := NewFilter2[Position, Velocity](w)
+	var all []Entity
+	var cursors [][2]int
+	q := f.Query()
+	for q.Next() {
+		a, i := q.Cursor()
+		all = append(all, q.Entity())
+		cursors = append(cursors, [2]int{a, i})
+	}
+	if len(all) != 6 {
+		t.Fatalf("expected 6 entities, got %d", len(all))
+	}
+
+	// Resume right after each recorded position.
+	for n, c := range cursors {
+		q := f.Query()
+		q.SeekTo(c[0], c[1])
+		if q.Entity() != all[n] || q.Index() != n {
+			t.Fatalf("SeekTo(%d, %d): expected %v at index %d, got %v at %d", c[0], c[1], all[n], n, q.Entity(), q.Index())
+		}
+		rest := 0
+		for q.Next() {
+			if q.Entity() != all[n+1+rest] {
+				t.Fatalf("unexpected entity after resuming at %d", n)
+			}
+			rest++
+		}
+		if rest != len(all)-n-1 {
+			t.Errorf("expected %d entities after resuming at %d, got %d", len(all)-n-1, n, rest)
+		}
+	}
+
+	// Positioning before an archetype's first element.
+	q = f.Query()
+	q.SeekTo(1, -1)
+	if q.Remaining() != 3 || !q.Next() || q.Entity() != all[3] {
+		t.Error("expected SeekTo(1, -1) to resume at the second archetype")
+	}
+	q.SeekTo(10, 0)
+	if q.Next() {
+		t.Error("expected SeekTo past the end to exhaust the query")
+	}
+
+	q1 := NewFilter[Position](w).Query()
+	q1.SeekTo(0, 1)
+	if a, i := q1.Cursor(); a != 0 || i != 1 || q1.Get().X != 1 {
+		t.Errorf("unexpected single-component cursor (%d, %d)", a, i)
+	}
+}
+
+func TestRemoveAndGet(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{X: 3}, Inventory{Items: []int{1, 2}})
+
+	inv, ok := RemoveAndGet[Inventory](w, e)
+	if !ok || len(inv.Items) != 2 {
+		t.Fatalf("expected removed inventory with 2 items, got %v %v", inv, ok)
+	}
+	if GetComponent[Inventory](w, e) != nil {
+		t.Error("expected the component to be removed")
+	}
+	if p := GetComponent[Position](w, e); p == nil || p.X != 3 {
+		t.Error("expected other components to be kept")
+	}
+	if _, ok := RemoveAndGet[Inventory](w, e); ok {
+		t.Error("expected false for a missing component")
+	}
+	if _, ok := RemoveAndGet[Dummy1](w, e); ok {
+		t.Error("expected false for an unregistered component")
+	}
+	w.RemoveEntity(e)
+	if _, ok := RemoveAndGet[Position](w, e); ok {
+		t.Error("expected false for an invalid entity")
+	}
+}
+
+func TestSetMaxEntities(t *testing.T) {
+	w := NewWorld(2)
+	w.SetMaxEntities(5)
+	b := NewBuilder2[Position, Velocity](w)
+	if n := b.NewEntities(3); n != 3 {
+		t.Fatalf("expected 3 entities, got %d", n)
+	}
+	if n := b.NewEntitiesWithValueSet(4, Position{X: 1}, Velocity{}); n != 2 {
+		t.Fatalf("expected the batch to stop at the cap after 2 entities, got %d", n)
+	}
+	if w.entities.capacity > 5 {
+		t.Errorf("capacity %d grew past the limit", w.entities.capacity)
+	}
+	version := w.mutationVersion.Load()
+	if _, ok := b.TryNewEntity(); ok {
+		t.Error("expected TryNewEntity to fail at the cap")
+	}
+	if e := w.CreateEntity(); e != (Entity{}) {
+		t.Errorf("expected the zero Entity at the cap, got %v", e)
+	}
+	if n := w.CreateEntities(3); n != 0 {
+		t.Errorf("expected no entities at the cap, got %d", n)
+	}
+	if w.mutationVersion.Load() != version {
+		t.Error("failed creations must not bump the mutation version")
+	}
+
+	w.RemoveEntity(NewFilter[Position](w).Entities()[0])
+	if e, ok := b.TryNewEntity(); !ok || !w.IsValid(e) {
+		t.Error("expected creation to succeed once an entity was removed")
+	}
+
+	w.SetMaxEntities(0)
+	if n := w.CreateEntities(10); n != 10 {
+		t.Errorf("expected the limit to be removed, got %d", n)
+	}
+}
+
+func TestNewFilterExact(t *testing.T) {
+	w := NewWorld(8)
+	pure := w.CreateEntity()
+	SetComponent2(w, pure, Position{}, Velocity{})
+	rich := w.CreateEntity()
+	SetComponent3(w, rich, Position{}, Velocity{}, Health{})
+	only := w.CreateEntity()
+	SetComponent(w, only, Position{})
+
+	f := NewFilterExact2[Position, Velocity](w)
+	ents := f.Entities()
+	if len(ents) != 1 || ents[0] != pure {
+		t.Errorf("expected only the pure entity, got %v", ents)
+	}
+	if n := NewFilter2[Position, Velocity](w).Count(); n != 2 {
+		t.Errorf("expected subset filter to match 2 entities, got %d", n)
+	}
+	f1 := NewFilterExact[Position](w)
+	if ents := f1.Entities(); len(ents) != 1 || ents[0] != only {
+		t.Errorf("expected only the Position-only entity, got %v", ents)
+	}
+
+	// Archetypes created later follow the same rule, and New keeps the mode.
+	e := w.CreateEntity()
+	SetComponent3(w, e, Velocity{}, Position{}, Dummy1{})
+	clone := f.New(w)
+	if n := clone.Count(); n != 1 {
+		t.Errorf("expected the cloned exact filter to match 1 entity, got %d", n)
+	}
+	if n := f.Count(); n != 1 {
+		t.Errorf("expected 1 exact match, got %d", n)
+	}
+}
+
+type alignedCounter struct {
+	Flag  byte
+	Count atomic.Int64
+}
+
+type vec4 struct {
+	V [4]float32
+}
+
+type oddSized struct {
+	A uint16
+	B byte
+}
+
+func checkColumnAlignment[T any](t *testing.T, w *World) {
+	t.Helper()
+	typ := reflect.TypeFor[T]()
+	id := w.getCompTypeID(typ)
+	align := uintptr(typ.Align())
+	for _, a := range w.archetypes.archetypes {
+		if !a.mask.has(id) {
+			continue
+		}
+		if a.compSizes[id]%align != 0 {
+			t.Errorf("%v: size %d is not a multiple of alignment %d", typ, a.compSizes[id], align)
+		}
+		for i := 0; i < a.size; i++ {
+			if addr := uintptr(a.compPointers[id]) + uintptr(i)*a.compSizes[id]; addr%align != 0 {
+				t.Fatalf("%v: element %d at %#x is not %d-byte aligned", typ, i, addr, align)
+			}
+		}
+	}
+}
+
+func TestComponentColumnAlignment(t *testing.T) {
+	w := NewWorld(1)
+	b := NewBuilder3[oddSized, alignedCounter, vec4](w)
+	b.NewEntities(10) // grows the world several times
+	e := w.CreateEntity()
+	SetComponent2(w, e, oddSized{}, alignedCounter{})
+
+	checkColumnAlignment[oddSized](t, w)
+	checkColumnAlignment[alignedCounter](t, w)
+	checkColumnAlignment[vec4](t, w)
+
+	f := NewFilter[alignedCounter](w)
+	for f.Next() {
+		f.Get().Count.Add(1) // atomics require 8-byte alignment on 32-bit platforms
+	}
+}
+
+func TestEntitiesCached(t *testing.T) {
+	w := NewWorld(4)
+	e1 := w.CreateEntity()
+	SetComponent2(w, e1, Position{}, Velocity{})
+	f := NewFilter2[Position, Velocity](w)
+	first := f.EntitiesCached()
+	if len(first) != 1 || first[0] != e1 {
+		t.Fatalf("expected [%v], got %v", e1, first)
+	}
+	if again := f.EntitiesCached(); &again[0] != &first[0] {
+		t.Fatal("expected the same slice while the world is unchanged")
+	}
+	SetComponent(w, e1, Position{X: 1})
+	if again := f.EntitiesCached(); &again[0] != &first[0] {
+		t.Fatal("component writes must not invalidate the cached slice")
+	}
+
+	e2 := w.CreateEntity()
+	SetComponent2(w, e2, Position{}, Velocity{})
+	second := f.EntitiesCached()
+	if len(second) != 2 {
+		t.Fatalf("expected 2 entities after a structural change, got %v", second)
+	}
+	if len(first) != 1 || first[0] != e1 {
+		t.Fatal("a previously returned slice was modified")
+	}
+	w.RemoveEntity(e1)
+	f.Entities()
+	if second[0] != e1 || second[1] != e2 {
+		t.Fatal("Entities rewrote a slice returned by EntitiesCached")
+	}
+}
+
+func TestView2(t *testing.T) {
+	w := NewWorld(4)
+	v := NewView2[Position, Velocity](w)
+	e1 := v.Spawn()
+	e2 := v.SpawnWith(Position{X: 1}, Velocity{DX: 2})
+	if p, vel := v.Get(e2); p == nil || p.X != 1 || vel.DX != 2 {
+		t.Fatalf("SpawnWith: unexpected components %v %v", p, vel)
+	}
+	v.Set(e1, Position{X: 3}, Velocity{DX: 4})
+	other := w.CreateEntity()
+	SetComponent(w, other, Position{})
+	v.Set(other, Position{X: 5}, Velocity{DX: 6})
+
+	sum := 0
+	n := 0
+	v.Each(func(e Entity, p *Position, vel *Velocity) {
+		sum += int(p.X + vel.DX)
+		n++
+	})
+	if n != 3 || sum != 21 {
+		t.Fatalf("Each: expected 3 entities summing to 21, got %d and %d", n, sum)
+	}
+	if v.Filter().Count() != 3 || v.Builder() == nil {
+		t.Fatal("unexpected underlying filter or builder")
+	}
+}
+
+func TestReusedEntityNeverEqualsFreedHandle(t *testing.T) {
+	w := NewWorld(1)
+	seen := make(map[Entity]bool)
+	e := w.CreateEntity()
+	for range 100 {
+		seen[e] = true
+		w.RemoveEntity(e)
+		next := w.CreateEntity()
+		if next.ID != e.ID {
+			t.Fatal("expected the freed ID to be reused")
+		}
+		if seen[next] || next.Version <= e.Version {
+			t.Fatalf("reused handle %v collides with or precedes freed %v", next, e)
+		}
+		if w.IsValid(e) {
+			t.Fatal("freed handle reported valid after reuse")
+		}
+		e = next
+	}
+
+	// The version counter skips 0 when it wraps.
+	w.entities.nextEntityVer = ^uint32(0)
+	a := w.CreateEntity()
+	b := w.CreateEntity()
+	if a.Version != ^uint32(0) || b.Version != 1 {
+		t.Fatalf("expected versions MaxUint32 and 1 across the wrap, got %d and %d", a.Version, b.Version)
+	}
+	if w.IsValid(Entity{}) {
+		t.Fatal("the zero Entity must never be valid")
+	}
+}
+
+func TestFilterEmptyAndMatchCount(t *testing.T) {
+	w := NewWorld(4)
+	f := NewFilter2[Position, Velocity](w)
+	if !f.Empty() || f.MatchCount() != 0 {
+		t.Fatal("expected no matching archetype before any entity uses the components")
+	}
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{}, Velocity{})
+	SetComponent3(w, w.CreateEntity(), Position{}, Velocity{}, Health{})
+	if f.Empty() || f.MatchCount() != 2 {
+		t.Fatalf("expected 2 matching archetypes, got %d", f.MatchCount())
+	}
+	w.ClearEntities()
+	if f.Empty() || f.Count() != 0 {
+		t.Error("expected archetypes to keep matching once emptied")
+	}
+}
+
+func TestShortComponentNames(t *testing.T) {
+	w := NewWorld(2)
+	e := w.CreateEntity()
+	Set2(w, e, Position{X: 1}, Velocity{DX: 2})
+	Set(w, e, Health{HP: 3})
+	if p, v := Get2[Position, Velocity](w, e); p.X != 1 || v.DX != 2 {
+		t.Fatalf("Get2: unexpected components %+v %+v", *p, *v)
+	}
+	if h := Get[Health](w, e); h == nil || h.HP != 3 {
+		t.Fatal("Get: expected Health")
+	}
+	Remove2[Position, Velocity](w, e)
+	Remove[Health](w, e)
+	if Get[Position](w, e) != nil || Get[Health](w, e) != nil {
+		t.Error("expected Remove and Remove2 to drop the components")
+	}
+}
+
+func TestFilterTake(t *testing.T) {
+	w := NewWorld(8)
+	for i := range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{})
+	}
+	for i := range 3 {
+		SetComponent3(w, w.CreateEntity(), Position{X: float32(3 + i)}, Velocity{}, Health{})
+	}
+	f := NewFilter2[Position, Velocity](w)
+	all := f.Entities()
+
+	for _, n := range []int{0, 2, 4, 10} {
+		f.Take(n)
+		var got []Entity
+		for f.Next() {
+			got = append(got, f.Entity())
+		}
+		want := all[:min(n, len(all))]
+		if !slices.Equal(got, want) {
+			t.Errorf("Take(%d): expected %v, got %v", n, want, got)
+		}
+		if te := f.TakeEntities(n); !slices.Equal(te, want) {
+			t.Errorf("TakeEntities(%d): expected %v, got %v", n, want, te)
+		}
+	}
+
+	f.Reset()
+	count := 0
+	for f.Next() {
+		count++
+	}
+	if count != 6 {
+		t.Errorf("expected Reset to clear the limit, got %d entities", count)
+	}
+
+	single := NewFilter[Position](w)
+	single.Take(1)
+	if !single.Next() || single.Next() {
+		t.Error("expected Filter.Take(1) to yield exactly one entity")
+	}
+
+	head := f.TakeEntities(1)
+	_ = append(head, Entity{})
+	if f.Entities()[1] != all[1] {
+		t.Error("appending to TakeEntities must not overwrite the filter's cache")
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	w := NewWorld(8)
+	for i := range 3 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Velocity{DX: 1})
+	}
+	e := w.CreateEntity()
+	SetComponent3(w, e, Position{X: 10}, Velocity{DX: 2}, Health{})
+
+	f2 := NewFilter2[Position, Velocity](w)
+	f2.Apply(func(p *Position, v *Velocity) {
+		p.X += v.DX
+	})
+	sum := float32(0)
+	NewFilter[Position](w).Apply(func(p *Position) {
+		sum += p.X
+	})
+	if sum != 1+2+3+12 {
+		t.Fatalf("expected Apply to update every entity, got sum %v", sum)
+	}
+	n := 0
+	NewFilter3[Position, Velocity, Health](w).Apply(func(*Position, *Velocity, *Health) {
+		n++
+	})
+	if n != 1 {
+		t.Fatalf("expected Apply3 to visit 1 entity, got %d", n)
+	}
+}
+
+func TestDeterministicIDs(t *testing.T) {
+	run := func() []Entity {
+		w := NewWorld(4, WithDeterministicIDs())
+		var out []Entity
+		var live []Entity
+		for i := range 12 {
+			live = append(live, w.CreateEntity())
+			if i%3 == 2 {
+				w.RemoveEntity(live[i/2])
+			}
+		}
+		out = append(out, live...)
+		NewBuilder[Position](w).NewEntities(5)
+		out = append(out, NewFilter[Position](w).Entities()...)
+		return out
+	}
+	a, b := run(), run()
+	if !slices.Equal(a, b) {
+		t.Fatalf("expected identical handles across runs:\n%v\n%v", a, b)
+	}
+
+	w := NewWorld(8, WithDeterministicIDs())
+	ents := make([]Entity, 6)
+	for i := range ents {
+		ents[i] = w.CreateEntity()
+		if ents[i].ID != uint32(i) {
+			t.Fatalf("expected ID %d, got %d", i, ents[i].ID)
+		}
+	}
+	w.RemoveEntity(ents[4])
+	w.RemoveEntity(ents[1])
+	w.RemoveEntity(ents[3])
+	for _, want := range []uint32{1, 3, 4, 6} {
+		if got := w.CreateEntity().ID; got != want {
+			t.Fatalf("expected the lowest free ID %d, got %d", want, got)
+		}
+	}
+	// Growth keeps handing out the lowest IDs.
+	for want := uint32(7); want < 20; want++ {
+		if got := w.CreateEntity().ID; got != want {
+			t.Fatalf("expected ID %d after growth, got %d", want, got)
+		}
+	}
+}
+
+func TestArchetypeTransitionCache(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{X: 1})
+	src := w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex]
+	for range 3 {
+		SetComponent(w, e, Velocity{DX: 2})
+		RemoveComponent[Velocity](w, e)
+	}
+	if len(src.edges) != 1 {
+		t.Fatalf("expected 1 cached transition, got %d", len(src.edges))
+	}
+	SetComponent(w, e, Velocity{DX: 3})
+	if got := src.edges[0].to; got != w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex] {
+		t.Fatal("cached transition does not point at the entity's archetype")
+	}
+	if p, v := GetComponent2[Position, Velocity](w, e); p.X != 1 || v.DX != 3 {
+		t.Fatalf("unexpected components after cached moves: %v %v", p, v)
+	}
+	// The cache is bounded.
+	add := []func(Entity){
+		func(e Entity) { SetComponent(w, e, Health{}) },
+		func(e Entity) { SetComponent(w, e, Dummy1{}) },
+		func(e Entity) { SetComponent(w, e, Dummy2{}) },
+		func(e Entity) { SetComponent(w, e, Scale{}) },
+		func(e Entity) { SetComponent(w, e, Inventory{}) },
+		func(e Entity) { SetComponent(w, e, WithPointer{}) },
+		func(e Entity) { SetComponent(w, e, vec4{}) },
+		func(e Entity) { SetComponent(w, e, oddSized{}) },
+		func(e Entity) { SetComponent(w, e, Velocity{}) },
+	}
+	for _, fn := range add {
+		for range 2 {
+			other := w.CreateEntity()
+			SetComponent(w, other, Position{})
+			fn(other)
+		}
+	}
+	if len(src.edges) != maxArchetypeEdges {
+		t.Fatalf("expected %d cached transitions, got %d", maxArchetypeEdges, len(src.edges))
+	}
+}
+
+func TestOnArchetypeCreated(t *testing.T) {
+	w := NewWorld(4)
+	var masks []Mask
+	var types [][]reflect.Type
+	w.OnArchetypeCreated(func(m Mask, ts []reflect.Type) {
+		masks = append(masks, m)
+		types = append(types, ts)
+	})
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{})
+	SetComponent(w, e, Velocity{})
+	RemoveComponent[Velocity](w, e)
+	NewBuilder2[Position, Velocity](w).NewEntity()
+	if len(masks) != 2 {
+		t.Fatalf("expected 2 new archetypes, got %d", len(masks))
+	}
+	if len(types[0]) != 1 || types[0][0] != reflect.TypeFor[Position]() {
+		t.Fatalf("unexpected types for the first archetype: %v", types[0])
+	}
+	if len(types[1]) != 2 || !slices.Contains(types[1], reflect.TypeFor[Velocity]()) {
+		t.Fatalf("unexpected types for the second archetype: %v", types[1])
+	}
+	if _, ok := w.ArchetypeGraph()[masks[1]]; !ok {
+		t.Fatal("reported mask does not match a world archetype")
+	}
+	w.OnArchetypeCreated(nil)
+	SetComponent(w, e, Health{})
+	if len(masks) != 2 {
+		t.Fatal("callback fired after being removed")
+	}
+}
+
+type gravity struct{ G float32 }
+
+func TestEachWithResource(t *testing.T) {
+	w := NewWorld(8)
+	for i := range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{Y: float32(i)}, Velocity{})
+	}
+	f := NewFilter2[Position, Velocity](w)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing resource")
+		}
+	}()
+	w.Resources().Add(&gravity{G: -9})
+	EachWithResource2(f, func(g *gravity, e Entity, p *Position, v *Velocity) {
+		v.DY += g.G
+		p.Y += v.DY
+	})
+	sum := float32(0)
+	EachWithResource(NewFilter[Position](w), func(g *gravity, e Entity, p *Position) {
+		sum += p.Y
+	})
+	if sum != 0+1+2-27 {
+		t.Errorf("unexpected sum %v", sum)
+	}
+	EachWithResource2(f, func(*Health, Entity, *Position, *Velocity) {})
+}
+
+func TestFilterResetUnlocked(t *testing.T) {
+	w := NewWorld(8)
+	f := NewFilter2[Position, Velocity](w)
+	for range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{}, Velocity{DX: 1})
+	}
+	for range 2 {
+		f.ResetUnlocked()
+		for f.Next() {
+			p, v := f.Get()
+			p.X += v.DX
+		}
+	}
+	f1 := NewFilter[Position](w)
+	f1.ResetUnlocked()
+	n := 0
+	for f1.Next() {
+		if f1.Get().X != 2 {
+			t.Errorf("expected X == 2, got %v", f1.Get().X)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("expected 3 entities, got %d", n)
+	}
+}
+
+func TestFilterBySize(t *testing.T) {
+	w := NewWorld(32)
+	spawn := func(n int, extra func(Entity)) {
+		for range n {
+			e := w.CreateEntity()
+			SetComponent2(w, e, Position{}, Velocity{})
+			extra(e)
+		}
+	}
+	spawn(1, func(Entity) {})
+	spawn(5, func(e Entity) { SetComponent(w, e, Health{}) })
+	spawn(3, func(e Entity) { SetComponent(w, e, Scale{}) })
+	f := NewFilter2[Position, Velocity](w)
+	f.BySize()
+	sizes := func() []int {
+		var got []int
+		for _, a := range f.matchingArches {
+			got = append(got, a.size)
+		}
+		return got
+	}
+	f.Reset()
+	if got := sizes(); !slices.Equal(got, []int{5, 3, 1}) {
+		t.Fatalf("expected archetypes largest first, got sizes %v", got)
+	}
+	if other := NewFilter2[Position, Velocity](w); other.MatchCount() != 3 || other.matchingArches[0].size != 1 {
+		t.Error("BySize reordered the archetype list shared with other filters")
+	}
+	spawn(4, func(Entity) {})
+	f.Reset()
+	if got := sizes(); !slices.Equal(got, []int{5, 5, 3}) {
+		t.Fatalf("expected the order to follow the new sizes, got %v", got)
+	}
+	n := 0
+	for f.Next() {
+		n++
+	}
+	if n != 13 {
+		t.Errorf("expected 13 entities, got %d", n)
+	}
+}
+
+func TestFilterRefreshBeforeStartTakesNoLock(t *testing.T) {
+	w := NewWorld(8)
+	f := NewFilter2[Position, Velocity](w)
+	f.BySize()
+	SetComponent2(w, w.CreateEntity(), Position{}, Velocity{})
+	for range 3 {
+		SetComponent3(w, w.CreateEntity(), Position{}, Velocity{}, Health{})
+	}
+	n := 0
+	w.Mutate(func(*Txn) { // holds the write lock
+		for f.Next() {
+			n++
+		}
+	})
+	if n != 4 {
+		t.Errorf("expected 4 entities, got %d", n)
+	}
+	if f.matchingArches[0].size != 3 {
+		t.Error("expected the refreshed archetypes to be sorted by size")
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	w := NewWorld(8)
+	for i := range 4 {
+		SetComponent2(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{DX: 10})
+	}
+	SetComponent(w, w.CreateEntity(), Position{X: 100})
+	sums := Map2(NewFilter2[Position, Velocity](w), func(p *Position, v *Velocity) float32 {
+		return p.X + v.DX
+	})
+	slices.Sort(sums)
+	if !slices.Equal(sums, []float32{10, 11, 12, 13}) {
+		t.Errorf("unexpected Map2 result %v", sums)
+	}
+	if xs := Map(NewFilter[Position](w), func(p *Position) float32 { return p.X }); len(xs) != 5 || cap(xs) != 5 {
+		t.Errorf("expected 5 preallocated results, got len %d cap %d", len(xs), cap(xs))
+	}
+	if res := Map(NewFilter[Health](w), func(*Health) int { return 1 }); len(res) != 0 {
+		t.Errorf("expected no result, got %v", res)
+	}
+}
+
+func TestFilterEachMut(t *testing.T) {
+	w := NewWorld(16)
+	for i := range 10 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Health{HP: i})
+	}
+	f := NewFilter2[Position, Health](w)
+	visited := 0
+	f.EachMut(func(e Entity, p *Position, h *Health) Action {
+		visited++
+		p.X++
+		if h.HP%2 == 0 {
+			return ActionRemove
+		}
+		return ActionKeep
+	})
+	if visited != 10 {
+		t.Fatalf("expected 10 visits, got %d", visited)
+	}
+	if n := f.Count(); n != 5 {
+		t.Fatalf("expected 5 entities left, got %d", n)
+	}
+	f.Reset()
+	for f.Next() {
+		p, h := f.Get()
+		if h.HP%2 == 0 || p.X != float32(h.HP+1) {
+			t.Errorf("unexpected survivor %v %v", p, h)
+		}
+	}
+
+	NewFilter[Health](w).EachMut(func(e Entity, h *Health) Action {
+		if h.HP > 5 {
+			return ActionRemove
+		}
+		return ActionKeep
+	})
+	if n := f.Count(); n != 3 {
+		t.Errorf("expected 3 entities left, got %d", n)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterEachSafe(t *testing.T) {
+	w := NewWorld(8)
+	var ents []Entity
+	for i := range 6 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: float32(i)}, Velocity{})
+		ents = append(ents, e)
+	}
+	f := NewFilter2[Position, Velocity](w)
+	visited := 0
+	f.EachSafe(func(e Entity, p *Position, v *Velocity) {
+		visited++
+		v.DX = p.X
+		switch e {
+		case ents[0]:
+			// Remove the current entity and a later one, and strip another.
+			w.RemoveEntity(e)
+			w.RemoveEntity(ents[5])
+			RemoveComponent[Velocity](w, ents[4])
+		case ents[1]:
+			SetComponent(w, w.CreateEntity(), Position{})
+			SetComponent(w, e, Health{})
+		}
+	})
+	if visited != 4 {
+		t.Fatalf("expected 4 visited entities, got %d", visited)
+	}
+	if v := GetComponent[Velocity](w, ents[3]); v == nil || v.DX != 3 {
+		t.Fatal("expected EachSafe to write through the pointers")
+	}
+	if f.Count() != 3 {
+		t.Fatalf("expected 3 remaining matches, got %d", f.Count())
+	}
+
+	n := 0
+	NewFilter[Position](w).EachSafe(func(e Entity, _ *Position) {
+		w.RemoveEntity(e)
+		n++
+	})
+	if n != 5 || NewFilter[Position](w).Count() != 0 {
+		t.Fatalf("expected to remove all 5 Position entities, removed %d", n)
+	}
+}
+
+func TestComponentInfo(t *testing.T) {
+	w := NewWorld(1)
+	if _, size, ok := w.ComponentInfo(reflect.TypeFor[Position]()); ok || size != unsafe.Sizeof(Position{}) {
+		t.Fatal("expected Position to be unregistered before use")
+	}
+	SetComponent2(w, w.CreateEntity(), Velocity{}, Position{})
+	id, size, ok := w.ComponentInfo(reflect.TypeFor[Position]())
+	if !ok || size != unsafe.Sizeof(Position{}) {
+		t.Fatalf("unexpected info for Position: %d %d %v", id, size, ok)
+	}
+	types := w.RegisteredComponents()
+	if len(types) != 2 || types[id] != reflect.TypeFor[Position]() {
+		t.Fatalf("unexpected registered components %v", types)
+	}
+	if _, _, ok := w.ComponentInfo(reflect.TypeFor[Health]()); ok {
+		t.Fatal("ComponentInfo must not register types")
+	}
+	if len(w.RegisteredComponents()) != 2 {
+		t.Fatal("expected the registry to be unchanged by lookups")
+	}
+}
+
+func TestComponentsOf(t *testing.T) {
+	w := NewWorld(4)
+	RegisterTransient[Scale](w)
+	e := w.CreateEntity()
+	if types := w.ComponentsOf(e); len(types) != 0 {
+		t.Errorf("expected no component, got %v", types)
+	}
+	SetComponent2(w, e, Position{}, Velocity{})
+	SetComponent(w, e, Scale{})
+	want := []reflect.Type{reflect.TypeFor[Position](), reflect.TypeFor[Velocity](), reflect.TypeFor[Scale]()}
+	if types := w.ComponentsOf(e); !slices.Equal(types, want) {
+		t.Errorf("expected %v, got %v", want, types)
+	}
+	w.RemoveEntity(e)
+	if types := w.ComponentsOf(e); types != nil {
+		t.Errorf("expected nil for a removed entity, got %v", types)
+	}
+}
+
+func TestWorldDiff(t *testing.T) {
+	w := NewWorld(4)
+	a := w.CreateEntity()
+	SetComponent2(w, a, Position{}, Velocity{})
+	b := w.CreateEntity()
+	SetComponent2(w, b, Position{}, Health{})
+
+	onlyA, onlyB, common := w.Diff(a, b)
+	pos, vel, hp := reflect.TypeFor[Position](), reflect.TypeFor[Velocity](), reflect.TypeFor[Health]()
+	if !slices.Equal(onlyA, []reflect.Type{vel}) || !slices.Equal(onlyB, []reflect.Type{hp}) || !slices.Equal(common, []reflect.Type{pos}) {
+		t.Fatalf("unexpected diff: onlyA %v, onlyB %v, common %v", onlyA, onlyB, common)
+	}
+
+	w.RemoveEntity(b)
+	onlyA, onlyB, common = w.Diff(a, b)
+	if len(onlyA) != 2 || onlyB != nil || common != nil {
+		t.Errorf("expected an invalid entity to have no components, got %v %v %v", onlyA, onlyB, common)
+	}
+}
+
+func TestFilterSetAll(t *testing.T) {
+	w := NewWorld(8)
+	TrackChanges[Velocity](w)
+	for i := range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{DX: float32(i)})
+	}
+	SetComponent3(w, w.CreateEntity(), Position{}, Velocity{}, Health{})
+	lone := w.CreateEntity()
+	SetComponent(w, lone, Position{X: 7})
+	w.ClearChanged()
+
+	version := w.mutationVersion.Load()
+	f := NewFilter2[Position, Velocity](w)
+	f.SetAll(Position{X: 1}, Velocity{DX: 2})
+	if w.mutationVersion.Load() != version {
+		t.Fatal("SetAll must not bump the mutation version")
+	}
+	n := 0
+	for f.Next() {
+		p, v := f.Get()
+		if p.X != 1 || v.DX != 2 {
+			t.Fatalf("unexpected components %v %v", p, v)
+		}
+		if !f.Changed() {
+			t.Fatal("expected SetAll to mark tracked components changed")
+		}
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 entities, got %d", n)
+	}
+	if p := GetComponent[Position](w, lone); p.X != 7 {
+		t.Fatal("SetAll changed a non-matching entity")
+	}
+	NewFilter[Position](w).SetAll(Position{X: 3})
+	if p := GetComponent[Position](w, lone); p.X != 3 {
+		t.Fatal("expected Filter.SetAll to update every Position")
+	}
+}
+
+func TestEnsureCapacity(t *testing.T) {
+	w := NewWorld(4)
+	mask := w.MaskOf(reflect.TypeFor[Position](), reflect.TypeFor[Velocity]())
+	version := w.archetypes.archetypeVersion.Load()
+	w.EnsureCapacity(mask, 100)
+	if w.archetypes.archetypeVersion.Load() == version {
+		t.Fatal("expected EnsureCapacity to create the archetype")
+	}
+	if w.entities.capacity < 100 || len(w.entities.freeIDs) < 100 {
+		t.Fatalf("expected room for 100 entities, capacity %d", w.entities.capacity)
+	}
+	capacity := w.entities.capacity
+	b := NewBuilder2[Position, Velocity](w)
+	for range 100 {
+		b.NewEntity()
+	}
+	if w.entities.capacity != capacity {
+		t.Fatal("creating the reserved entities must not grow the world again")
+	}
+	if _, ok := w.ArchetypeGraph()[mask]; !ok {
+		t.Fatal("MaskOf does not match the builder's archetype")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an unregistered component ID")
+		}
+	}()
+	var bad Mask
+	bad.set(200)
+	w.EnsureCapacity(bad, 1)
+}
+
+func TestFiltersShareMatchSets(t *testing.T) {
+	w := NewWorld(4)
+	SetComponent2(w, w.CreateEntity(), Position{}, Velocity{})
+	f1 := NewFilter2[Position, Velocity](w)
+	f2 := NewFilter2[Position, Velocity](w)
+	exact := NewFilterExact2[Position, Velocity](w)
+	if f1.matchSet == nil || f1.matchSet != f2.matchSet {
+		t.Fatal("expected identical filters to share a match set")
+	}
+	if exact.matchSet == f1.matchSet {
+		t.Fatal("exact and superset filters must not share a match set")
+	}
+	SetComponent3(w, w.CreateEntity(), Position{}, Velocity{}, Health{})
+	f1.Reset()
+	if f1.matchSet.scanned != len(w.archetypes.archetypes) {
+		t.Fatal("expected the shared set to be updated by the first filter")
+	}
+	f2.Reset()
+	if f2.Count() != 2 || exact.Count() != 1 {
+		t.Fatalf("unexpected counts %d and %d", f2.Count(), exact.Count())
+	}
+
+	key := matchKey{mask: f1.mask}
+	ms := f1.matchSet
+	f1.Close()
+	if w.matchSets[key] != ms || ms.refs != 1 {
+		t.Fatal("expected the set to stay alive while another filter uses it")
+	}
+	f2.Close()
+	if _, ok := w.matchSets[key]; ok {
+		t.Fatal("expected the set to be released after the last Close")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic using a closed filter")
+		}
+	}()
+	f1.Reset()
+}
+
+func TestSharedMatchSetConcurrentResets(t *testing.T) {
+	w := NewWorld(16)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 50 {
+				f := NewFilter[Position](w)
+				f.Reset()
+				for f.Next() {
+				}
+			}
+		})
+	}
+	for range 50 {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{}, Velocity{})
+		SetComponent(w, e, Health{})
+	}
+	wg.Wait()
+	if NewFilter[Position](w).Count() != 50 {
+		t.Fatal("unexpected count after concurrent updates")
+	}
+}
+
+func TestEntityPacking(t *testing.T) {
+	w := NewWorld(2)
+	e := w.CreateEntity()
+	packed := e.Packed()
+	if UnpackEntity(packed) != e {
+		t.Fatal("expected UnpackEntity to invert Packed")
+	}
+	if uint64(packed) != uint64(e.ID)<<32|uint64(e.Version) {
+		t.Fatalf("unexpected packing %x", uint64(packed))
+	}
+	if got, ok := w.Lookup(packed); !ok || got != e {
+		t.Fatal("expected Lookup to find the live entity")
+	}
+	w.RemoveEntity(e)
+	reused := w.CreateEntity()
+	if reused.ID != e.ID || reused.Packed() == packed {
+		t.Fatal("expected a reused ID to pack differently")
+	}
+	if _, ok := w.Lookup(packed); ok {
+		t.Fatal("expected Lookup to reject a stale handle")
+	}
+}
+
+type frozenTag struct{}
+
+func TestIsTag(t *testing.T) {
+	w := NewWorld(4)
+	if !w.IsTag(reflect.TypeFor[frozenTag]()) || w.IsTag(reflect.TypeFor[Position]()) {
+		t.Fatal("unexpected IsTag result for unregistered types")
+	}
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{X: 1}, frozenTag{})
+	for range 10 {
+		SetComponent(w, w.CreateEntity(), frozenTag{}) // forces growth
+	}
+	if !w.IsTag(reflect.TypeFor[frozenTag]()) {
+		t.Fatal("expected a registered tag")
+	}
+	id, _ := w.lookupCompTypeID(reflect.TypeFor[frozenTag]())
+	for _, a := range w.archetypes.archetypes {
+		if a.mask.has(id) && a.compPointers[id] != tagColumn {
+			t.Errorf("archetype %d allocated a column for a tag", a.index)
+		}
+	}
+	if GetComponent[frozenTag](w, e) == nil || GetComponent[Position](w, e).X != 1 {
+		t.Error("expected the tagged entity to keep its components")
+	}
+}
+
+func TestWorldHasAll(t *testing.T) {
+	w := NewWorld(8)
+	RegisterTransient[hitFlag](w)
+	both := w.CreateEntity()
+	SetComponent2(w, both, Position{}, Velocity{})
+	SetComponent(w, both, hitFlag{})
+	pos := w.CreateEntity()
+	SetComponent(w, pos, Position{})
+	dead := w.CreateEntity()
+	w.RemoveEntity(dead)
+	ents := []Entity{both, pos, dead}
+
+	check := func(want []bool, types ...reflect.Type) {
+		t.Helper()
+		if got := w.HasAll(ents, types...); !slices.Equal(got, want) {
+			t.Errorf("HasAll(%v) = %v, want %v", types, got, want)
+		}
+	}
+	posT, velT := reflect.TypeFor[Position](), reflect.TypeFor[Velocity]()
+	check([]bool{true, true, false})
+	check([]bool{true, true, false}, posT)
+	check([]bool{true, false, false}, posT, velT)
+	check([]bool{true, false, false}, posT, reflect.TypeFor[hitFlag]())
+	check([]bool{false, false, false}, posT, reflect.TypeFor[Health]())
+}
+
+func TestSetGrowthStrategy(t *testing.T) {
+	w := NewWorld(4)
+	var calls [][2]int
+	w.SetGrowthStrategy(func(current, needed int) int {
+		calls = append(calls, [2]int{current, needed})
+		return current + 10
+	})
+	for range 5 {
+		w.CreateEntity()
+	}
+	if w.entities.capacity != 14 {
+		t.Fatalf("expected linear growth to 14, got %d", w.entities.capacity)
+	}
+	if len(calls) != 1 || calls[0] != [2]int{4, 5} {
+		t.Errorf("unexpected strategy calls %v", calls)
+	}
+	// A result below needed is raised to needed.
+	w.CreateEntities(30)
+	if w.entities.capacity != 35 {
+		t.Errorf("expected capacity 35, got %d", w.entities.capacity)
+	}
+
+	version := w.mutationVersion.Load()
+	w.EnsureCapacity(w.MaskOf(reflect.TypeFor[Position]()), 100)
+	if w.entities.capacity < 135 || w.mutationVersion.Load() == version {
+		t.Errorf("EnsureCapacity should grow and bump the version, got capacity %d", w.entities.capacity)
+	}
+
+	w.SetGrowthStrategy(nil)
+	old := w.entities.capacity
+	w.CreateEntities(len(w.entities.freeIDs) + 1)
+	if w.entities.capacity != old*2 {
+		t.Errorf("expected doubling to %d, got %d", old*2, w.entities.capacity)
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterCreatedBeforeArchetype(t *testing.T) {
+	w := NewWorld(4)
+	f := NewFilter2[Position, Velocity](w)
+	g := NewFilter[Health](w)
+	c := NewFilter[Position](w).Intersect(NewFilter[Velocity](w))
+	// The Health archetype exists but is empty when g is created.
+	h := w.CreateEntity()
+	SetComponent(w, h, Health{})
+	w.RemoveEntity(h)
+	g2 := NewFilter[Health](w)
+
+	for i := range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{})
+	}
+	SetComponent(w, w.CreateEntity(), Health{HP: 1})
+
+	count := func(next func() bool) int {
+		n := 0
+		for next() {
+			n++
+		}
+		return n
+	}
+	// Iterated straight away, without a Reset after the spawns.
+	if n := count(f.Next); n != 3 {
+		t.Errorf("Filter2: expected 3 matches, got %d", n)
+	}
+	if n := count(g.Next); n != 1 {
+		t.Errorf("Filter: expected 1 match, got %d", n)
+	}
+	if n := count(g2.Next); n != 1 {
+		t.Errorf("Filter over an empty archetype: expected 1 match, got %d", n)
+	}
+	if n := count(c.Next); n != 3 {
+		t.Errorf("CompositeFilter: expected 3 matches, got %d", n)
+	}
+	f.Reset()
+	if n := count(f.Next); n != 3 || f.Count() != 3 {
+		t.Errorf("expected 3 matches after Reset, got %d", n)
+	}
+}
@@ -0,0 +1,101 @@
+package teishoku
+
+import "reflect"
+
+// eventBuffer is the type-erased view of an eventQueue used by BeginFrame to
+// discard events nobody polled.
+type eventBuffer interface {
+	reset()
+}
+
+// eventQueue is a growable ring buffer holding the pending events of one type.
+type eventQueue[T any] struct {
+	buf  []T
+	head int
+	n    int
+}
+
+func (q *eventQueue[T]) push(ev T) {
+	if q.n == len(q.buf) {
+		grown := make([]T, max(8, 2*len(q.buf)))
+		q.copyTo(grown)
+		q.buf = grown
+		q.head = 0
+	}
+	q.buf[(q.head+q.n)%len(q.buf)] = ev
+	q.n++
+}
+
+// copyTo copies the queued events, oldest first, to the start of dst.
+func (q *eventQueue[T]) copyTo(dst []T) {
+	k := copy(dst, q.buf[q.head:min(q.head+q.n, len(q.buf))])
+	copy(dst[k:], q.buf[:q.n-k])
+}
+
+func (q *eventQueue[T]) drain() []T {
+	if q.n == 0 {
+		return nil
+	}
+	out := make([]T, q.n)
+	q.copyTo(out)
+	q.reset()
+	return out
+}
+
+func (q *eventQueue[T]) reset() {
+	clear(q.buf)
+	q.head = 0
+	q.n = 0
+}
+
+// Emit queues a one-shot event of type `T` on the world. Events are transient:
+// unlike resources they are not kept around, and unlike components they are
+// not attached to an entity. They stay queued until a system collects them
+// with PollEvents, or until the next BeginFrame discards them.
+//
+// Emit is safe to call concurrently and while filters are being iterated.
+//
+// Parameters:
+//   - w: The World to emit the event on.
+//   - ev: The event value.
+func Emit[T any](w *World, ev T) {
+	w.eventsMu.Lock()
+	defer w.eventsMu.Unlock()
+	t := reflect.TypeFor[T]()
+	q, ok := w.events[t].(*eventQueue[T])
+	if !ok {
+		if w.events == nil {
+			w.events = make(map[reflect.Type]eventBuffer)
+		}
+		q = &eventQueue[T]{}
+		w.events[t] = q
+	}
+	q.push(ev)
+}
+
+// PollEvents removes and returns every queued event of type `T`, in the order
+// they were emitted. Each event is returned by exactly one PollEvents call.
+//
+// Parameters:
+//   - w: The World to poll.
+//
+// Returns:
+//   - The queued events, or nil if there are none.
+func PollEvents[T any](w *World) []T {
+	w.eventsMu.Lock()
+	defer w.eventsMu.Unlock()
+	q, ok := w.events[reflect.TypeFor[T]()].(*eventQueue[T])
+	if !ok {
+		return nil
+	}
+	return q.drain()
+}
+
+// resetEvents discards every queued event, keeping the buffers for reuse.
+func (w *World) resetEvents() {
+	w.eventsMu.Lock()
+	defer w.eventsMu.Unlock()
+	for _, q := range w.events {
+		q.reset()
+	}
+}
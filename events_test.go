@@ -0,0 +1,47 @@
+package teishoku
+
+import (
+	"slices"
+	"testing"
+)
+
+type damageDealt struct {
+	Target Entity
+	Amount int
+}
+
+func TestEmitPollEvents(t *testing.T) {
+	w := NewWorld(1)
+	if PollEvents[damageDealt](w) != nil {
+		t.Fatal("expected no events before any Emit")
+	}
+	var want []damageDealt
+	for i := range 20 {
+		ev := damageDealt{Amount: i}
+		Emit(w, ev)
+		want = append(want, ev)
+		if i == 5 {
+			// Drain part way through and keep emitting, forcing a regrow.
+			if got := PollEvents[damageDealt](w); !slices.Equal(got, want) {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+			want = want[:0]
+		}
+	}
+	Emit(w, "other type")
+	if got := PollEvents[damageDealt](w); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if PollEvents[damageDealt](w) != nil {
+		t.Fatal("expected events to be drained")
+	}
+	if got := PollEvents[string](w); len(got) != 1 || got[0] != "other type" {
+		t.Fatalf("expected the string event, got %v", got)
+	}
+
+	Emit(w, damageDealt{Amount: 1})
+	w.BeginFrame()
+	if PollEvents[damageDealt](w) != nil {
+		t.Fatal("expected BeginFrame to discard unpolled events")
+	}
+}
@@ -1,6 +1,7 @@
 package teishoku
 
 import (
+	"io"
 	"reflect"
 	"unsafe"
 )
@@ -15,8 +16,11 @@ type Filter[T any] struct {
 	queryCache
 	curMatchIdx int // index into matchingArches
 	curIdx      int // index into the current archetype's entity/component array
+	curOffset   int // number of entities in the archetypes already iterated
 	compSize    uintptr
 	curArchSize int
+	snapshot    []Entity // reused by EachSafe
+	limit       int      // total entities to yield after Take, or -1
 	compID      uint8
 }
 
@@ -30,6 +34,25 @@ type Filter[T any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter[T]`.
 func NewFilter[T any](w *World) *Filter[T] {
+	return newFilter[T](w, false)
+}
+
+// NewFilterExact creates a new `Filter` that iterates only over entities
+// whose sole component is `T`: entities carrying any additional component are
+// not matched. This is useful to treat "pure" entities differently from
+// enriched ones, or to assert that nothing added extra components to them.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter[T]`.
+func NewFilterExact[T any](w *World) *Filter[T] {
+	return newFilter[T](w, true)
+}
+
+func newFilter[T any](w *World, exact bool) *Filter[T] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id := w.getCompTypeID(reflect.TypeFor[T]())
@@ -42,6 +65,7 @@ func NewFilter[T any](w *World) *Filter[T] {
 		curIdx:      -1,
 	}
 	f.compSize = w.components.compIDToSize[id]
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -49,9 +73,12 @@ func NewFilter[T any](w *World) *Filter[T] {
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component type, equivalent to calling `NewFilter`.
+// same component type and matching mode, equivalent to calling `NewFilter` (or
+// `NewFilterExact` for an exact filter). The returned filter is bound to `w`;
+// the receiver is left untouched and keeps querying the world it was created
+// for.
 func (f *Filter[T]) New(w *World) *Filter[T] {
-	return NewFilter[T](w)
+	return newFilter[T](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It must be called
@@ -59,17 +86,33 @@ func (f *Filter[T]) New(w *World) *Filter[T] {
 // automatically detect if new archetypes have been created since the last
 // iteration and update its internal list accordingly.
 func (f *Filter[T]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter[T]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter[T]) doReset() {
 	if f.isArchetypeStale() {
 		f.updateMatching()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBase = a.compPointers[f.compID]
@@ -80,10 +123,31 @@ func (f *Filter[T]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter[T]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Example:
 //
 //	query := teishoku.NewFilter[Position](world)
@@ -95,6 +159,9 @@ func (f *Filter[T]) doReset() {
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter[T]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -102,7 +169,18 @@ func (f *Filter[T]) Next() bool {
 }
 
 func (f *Filter[T]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -110,16 +188,34 @@ func (f *Filter[T]) nextArchetype() bool {
 	f.curBase = a.compPointers[f.compID]
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter[T]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter[T]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -129,9 +225,343 @@ func (f *Filter[T]) Entity() Entity {
 // Returns:
 //   - A pointer to the component data (*T).
 func (f *Filter[T]) Get() *T {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		f.world.countRead(f.compID)
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return (*T)(unsafe.Add(f.curBase, uintptr(f.curIdx)*f.compSize))
 }
 
+// GetValues returns a copy of the component of type `T` for the current entity
+// in the iteration. This should only be called after `Next()` has returned true.
+//
+// Unlike Get, the result does not point into archetype storage, so it stays
+// safe to keep after later structural changes. Use it in read-only systems to
+// make that intent explicit and avoid retaining a pointer that may dangle.
+//
+// Returns:
+//   - A copy of the component data (T).
+func (f *Filter[T]) GetValues() T {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T)(unsafe.Add(f.curBase, uintptr(f.curIdx)*f.compSize))
+}
+
+// Apply calls fn with a pointer to the `T` component of every matching
+// entity. It walks the archetype columns directly instead of going through
+// Next and Get, which makes it the fastest way to run a simple system over a
+// filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter[T]) Apply(fn func(*T)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		if a.size == 0 {
+			continue
+		}
+		col := unsafe.Slice((*T)(a.compPointers[f.compID]), a.size)
+		for k := range col {
+			fn(&col[k])
+		}
+	}
+}
+
+// SetAll overwrites the `T` component of every matching entity with val. The
+// columns are written directly, which is much faster than calling
+// SetComponent per entity, e.g. to reset all timers at the start of a frame.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// `T` is tracked (see TrackChanges).
+//
+// Parameters:
+//   - val: The value to store in every matching entity.
+func (f *Filter[T]) SetAll(val T) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		if a.size == 0 {
+			continue
+		}
+		col := unsafe.Slice((*T)(a.compPointers[f.compID]), a.size)
+		for k := range col {
+			col[k] = val
+		}
+		if w.changes[f.compID] != nil || w.versions[f.compID] != nil {
+			for _, e := range a.entityIDs[:a.size] {
+				w.markChanged(f.compID, e.ID)
+			}
+		}
+	}
+}
+
+// Action tells EachMut what to do with the entity it just visited.
+type Action uint8
+
+const (
+	// ActionKeep leaves the entity in the world.
+	ActionKeep Action = iota
+	// ActionRemove removes the entity once the iteration is complete.
+	ActionRemove
+)
+
+// EachMut calls fn with a pointer to the `T` component of every matching
+// entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter[T]) EachMut(fn func(Entity, *T) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col := unsafe.Slice((*T)(a.compPointers[f.compID]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its `T`
+// component. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource[R, T any](f *Filter[T], fn func(*R, Entity, *T)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col := unsafe.Slice((*T)(a.compPointers[f.compID]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col[k])
+		}
+	}
+}
+
+// Page is a page of the entities matched by a Filter, created by
+// Filter.Page. It records the archetype row ranges covering the page, so it
+// can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page[T any] struct {
+	pageCursor
+	id uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter[T]) Page(offset, limit int) Page[T] {
+	return Page[T]{pageCursor: f.newPageCursor(offset, limit), id: f.compID}
+}
+
+// Get returns a pointer to the `T` component of the current entity of the
+// page.
+func (p *Page[T]) Get() *T {
+	a, i := p.row()
+	return (*T)(unsafe.Add(a.compPointers[p.id], i*a.compSizes[p.id]))
+}
+
+// Map calls fn with a pointer to the `T` component of every entity matched by
+// f and collects the results, in iteration order, e.g. to extract every enemy
+// position for a minimap. The result is preallocated with the filter's count.
+// It allocates on every call, so it suits tooling and one-off computations
+// better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map[T, R any](f *Filter[T], fn func(*T) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c *T) {
+		res = append(res, fn(c))
+	})
+	return res
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost `T` before being visited are
+// skipped. The pointer passed to fn is only valid until fn makes a structural
+// change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter[T]) EachSafe(fn func(Entity, *T)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		var c *T
+		if w.IsValidNoLock(e) {
+			meta := w.entities.metas[e.ID]
+			a := w.archetypes.archetypes[meta.archetypeIndex]
+			if a.mask.has(f.compID) {
+				c = (*T)(unsafe.Add(a.compPointers[f.compID], uintptr(meta.index)*f.compSize))
+			}
+		}
+		w.mu.RUnlock()
+		if c != nil {
+			fn(e, c)
+		}
+	}
+	f.snapshot = ents[:0]
+}
+
+// Version returns the write counter of the current entity's `T`, or 0 if
+// versions of `T` are not tracked (see TrackVersions). This should only be
+// called after `Next()` has returned true.
+func (f *Filter[T]) Version() uint32 {
+	return f.world.componentVersion(f.compID, f.curEntityIDs[f.curIdx].ID)
+}
+
+// MarkChanged flags the current entity's `T` as changed, for systems that
+// modified it in place through Get. It does nothing unless changes of `T` are
+// tracked (see TrackChanges). This should only be called after `Next()` has
+// returned true.
+func (f *Filter[T]) MarkChanged() {
+	f.world.markChanged(f.compID, f.curEntityIDs[f.curIdx].ID)
+}
+
+// Changed reports whether the current entity's `T` changed since the last
+// World.ClearChanged. It is always false unless changes of `T` are tracked.
+//
+// Returns:
+//   - true if the component is flagged as changed, false otherwise.
+func (f *Filter[T]) Changed() bool {
+	return f.world.isChanged(f.compID, f.curEntityIDs[f.curIdx].ID)
+}
+
+// NextChanged advances the filter to the next matching entity whose `T`
+// changed since the last World.ClearChanged, skipping the others. It is used
+// in place of Next by reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter[T]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether `T` was added to the current entity during the
+// previous frame, as delimited by World.BeginFrame. It is always false unless
+// changes of `T` are tracked (see TrackChanges).
+//
+// Returns:
+//   - true if the component was recently added, false otherwise.
+func (f *Filter[T]) Added() bool {
+	return f.world.isAdded(f.compID, f.curEntityIDs[f.curIdx].ID)
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter[T]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory, making it highly
@@ -139,6 +569,7 @@ func (f *Filter[T]) Get() *T {
 //
 // After this operation, the filter will be empty.
 func (f *Filter[T]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -147,18 +578,39 @@ func (f *Filter[T]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and a pointer to its `T` component, e.g. to dump the world's state
+// while debugging or to write a simple save file. It resets the filter and
+// iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter[T]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 1)
+	f.Reset()
+	for f.Next() {
+		comps[0] = f.Get()
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns a slice containing all entities that match the filter's
 // query. This method retrieves a cached list of entities, which is updated only
 // when the filter is reset or detects that the world's archetypes have changed.
@@ -182,6 +634,7 @@ type Query[T any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSize       uintptr
 	curArchSize    int
 	compID         uint8
@@ -189,6 +642,7 @@ type Query[T any] struct {
 
 // Query creates a new Query iterator from the Filter.
 func (f *Filter[T]) Query() Query[T] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -222,7 +676,14 @@ func (q *Query[T]) Next() bool {
 }
 
 func (q *Query[T]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -234,13 +695,102 @@ func (q *Query[T]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query[T]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+//
+// Together with Remaining and the filter's Count, Skip allows a large query to
+// be processed in slices, e.g. a fixed budget of entities per frame.
+//
+// Parameters:
+//   - n: The number of entities to skip.
+func (q *Query[T]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as the index of the current
+// archetype among the matching ones and the entity's index inside it. Unlike
+// Index, the position can be handed to SeekTo to resume iteration exactly
+// there, e.g. in the next frame or on another goroutine working on a range.
+//
+// A cursor is only valid while the world's archetypes and entities are
+// unchanged: any structural change (creating, removing or moving entities)
+// invalidates it.
+func (q *Query[T]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to the position returned by an earlier Cursor call:
+// afterwards Entity and Get refer to element elemIdx of the archetype archIdx,
+// and Next continues with the element after it. Pass elemIdx -1 to position
+// the query just before the archetype's first element. An archIdx past the
+// last archetype exhausts the query.
+//
+// Like Cursor, positions are only meaningful while the world sees no
+// structural change.
+func (q *Query[T]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBase = a.compPointers[q.compID]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query[T]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query[T]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns a pointer to the component T for the current entity.
 func (q *Query[T]) Get() *T {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T)(unsafe.Add(q.curBase, uintptr(q.curIdx)*q.compSize))
 }
 
@@ -251,6 +801,7 @@ type Filter0 struct {
 	queryCache
 	curMatchIdx int // index into matchingArches
 	curIdx      int // index into the current archetype's entity/component array
+	curOffset   int // number of entities in the archetypes already iterated
 	curArchSize int
 }
 
@@ -263,6 +814,7 @@ type Filter0 struct {
 // Returns:
 //   - A pointer to the newly created `Filter0`.
 func NewFilter0(w *World) *Filter0 {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	var m bitmask256
@@ -277,8 +829,9 @@ func NewFilter0(w *World) *Filter0 {
 	return f
 }
 
-// New is a convenience method that constructs a new `Filter` instance for the
-// same component type, equivalent to calling `NewFilter`.
+// New is a convenience method that constructs a new `Filter0` instance,
+// equivalent to calling `NewFilter0`. The returned filter is bound to `w`; the
+// receiver is left untouched and keeps querying the world it was created for.
 func (f *Filter0) New(w *World) *Filter0 {
 	return NewFilter0(w)
 }
@@ -288,6 +841,7 @@ func (f *Filter0) New(w *World) *Filter0 {
 // automatically detect if new archetypes have been created since the last
 // iteration and update its internal list accordingly.
 func (f *Filter0) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
@@ -297,8 +851,10 @@ func (f *Filter0) doReset() {
 	if f.isArchetypeStale() {
 		f.updateMatching()
 	}
+	f.sortBySize()
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curEntityIDs = a.entityIDs
@@ -323,6 +879,9 @@ func (f *Filter0) doReset() {
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter0) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -330,7 +889,15 @@ func (f *Filter0) Next() bool {
 }
 
 func (f *Filter0) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -341,12 +908,27 @@ func (f *Filter0) nextArchetype() bool {
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter0) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter0) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -357,6 +939,7 @@ func (f *Filter0) Entity() Entity {
 //
 // After this operation, the filter will be empty.
 func (f *Filter0) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -365,14 +948,12 @@ func (f *Filter0) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
@@ -398,11 +979,13 @@ type Query0 struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	curArchSize    int
 }
 
 // Query returns a new Query0 iterator from the Filter0.
 func (f *Filter0) Query() Query0 {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -433,7 +1016,14 @@ func (q *Query0) Next() bool {
 }
 
 func (q *Query0) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -444,7 +1034,80 @@ func (q *Query0) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query0) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+//
+// Together with Remaining and the filter's Count, Skip allows a large query to
+// be processed in slices, e.g. a fixed budget of entities per frame.
+//
+// Parameters:
+//   - n: The number of entities to skip.
+func (q *Query0) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query0) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query0) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query0) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query0) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
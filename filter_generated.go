@@ -1,6 +1,7 @@
 package teishoku
 
 import (
+	"io"
 	"reflect"
 	"unsafe"
 )
@@ -13,8 +14,11 @@ type Filter2[T1 any, T2 any] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [2]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [2]uint8
 }
 
@@ -27,6 +31,23 @@ type Filter2[T1 any, T2 any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter2`.
 func NewFilter2[T1 any, T2 any](w *World) *Filter2[T1, T2] {
+	return newFilter2[T1, T2](w, false)
+}
+
+// NewFilterExact2 creates a new `Filter` that iterates only over entities
+// whose components are exactly T1, T2, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter2`.
+func NewFilterExact2[T1 any, T2 any](w *World) *Filter2[T1, T2] {
+	return newFilter2[T1, T2](w, true)
+}
+
+func newFilter2[T1 any, T2 any](w *World, exact bool) *Filter2[T1, T2] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
@@ -48,6 +69,7 @@ func NewFilter2[T1 any, T2 any](w *World) *Filter2[T1, T2] {
 	f.compSizes[0] = w.components.compIDToSize[id1]
 	f.compSizes[1] = w.components.compIDToSize[id2]
 	
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -55,26 +77,44 @@ func NewFilter2[T1 any, T2 any](w *World) *Filter2[T1, T2] {
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter2`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter2` (or `NewFilterExact2`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter2[T1, T2]) New(w *World) *Filter2[T1, T2] {
-	return NewFilter2[T1, T2](w)
+	return newFilter2[T1, T2](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter2[T1, T2]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter2[T1, T2]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter2[T1, T2]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBases[0] = a.compPointers[f.ids[0]]
@@ -87,14 +127,38 @@ func (f *Filter2[T1, T2]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter2[T1, T2]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter2[T1, T2]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -102,7 +166,18 @@ func (f *Filter2[T1, T2]) Next() bool {
 }
 
 func (f *Filter2[T1, T2]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -112,16 +187,34 @@ func (f *Filter2[T1, T2]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter2[T1, T2]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter2[T1, T2]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -132,14 +225,374 @@ func (f *Filter2[T1, T2]) Entity() Entity {
 // Returns:
 //   - Pointers to the component data (*T1, *T2).
 func (f *Filter2[T1, T2]) Get() (*T1, *T2) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
 		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1]))
 }
 
+// GetValues returns copies of the 2 components (T1, T2) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data (T1, T2).
+func (f *Filter2[T1, T2]) GetValues() (T1, T2) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		*(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1]))
+}
+
+// Apply calls fn with pointers to the components T1, T2 of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter2[T1, T2]) Apply(fn func(*T1, *T2)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range n {
+			fn(&col1[k], &col2[k])
+		}
+	}
+}
+
+// EachMut calls fn with pointers to the components T1, T2 of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter2[T1, T2]) EachMut(fn func(Entity, *T1, *T2) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+			col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col1[k], &col2[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource2 calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// T1, T2. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource2[R any, T1 any, T2 any](f *Filter2[T1, T2], fn func(*R, Entity, *T1, *T2)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource2 requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col1[k], &col2[k])
+		}
+	}
+}
+
+// Page2 is a page of the entities matched by a Filter2, created by
+// Filter2.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page2[T1 any, T2 any] struct {
+	pageCursor
+	ids [2]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter2[T1, T2]) Page(offset, limit int) Page2[T1, T2] {
+	return Page2[T1, T2]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to T1, T2 for the current entity of the page.
+func (p *Page2[T1, T2]) Get() (*T1, *T2) {
+	a, i := p.row()
+	return (*T1)(unsafe.Add(a.compPointers[p.ids[0]], i*a.compSizes[p.ids[0]])),
+		(*T2)(unsafe.Add(a.compPointers[p.ids[1]], i*a.compSizes[p.ids[1]]))
+}
+
+// Map2 calls fn with pointers to the components T1, T2 of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map2[T1 any, T2 any, R any](f *Filter2[T1, T2], fn func(*T1, *T2) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c1 *T1, c2 *T2) {
+		res = append(res, fn(c1, c2))
+	})
+	return res
+}
+
+// SetAll overwrites the components T1, T2 of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent2 per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+//   - v1: The value to store in every matching entity's T1.
+//   - v2: The value to store in every matching entity's T2.
+func (f *Filter2[T1, T2]) SetAll(v1 T1, v2 T2) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		for k := range col1 {
+			col1[k] = v1
+		}
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range col2 {
+			col2[k] = v2
+		}
+		for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of T1, T2 before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter2[T1, T2]) EachSafe(fn func(Entity, *T1, *T2)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if !a.mask.has(f.ids[0]) || !a.mask.has(f.ids[1]) {
+			w.mu.RUnlock()
+			continue
+		}
+		c1 := (*T1)(unsafe.Add(a.compPointers[f.ids[0]], uintptr(meta.index)*f.compSizes[0]))
+		c2 := (*T2)(unsafe.Add(a.compPointers[f.ids[1]], uintptr(meta.index)*f.compSizes[1]))
+		w.mu.RUnlock()
+		fn(e, c1, c2)
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// T1, T2, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter2[T1, T2]) Versions() (v1, v2 uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return f.world.componentVersion(f.ids[0], id), f.world.componentVersion(f.ids[1], id)
+}
+
+// MarkChanged flags the current entity's tracked components among
+// T1, T2 as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter2[T1, T2]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among T1, T2 of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter2[T1, T2]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter2[T1, T2]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among T1, T2 was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter2[T1, T2]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter2[T1, T2]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory.
 func (f *Filter2[T1, T2]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -148,18 +601,41 @@ func (f *Filter2[T1, T2]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its T1, T2 components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter2[T1, T2]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 2)
+	f.Reset()
+	for f.Next() {
+		p1, p2 := f.Get()
+		comps[0] = p1
+		comps[1] = p2
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns all entities that match the filter.
 func (f *Filter2[T1, T2]) Entities() []Entity {
 	return f.queryCache.Entities()
@@ -172,6 +648,7 @@ type Query2[T1 any, T2 any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [2]uintptr
 	curArchSize    int
 	ids            [2]uint8
@@ -179,6 +656,7 @@ type Query2[T1 any, T2 any] struct {
 
 // Query returns a new Query2 iterator from the Filter2.
 func (f *Filter2[T1, T2]) Query() Query2[T1, T2] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -193,8 +671,8 @@ func (f *Filter2[T1, T2]) Query() Query2[T1, T2] {
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		f.curBases[0] = a.compPointers[f.ids[0]]
-		f.curBases[1] = a.compPointers[f.ids[1]]
+		q.curBases[0] = a.compPointers[q.ids[0]]
+		q.curBases[1] = a.compPointers[q.ids[1]]
 		
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -216,7 +694,14 @@ func (q *Query2[T1, T2]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query2[T1, T2]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -230,13 +715,85 @@ func (q *Query2[T1, T2]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query2[T1, T2]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query2[T1, T2]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query2[T1, T2]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query2[T1, T2]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBases[0] = a.compPointers[q.ids[0]]
+	q.curBases[1] = a.compPointers[q.ids[1]]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query2[T1, T2]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query2[T1, T2]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to T1, T2 for the current entity.
 func (q *Query2[T1, T2]) Get() (*T1, *T2) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T1)(unsafe.Add(q.curBases[0], uintptr(q.curIdx)*q.compSizes[0])),
 		(*T2)(unsafe.Add(q.curBases[1], uintptr(q.curIdx)*q.compSizes[1]))
 }
@@ -249,8 +806,11 @@ type Filter3[T1 any, T2 any, T3 any] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [3]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [3]uint8
 }
 
@@ -263,6 +823,23 @@ type Filter3[T1 any, T2 any, T3 any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter3`.
 func NewFilter3[T1 any, T2 any, T3 any](w *World) *Filter3[T1, T2, T3] {
+	return newFilter3[T1, T2, T3](w, false)
+}
+
+// NewFilterExact3 creates a new `Filter` that iterates only over entities
+// whose components are exactly T1, T2, T3, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter3`.
+func NewFilterExact3[T1 any, T2 any, T3 any](w *World) *Filter3[T1, T2, T3] {
+	return newFilter3[T1, T2, T3](w, true)
+}
+
+func newFilter3[T1 any, T2 any, T3 any](w *World, exact bool) *Filter3[T1, T2, T3] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
@@ -287,6 +864,7 @@ func NewFilter3[T1 any, T2 any, T3 any](w *World) *Filter3[T1, T2, T3] {
 	f.compSizes[1] = w.components.compIDToSize[id2]
 	f.compSizes[2] = w.components.compIDToSize[id3]
 	
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -294,26 +872,44 @@ func NewFilter3[T1 any, T2 any, T3 any](w *World) *Filter3[T1, T2, T3] {
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter3`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter3` (or `NewFilterExact3`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter3[T1, T2, T3]) New(w *World) *Filter3[T1, T2, T3] {
-	return NewFilter3[T1, T2, T3](w)
+	return newFilter3[T1, T2, T3](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter3[T1, T2, T3]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter3[T1, T2, T3]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter3[T1, T2, T3]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBases[0] = a.compPointers[f.ids[0]]
@@ -327,14 +923,38 @@ func (f *Filter3[T1, T2, T3]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter3[T1, T2, T3]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter3[T1, T2, T3]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -342,7 +962,18 @@ func (f *Filter3[T1, T2, T3]) Next() bool {
 }
 
 func (f *Filter3[T1, T2, T3]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -353,16 +984,34 @@ func (f *Filter3[T1, T2, T3]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter3[T1, T2, T3]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter3[T1, T2, T3]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -373,39 +1022,434 @@ func (f *Filter3[T1, T2, T3]) Entity() Entity {
 // Returns:
 //   - Pointers to the component data (*T1, *T2, *T3).
 func (f *Filter3[T1, T2, T3]) Get() (*T1, *T2, *T3) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
 		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
 		(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2]))
 }
 
-// RemoveEntities efficiently removes all entities that match the filter's
-// query. This operation is performed in a batch, invalidating all matching
-// entities and recycling their IDs without moving any memory.
-func (f *Filter3[T1, T2, T3]) RemoveEntities() {
-	f.world.mu.Lock()
-	defer f.world.mu.Unlock()
-	if f.IsStale() {
+// GetValues returns copies of the 3 components (T1, T2, T3) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data (T1, T2, T3).
+func (f *Filter3[T1, T2, T3]) GetValues() (T1, T2, T3) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		*(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
+		*(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2]))
+}
+
+// Apply calls fn with pointers to the components T1, T2, T3 of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter3[T1, T2, T3]) Apply(fn func(*T1, *T2, *T3)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
 		f.updateMatching()
 	}
-	for _, a := range f.matchingArches {
-		for i := 0; i < a.size; i++ {
-			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k := range n {
+			fn(&col1[k], &col2[k], &col3[k])
 		}
-		a.size = 0
 	}
-	f.world.mutationVersion.Add(1)
-	f.doReset()
 }
 
-// Entities returns all entities that match the filter.
-func (f *Filter3[T1, T2, T3]) Entities() []Entity {
-	return f.queryCache.Entities()
-}
+// EachMut calls fn with pointers to the components T1, T2, T3 of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter3[T1, T2, T3]) EachMut(fn func(Entity, *T1, *T2, *T3) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+			col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+			col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col1[k], &col2[k], &col3[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource3 calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// T1, T2, T3. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource3[R any, T1 any, T2 any, T3 any](f *Filter3[T1, T2, T3], fn func(*R, Entity, *T1, *T2, *T3)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource3 requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col1[k], &col2[k], &col3[k])
+		}
+	}
+}
+
+// Page3 is a page of the entities matched by a Filter3, created by
+// Filter3.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page3[T1 any, T2 any, T3 any] struct {
+	pageCursor
+	ids [3]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter3[T1, T2, T3]) Page(offset, limit int) Page3[T1, T2, T3] {
+	return Page3[T1, T2, T3]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to T1, T2, T3 for the current entity of the page.
+func (p *Page3[T1, T2, T3]) Get() (*T1, *T2, *T3) {
+	a, i := p.row()
+	return (*T1)(unsafe.Add(a.compPointers[p.ids[0]], i*a.compSizes[p.ids[0]])),
+		(*T2)(unsafe.Add(a.compPointers[p.ids[1]], i*a.compSizes[p.ids[1]])),
+		(*T3)(unsafe.Add(a.compPointers[p.ids[2]], i*a.compSizes[p.ids[2]]))
+}
+
+// Map3 calls fn with pointers to the components T1, T2, T3 of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map3[T1 any, T2 any, T3 any, R any](f *Filter3[T1, T2, T3], fn func(*T1, *T2, *T3) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c1 *T1, c2 *T2, c3 *T3) {
+		res = append(res, fn(c1, c2, c3))
+	})
+	return res
+}
+
+// SetAll overwrites the components T1, T2, T3 of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent3 per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+//   - v1: The value to store in every matching entity's T1.
+//   - v2: The value to store in every matching entity's T2.
+//   - v3: The value to store in every matching entity's T3.
+func (f *Filter3[T1, T2, T3]) SetAll(v1 T1, v2 T2, v3 T3) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		for k := range col1 {
+			col1[k] = v1
+		}
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range col2 {
+			col2[k] = v2
+		}
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k := range col3 {
+			col3[k] = v3
+		}
+		for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of T1, T2, T3 before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter3[T1, T2, T3]) EachSafe(fn func(Entity, *T1, *T2, *T3)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if !a.mask.has(f.ids[0]) || !a.mask.has(f.ids[1]) || !a.mask.has(f.ids[2]) {
+			w.mu.RUnlock()
+			continue
+		}
+		c1 := (*T1)(unsafe.Add(a.compPointers[f.ids[0]], uintptr(meta.index)*f.compSizes[0]))
+		c2 := (*T2)(unsafe.Add(a.compPointers[f.ids[1]], uintptr(meta.index)*f.compSizes[1]))
+		c3 := (*T3)(unsafe.Add(a.compPointers[f.ids[2]], uintptr(meta.index)*f.compSizes[2]))
+		w.mu.RUnlock()
+		fn(e, c1, c2, c3)
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// T1, T2, T3, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter3[T1, T2, T3]) Versions() (v1, v2, v3 uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return f.world.componentVersion(f.ids[0], id), f.world.componentVersion(f.ids[1], id), f.world.componentVersion(f.ids[2], id)
+}
+
+// MarkChanged flags the current entity's tracked components among
+// T1, T2, T3 as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter3[T1, T2, T3]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among T1, T2, T3 of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter3[T1, T2, T3]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter3[T1, T2, T3]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among T1, T2, T3 was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter3[T1, T2, T3]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter3[T1, T2, T3]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveEntities efficiently removes all entities that match the filter's
+// query. This operation is performed in a batch, invalidating all matching
+// entities and recycling their IDs without moving any memory.
+func (f *Filter3[T1, T2, T3]) RemoveEntities() {
+	f.checkWorld()
+	f.world.mu.Lock()
+	defer f.world.mu.Unlock()
+	if f.IsStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		for i := 0; i < a.size; i++ {
+			ent := a.entityIDs[i]
+			f.world.releaseEntityNoLock(ent.ID)
+		}
+		a.clearDeadSlots(0, a.size)
+		a.size = 0
+	}
+	f.world.removeOwnedNoLock()
+	f.world.mutationVersion.Add(1)
+	f.doReset()
+}
+
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its T1, T2, T3 components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter3[T1, T2, T3]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 3)
+	f.Reset()
+	for f.Next() {
+		p1, p2, p3 := f.Get()
+		comps[0] = p1
+		comps[1] = p2
+		comps[2] = p3
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entities returns all entities that match the filter.
+func (f *Filter3[T1, T2, T3]) Entities() []Entity {
+	return f.queryCache.Entities()
+}
 
 // Query3 is an allocation-free iterator snapshot for Filter3.
 type Query3[T1 any, T2 any, T3 any] struct {
@@ -414,6 +1458,7 @@ type Query3[T1 any, T2 any, T3 any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [3]uintptr
 	curArchSize    int
 	ids            [3]uint8
@@ -421,6 +1466,7 @@ type Query3[T1 any, T2 any, T3 any] struct {
 
 // Query returns a new Query3 iterator from the Filter3.
 func (f *Filter3[T1, T2, T3]) Query() Query3[T1, T2, T3] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -435,9 +1481,9 @@ func (f *Filter3[T1, T2, T3]) Query() Query3[T1, T2, T3] {
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		f.curBases[0] = a.compPointers[f.ids[0]]
-		f.curBases[1] = a.compPointers[f.ids[1]]
-		f.curBases[2] = a.compPointers[f.ids[2]]
+		q.curBases[0] = a.compPointers[q.ids[0]]
+		q.curBases[1] = a.compPointers[q.ids[1]]
+		q.curBases[2] = a.compPointers[q.ids[2]]
 		
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -459,7 +1505,14 @@ func (q *Query3[T1, T2, T3]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query3[T1, T2, T3]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -474,13 +1527,86 @@ func (q *Query3[T1, T2, T3]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query3[T1, T2, T3]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query3[T1, T2, T3]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query3[T1, T2, T3]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query3[T1, T2, T3]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBases[0] = a.compPointers[q.ids[0]]
+	q.curBases[1] = a.compPointers[q.ids[1]]
+	q.curBases[2] = a.compPointers[q.ids[2]]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query3[T1, T2, T3]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query3[T1, T2, T3]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to T1, T2, T3 for the current entity.
 func (q *Query3[T1, T2, T3]) Get() (*T1, *T2, *T3) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T1)(unsafe.Add(q.curBases[0], uintptr(q.curIdx)*q.compSizes[0])),
 		(*T2)(unsafe.Add(q.curBases[1], uintptr(q.curIdx)*q.compSizes[1])),
 		(*T3)(unsafe.Add(q.curBases[2], uintptr(q.curIdx)*q.compSizes[2]))
@@ -494,8 +1620,11 @@ type Filter4[T1 any, T2 any, T3 any, T4 any] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [4]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [4]uint8
 }
 
@@ -508,6 +1637,23 @@ type Filter4[T1 any, T2 any, T3 any, T4 any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter4`.
 func NewFilter4[T1 any, T2 any, T3 any, T4 any](w *World) *Filter4[T1, T2, T3, T4] {
+	return newFilter4[T1, T2, T3, T4](w, false)
+}
+
+// NewFilterExact4 creates a new `Filter` that iterates only over entities
+// whose components are exactly T1, T2, T3, T4, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter4`.
+func NewFilterExact4[T1 any, T2 any, T3 any, T4 any](w *World) *Filter4[T1, T2, T3, T4] {
+	return newFilter4[T1, T2, T3, T4](w, true)
+}
+
+func newFilter4[T1 any, T2 any, T3 any, T4 any](w *World, exact bool) *Filter4[T1, T2, T3, T4] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
@@ -535,6 +1681,7 @@ func NewFilter4[T1 any, T2 any, T3 any, T4 any](w *World) *Filter4[T1, T2, T3, T
 	f.compSizes[2] = w.components.compIDToSize[id3]
 	f.compSizes[3] = w.components.compIDToSize[id4]
 	
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -542,26 +1689,44 @@ func NewFilter4[T1 any, T2 any, T3 any, T4 any](w *World) *Filter4[T1, T2, T3, T
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter4`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter4` (or `NewFilterExact4`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter4[T1, T2, T3, T4]) New(w *World) *Filter4[T1, T2, T3, T4] {
-	return NewFilter4[T1, T2, T3, T4](w)
+	return newFilter4[T1, T2, T3, T4](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter4[T1, T2, T3, T4]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter4[T1, T2, T3, T4]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter4[T1, T2, T3, T4]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBases[0] = a.compPointers[f.ids[0]]
@@ -576,14 +1741,38 @@ func (f *Filter4[T1, T2, T3, T4]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter4[T1, T2, T3, T4]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter4[T1, T2, T3, T4]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -591,7 +1780,18 @@ func (f *Filter4[T1, T2, T3, T4]) Next() bool {
 }
 
 func (f *Filter4[T1, T2, T3, T4]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -603,16 +1803,34 @@ func (f *Filter4[T1, T2, T3, T4]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter4[T1, T2, T3, T4]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter4[T1, T2, T3, T4]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -623,16 +1841,398 @@ func (f *Filter4[T1, T2, T3, T4]) Entity() Entity {
 // Returns:
 //   - Pointers to the component data (*T1, *T2, *T3, *T4).
 func (f *Filter4[T1, T2, T3, T4]) Get() (*T1, *T2, *T3, *T4) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
 		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
 		(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
 		(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3]))
 }
 
+// GetValues returns copies of the 4 components (T1, T2, T3, T4) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data (T1, T2, T3, T4).
+func (f *Filter4[T1, T2, T3, T4]) GetValues() (T1, T2, T3, T4) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		*(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
+		*(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
+		*(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3]))
+}
+
+// Apply calls fn with pointers to the components T1, T2, T3, T4 of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter4[T1, T2, T3, T4]) Apply(fn func(*T1, *T2, *T3, *T4)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		for k := range n {
+			fn(&col1[k], &col2[k], &col3[k], &col4[k])
+		}
+	}
+}
+
+// EachMut calls fn with pointers to the components T1, T2, T3, T4 of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter4[T1, T2, T3, T4]) EachMut(fn func(Entity, *T1, *T2, *T3, *T4) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+			col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+			col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+			col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col1[k], &col2[k], &col3[k], &col4[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource4 calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// T1, T2, T3, T4. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource4[R any, T1 any, T2 any, T3 any, T4 any](f *Filter4[T1, T2, T3, T4], fn func(*R, Entity, *T1, *T2, *T3, *T4)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource4 requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col1[k], &col2[k], &col3[k], &col4[k])
+		}
+	}
+}
+
+// Page4 is a page of the entities matched by a Filter4, created by
+// Filter4.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page4[T1 any, T2 any, T3 any, T4 any] struct {
+	pageCursor
+	ids [4]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter4[T1, T2, T3, T4]) Page(offset, limit int) Page4[T1, T2, T3, T4] {
+	return Page4[T1, T2, T3, T4]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to T1, T2, T3, T4 for the current entity of the page.
+func (p *Page4[T1, T2, T3, T4]) Get() (*T1, *T2, *T3, *T4) {
+	a, i := p.row()
+	return (*T1)(unsafe.Add(a.compPointers[p.ids[0]], i*a.compSizes[p.ids[0]])),
+		(*T2)(unsafe.Add(a.compPointers[p.ids[1]], i*a.compSizes[p.ids[1]])),
+		(*T3)(unsafe.Add(a.compPointers[p.ids[2]], i*a.compSizes[p.ids[2]])),
+		(*T4)(unsafe.Add(a.compPointers[p.ids[3]], i*a.compSizes[p.ids[3]]))
+}
+
+// Map4 calls fn with pointers to the components T1, T2, T3, T4 of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map4[T1 any, T2 any, T3 any, T4 any, R any](f *Filter4[T1, T2, T3, T4], fn func(*T1, *T2, *T3, *T4) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c1 *T1, c2 *T2, c3 *T3, c4 *T4) {
+		res = append(res, fn(c1, c2, c3, c4))
+	})
+	return res
+}
+
+// SetAll overwrites the components T1, T2, T3, T4 of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent4 per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+//   - v1: The value to store in every matching entity's T1.
+//   - v2: The value to store in every matching entity's T2.
+//   - v3: The value to store in every matching entity's T3.
+//   - v4: The value to store in every matching entity's T4.
+func (f *Filter4[T1, T2, T3, T4]) SetAll(v1 T1, v2 T2, v3 T3, v4 T4) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		for k := range col1 {
+			col1[k] = v1
+		}
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range col2 {
+			col2[k] = v2
+		}
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k := range col3 {
+			col3[k] = v3
+		}
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		for k := range col4 {
+			col4[k] = v4
+		}
+		for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of T1, T2, T3, T4 before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter4[T1, T2, T3, T4]) EachSafe(fn func(Entity, *T1, *T2, *T3, *T4)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if !a.mask.has(f.ids[0]) || !a.mask.has(f.ids[1]) || !a.mask.has(f.ids[2]) || !a.mask.has(f.ids[3]) {
+			w.mu.RUnlock()
+			continue
+		}
+		c1 := (*T1)(unsafe.Add(a.compPointers[f.ids[0]], uintptr(meta.index)*f.compSizes[0]))
+		c2 := (*T2)(unsafe.Add(a.compPointers[f.ids[1]], uintptr(meta.index)*f.compSizes[1]))
+		c3 := (*T3)(unsafe.Add(a.compPointers[f.ids[2]], uintptr(meta.index)*f.compSizes[2]))
+		c4 := (*T4)(unsafe.Add(a.compPointers[f.ids[3]], uintptr(meta.index)*f.compSizes[3]))
+		w.mu.RUnlock()
+		fn(e, c1, c2, c3, c4)
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// T1, T2, T3, T4, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter4[T1, T2, T3, T4]) Versions() (v1, v2, v3, v4 uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return f.world.componentVersion(f.ids[0], id), f.world.componentVersion(f.ids[1], id), f.world.componentVersion(f.ids[2], id), f.world.componentVersion(f.ids[3], id)
+}
+
+// MarkChanged flags the current entity's tracked components among
+// T1, T2, T3, T4 as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter4[T1, T2, T3, T4]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among T1, T2, T3, T4 of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter4[T1, T2, T3, T4]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter4[T1, T2, T3, T4]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among T1, T2, T3, T4 was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter4[T1, T2, T3, T4]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter4[T1, T2, T3, T4]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory.
 func (f *Filter4[T1, T2, T3, T4]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -641,18 +2241,43 @@ func (f *Filter4[T1, T2, T3, T4]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its T1, T2, T3, T4 components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter4[T1, T2, T3, T4]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 4)
+	f.Reset()
+	for f.Next() {
+		p1, p2, p3, p4 := f.Get()
+		comps[0] = p1
+		comps[1] = p2
+		comps[2] = p3
+		comps[3] = p4
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns all entities that match the filter.
 func (f *Filter4[T1, T2, T3, T4]) Entities() []Entity {
 	return f.queryCache.Entities()
@@ -665,6 +2290,7 @@ type Query4[T1 any, T2 any, T3 any, T4 any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [4]uintptr
 	curArchSize    int
 	ids            [4]uint8
@@ -672,6 +2298,7 @@ type Query4[T1 any, T2 any, T3 any, T4 any] struct {
 
 // Query returns a new Query4 iterator from the Filter4.
 func (f *Filter4[T1, T2, T3, T4]) Query() Query4[T1, T2, T3, T4] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -686,10 +2313,10 @@ func (f *Filter4[T1, T2, T3, T4]) Query() Query4[T1, T2, T3, T4] {
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		f.curBases[0] = a.compPointers[f.ids[0]]
-		f.curBases[1] = a.compPointers[f.ids[1]]
-		f.curBases[2] = a.compPointers[f.ids[2]]
-		f.curBases[3] = a.compPointers[f.ids[3]]
+		q.curBases[0] = a.compPointers[q.ids[0]]
+		q.curBases[1] = a.compPointers[q.ids[1]]
+		q.curBases[2] = a.compPointers[q.ids[2]]
+		q.curBases[3] = a.compPointers[q.ids[3]]
 		
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -711,7 +2338,14 @@ func (q *Query4[T1, T2, T3, T4]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query4[T1, T2, T3, T4]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -727,13 +2361,87 @@ func (q *Query4[T1, T2, T3, T4]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query4[T1, T2, T3, T4]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query4[T1, T2, T3, T4]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query4[T1, T2, T3, T4]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query4[T1, T2, T3, T4]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBases[0] = a.compPointers[q.ids[0]]
+	q.curBases[1] = a.compPointers[q.ids[1]]
+	q.curBases[2] = a.compPointers[q.ids[2]]
+	q.curBases[3] = a.compPointers[q.ids[3]]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query4[T1, T2, T3, T4]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query4[T1, T2, T3, T4]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to T1, T2, T3, T4 for the current entity.
 func (q *Query4[T1, T2, T3, T4]) Get() (*T1, *T2, *T3, *T4) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T1)(unsafe.Add(q.curBases[0], uintptr(q.curIdx)*q.compSizes[0])),
 		(*T2)(unsafe.Add(q.curBases[1], uintptr(q.curIdx)*q.compSizes[1])),
 		(*T3)(unsafe.Add(q.curBases[2], uintptr(q.curIdx)*q.compSizes[2])),
@@ -748,8 +2456,11 @@ type Filter5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [5]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [5]uint8
 }
 
@@ -762,6 +2473,23 @@ type Filter5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter5`.
 func NewFilter5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *Filter5[T1, T2, T3, T4, T5] {
+	return newFilter5[T1, T2, T3, T4, T5](w, false)
+}
+
+// NewFilterExact5 creates a new `Filter` that iterates only over entities
+// whose components are exactly T1, T2, T3, T4, T5, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter5`.
+func NewFilterExact5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *Filter5[T1, T2, T3, T4, T5] {
+	return newFilter5[T1, T2, T3, T4, T5](w, true)
+}
+
+func newFilter5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, exact bool) *Filter5[T1, T2, T3, T4, T5] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
@@ -792,6 +2520,7 @@ func NewFilter5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *Filter5[T1, T
 	f.compSizes[3] = w.components.compIDToSize[id4]
 	f.compSizes[4] = w.components.compIDToSize[id5]
 	
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -799,26 +2528,44 @@ func NewFilter5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *Filter5[T1, T
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter5`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter5` (or `NewFilterExact5`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter5[T1, T2, T3, T4, T5]) New(w *World) *Filter5[T1, T2, T3, T4, T5] {
-	return NewFilter5[T1, T2, T3, T4, T5](w)
+	return newFilter5[T1, T2, T3, T4, T5](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter5[T1, T2, T3, T4, T5]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter5[T1, T2, T3, T4, T5]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter5[T1, T2, T3, T4, T5]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBases[0] = a.compPointers[f.ids[0]]
@@ -834,14 +2581,38 @@ func (f *Filter5[T1, T2, T3, T4, T5]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter5[T1, T2, T3, T4, T5]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter5[T1, T2, T3, T4, T5]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -849,7 +2620,18 @@ func (f *Filter5[T1, T2, T3, T4, T5]) Next() bool {
 }
 
 func (f *Filter5[T1, T2, T3, T4, T5]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -862,16 +2644,34 @@ func (f *Filter5[T1, T2, T3, T4, T5]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter5[T1, T2, T3, T4, T5]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter5[T1, T2, T3, T4, T5]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -880,19 +2680,412 @@ func (f *Filter5[T1, T2, T3, T4, T5]) Entity() Entity {
 // has returned true.
 //
 // Returns:
-//   - Pointers to the component data (*T1, *T2, *T3, *T4, *T5).
-func (f *Filter5[T1, T2, T3, T4, T5]) Get() (*T1, *T2, *T3, *T4, *T5) {
-	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
-		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
-		(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
-		(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3])),
-		(*T5)(unsafe.Add(f.curBases[4], uintptr(f.curIdx)*f.compSizes[4]))
+//   - Pointers to the component data (*T1, *T2, *T3, *T4, *T5).
+func (f *Filter5[T1, T2, T3, T4, T5]) Get() (*T1, *T2, *T3, *T4, *T5) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
+	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
+		(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
+		(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3])),
+		(*T5)(unsafe.Add(f.curBases[4], uintptr(f.curIdx)*f.compSizes[4]))
+}
+
+// GetValues returns copies of the 5 components (T1, T2, T3, T4, T5) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data (T1, T2, T3, T4, T5).
+func (f *Filter5[T1, T2, T3, T4, T5]) GetValues() (T1, T2, T3, T4, T5) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		*(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
+		*(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
+		*(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3])),
+		*(*T5)(unsafe.Add(f.curBases[4], uintptr(f.curIdx)*f.compSizes[4]))
+}
+
+// Apply calls fn with pointers to the components T1, T2, T3, T4, T5 of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter5[T1, T2, T3, T4, T5]) Apply(fn func(*T1, *T2, *T3, *T4, *T5)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		for k := range n {
+			fn(&col1[k], &col2[k], &col3[k], &col4[k], &col5[k])
+		}
+	}
+}
+
+// EachMut calls fn with pointers to the components T1, T2, T3, T4, T5 of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter5[T1, T2, T3, T4, T5]) EachMut(fn func(Entity, *T1, *T2, *T3, *T4, *T5) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+			col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+			col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+			col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+			col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col1[k], &col2[k], &col3[k], &col4[k], &col5[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource5 calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// T1, T2, T3, T4, T5. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource5[R any, T1 any, T2 any, T3 any, T4 any, T5 any](f *Filter5[T1, T2, T3, T4, T5], fn func(*R, Entity, *T1, *T2, *T3, *T4, *T5)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource5 requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col1[k], &col2[k], &col3[k], &col4[k], &col5[k])
+		}
+	}
+}
+
+// Page5 is a page of the entities matched by a Filter5, created by
+// Filter5.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
+	pageCursor
+	ids [5]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter5[T1, T2, T3, T4, T5]) Page(offset, limit int) Page5[T1, T2, T3, T4, T5] {
+	return Page5[T1, T2, T3, T4, T5]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to T1, T2, T3, T4, T5 for the current entity of the page.
+func (p *Page5[T1, T2, T3, T4, T5]) Get() (*T1, *T2, *T3, *T4, *T5) {
+	a, i := p.row()
+	return (*T1)(unsafe.Add(a.compPointers[p.ids[0]], i*a.compSizes[p.ids[0]])),
+		(*T2)(unsafe.Add(a.compPointers[p.ids[1]], i*a.compSizes[p.ids[1]])),
+		(*T3)(unsafe.Add(a.compPointers[p.ids[2]], i*a.compSizes[p.ids[2]])),
+		(*T4)(unsafe.Add(a.compPointers[p.ids[3]], i*a.compSizes[p.ids[3]])),
+		(*T5)(unsafe.Add(a.compPointers[p.ids[4]], i*a.compSizes[p.ids[4]]))
+}
+
+// Map5 calls fn with pointers to the components T1, T2, T3, T4, T5 of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map5[T1 any, T2 any, T3 any, T4 any, T5 any, R any](f *Filter5[T1, T2, T3, T4, T5], fn func(*T1, *T2, *T3, *T4, *T5) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c1 *T1, c2 *T2, c3 *T3, c4 *T4, c5 *T5) {
+		res = append(res, fn(c1, c2, c3, c4, c5))
+	})
+	return res
+}
+
+// SetAll overwrites the components T1, T2, T3, T4, T5 of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent5 per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+//   - v1: The value to store in every matching entity's T1.
+//   - v2: The value to store in every matching entity's T2.
+//   - v3: The value to store in every matching entity's T3.
+//   - v4: The value to store in every matching entity's T4.
+//   - v5: The value to store in every matching entity's T5.
+func (f *Filter5[T1, T2, T3, T4, T5]) SetAll(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		for k := range col1 {
+			col1[k] = v1
+		}
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range col2 {
+			col2[k] = v2
+		}
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k := range col3 {
+			col3[k] = v3
+		}
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		for k := range col4 {
+			col4[k] = v4
+		}
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		for k := range col5 {
+			col5[k] = v5
+		}
+		for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of T1, T2, T3, T4, T5 before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter5[T1, T2, T3, T4, T5]) EachSafe(fn func(Entity, *T1, *T2, *T3, *T4, *T5)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if !a.mask.has(f.ids[0]) || !a.mask.has(f.ids[1]) || !a.mask.has(f.ids[2]) || !a.mask.has(f.ids[3]) || !a.mask.has(f.ids[4]) {
+			w.mu.RUnlock()
+			continue
+		}
+		c1 := (*T1)(unsafe.Add(a.compPointers[f.ids[0]], uintptr(meta.index)*f.compSizes[0]))
+		c2 := (*T2)(unsafe.Add(a.compPointers[f.ids[1]], uintptr(meta.index)*f.compSizes[1]))
+		c3 := (*T3)(unsafe.Add(a.compPointers[f.ids[2]], uintptr(meta.index)*f.compSizes[2]))
+		c4 := (*T4)(unsafe.Add(a.compPointers[f.ids[3]], uintptr(meta.index)*f.compSizes[3]))
+		c5 := (*T5)(unsafe.Add(a.compPointers[f.ids[4]], uintptr(meta.index)*f.compSizes[4]))
+		w.mu.RUnlock()
+		fn(e, c1, c2, c3, c4, c5)
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// T1, T2, T3, T4, T5, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter5[T1, T2, T3, T4, T5]) Versions() (v1, v2, v3, v4, v5 uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return f.world.componentVersion(f.ids[0], id), f.world.componentVersion(f.ids[1], id), f.world.componentVersion(f.ids[2], id), f.world.componentVersion(f.ids[3], id), f.world.componentVersion(f.ids[4], id)
+}
+
+// MarkChanged flags the current entity's tracked components among
+// T1, T2, T3, T4, T5 as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter5[T1, T2, T3, T4, T5]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among T1, T2, T3, T4, T5 of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter5[T1, T2, T3, T4, T5]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter5[T1, T2, T3, T4, T5]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among T1, T2, T3, T4, T5 was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter5[T1, T2, T3, T4, T5]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter5[T1, T2, T3, T4, T5]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
 }
 
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory.
 func (f *Filter5[T1, T2, T3, T4, T5]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -901,18 +3094,44 @@ func (f *Filter5[T1, T2, T3, T4, T5]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its T1, T2, T3, T4, T5 components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter5[T1, T2, T3, T4, T5]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 5)
+	f.Reset()
+	for f.Next() {
+		p1, p2, p3, p4, p5 := f.Get()
+		comps[0] = p1
+		comps[1] = p2
+		comps[2] = p3
+		comps[3] = p4
+		comps[4] = p5
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns all entities that match the filter.
 func (f *Filter5[T1, T2, T3, T4, T5]) Entities() []Entity {
 	return f.queryCache.Entities()
@@ -925,6 +3144,7 @@ type Query5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [5]uintptr
 	curArchSize    int
 	ids            [5]uint8
@@ -932,6 +3152,7 @@ type Query5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
 
 // Query returns a new Query5 iterator from the Filter5.
 func (f *Filter5[T1, T2, T3, T4, T5]) Query() Query5[T1, T2, T3, T4, T5] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -946,11 +3167,11 @@ func (f *Filter5[T1, T2, T3, T4, T5]) Query() Query5[T1, T2, T3, T4, T5] {
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		f.curBases[0] = a.compPointers[f.ids[0]]
-		f.curBases[1] = a.compPointers[f.ids[1]]
-		f.curBases[2] = a.compPointers[f.ids[2]]
-		f.curBases[3] = a.compPointers[f.ids[3]]
-		f.curBases[4] = a.compPointers[f.ids[4]]
+		q.curBases[0] = a.compPointers[q.ids[0]]
+		q.curBases[1] = a.compPointers[q.ids[1]]
+		q.curBases[2] = a.compPointers[q.ids[2]]
+		q.curBases[3] = a.compPointers[q.ids[3]]
+		q.curBases[4] = a.compPointers[q.ids[4]]
 		
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -972,7 +3193,14 @@ func (q *Query5[T1, T2, T3, T4, T5]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query5[T1, T2, T3, T4, T5]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -989,13 +3217,88 @@ func (q *Query5[T1, T2, T3, T4, T5]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query5[T1, T2, T3, T4, T5]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query5[T1, T2, T3, T4, T5]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query5[T1, T2, T3, T4, T5]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query5[T1, T2, T3, T4, T5]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBases[0] = a.compPointers[q.ids[0]]
+	q.curBases[1] = a.compPointers[q.ids[1]]
+	q.curBases[2] = a.compPointers[q.ids[2]]
+	q.curBases[3] = a.compPointers[q.ids[3]]
+	q.curBases[4] = a.compPointers[q.ids[4]]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query5[T1, T2, T3, T4, T5]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query5[T1, T2, T3, T4, T5]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to T1, T2, T3, T4, T5 for the current entity.
 func (q *Query5[T1, T2, T3, T4, T5]) Get() (*T1, *T2, *T3, *T4, *T5) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T1)(unsafe.Add(q.curBases[0], uintptr(q.curIdx)*q.compSizes[0])),
 		(*T2)(unsafe.Add(q.curBases[1], uintptr(q.curIdx)*q.compSizes[1])),
 		(*T3)(unsafe.Add(q.curBases[2], uintptr(q.curIdx)*q.compSizes[2])),
@@ -1011,8 +3314,11 @@ type Filter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [6]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [6]uint8
 }
 
@@ -1025,6 +3331,23 @@ type Filter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
 // Returns:
 //   - A pointer to the newly created `Filter6`.
 func NewFilter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *Filter6[T1, T2, T3, T4, T5, T6] {
+	return newFilter6[T1, T2, T3, T4, T5, T6](w, false)
+}
+
+// NewFilterExact6 creates a new `Filter` that iterates only over entities
+// whose components are exactly T1, T2, T3, T4, T5, T6, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter6`.
+func NewFilterExact6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *Filter6[T1, T2, T3, T4, T5, T6] {
+	return newFilter6[T1, T2, T3, T4, T5, T6](w, true)
+}
+
+func newFilter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, exact bool) *Filter6[T1, T2, T3, T4, T5, T6] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
@@ -1058,6 +3381,7 @@ func NewFilter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *Filte
 	f.compSizes[4] = w.components.compIDToSize[id5]
 	f.compSizes[5] = w.components.compIDToSize[id6]
 	
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -1065,26 +3389,44 @@ func NewFilter6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *Filte
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter6`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter6` (or `NewFilterExact6`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) New(w *World) *Filter6[T1, T2, T3, T4, T5, T6] {
-	return NewFilter6[T1, T2, T3, T4, T5, T6](w)
+	return newFilter6[T1, T2, T3, T4, T5, T6](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		f.curBases[0] = a.compPointers[f.ids[0]]
@@ -1101,14 +3443,38 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -1116,7 +3482,18 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) Next() bool {
 }
 
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -1130,16 +3507,34 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -1150,6 +3545,15 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) Entity() Entity {
 // Returns:
 //   - Pointers to the component data (*T1, *T2, *T3, *T4, *T5, *T6).
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Get() (*T1, *T2, *T3, *T4, *T5, *T6) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return (*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
 		(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
 		(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
@@ -1158,10 +3562,405 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) Get() (*T1, *T2, *T3, *T4, *T5, *T6) {
 		(*T6)(unsafe.Add(f.curBases[5], uintptr(f.curIdx)*f.compSizes[5]))
 }
 
+// GetValues returns copies of the 6 components (T1, T2, T3, T4, T5, T6) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data (T1, T2, T3, T4, T5, T6).
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) GetValues() (T1, T2, T3, T4, T5, T6) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return *(*T1)(unsafe.Add(f.curBases[0], uintptr(f.curIdx)*f.compSizes[0])),
+		*(*T2)(unsafe.Add(f.curBases[1], uintptr(f.curIdx)*f.compSizes[1])),
+		*(*T3)(unsafe.Add(f.curBases[2], uintptr(f.curIdx)*f.compSizes[2])),
+		*(*T4)(unsafe.Add(f.curBases[3], uintptr(f.curIdx)*f.compSizes[3])),
+		*(*T5)(unsafe.Add(f.curBases[4], uintptr(f.curIdx)*f.compSizes[4])),
+		*(*T6)(unsafe.Add(f.curBases[5], uintptr(f.curIdx)*f.compSizes[5]))
+}
+
+// Apply calls fn with pointers to the components T1, T2, T3, T4, T5, T6 of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Apply(fn func(*T1, *T2, *T3, *T4, *T5, *T6)) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		col6 := unsafe.Slice((*T6)(a.compPointers[f.ids[5]]), n)
+		for k := range n {
+			fn(&col1[k], &col2[k], &col3[k], &col4[k], &col5[k], &col6[k])
+		}
+	}
+}
+
+// EachMut calls fn with pointers to the components T1, T2, T3, T4, T5, T6 of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) EachMut(fn func(Entity, *T1, *T2, *T3, *T4, *T5, *T6) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+			col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+			col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+			col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+			col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+			col6 := unsafe.Slice((*T6)(a.compPointers[f.ids[5]]), n)
+			for k, e := range a.entityIDs[:n] {
+				if fn(e, &col1[k], &col2[k], &col3[k], &col4[k], &col5[k], &col6[k]) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource6 calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// T1, T2, T3, T4, T5, T6. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource6[R any, T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](f *Filter6[T1, T2, T3, T4, T5, T6], fn func(*R, Entity, *T1, *T2, *T3, *T4, *T5, *T6)) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource6 requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		col6 := unsafe.Slice((*T6)(a.compPointers[f.ids[5]]), n)
+		for k, e := range a.entityIDs[:n] {
+			fn(res, e, &col1[k], &col2[k], &col3[k], &col4[k], &col5[k], &col6[k])
+		}
+	}
+}
+
+// Page6 is a page of the entities matched by a Filter6, created by
+// Filter6.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
+	pageCursor
+	ids [6]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Page(offset, limit int) Page6[T1, T2, T3, T4, T5, T6] {
+	return Page6[T1, T2, T3, T4, T5, T6]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to T1, T2, T3, T4, T5, T6 for the current entity of the page.
+func (p *Page6[T1, T2, T3, T4, T5, T6]) Get() (*T1, *T2, *T3, *T4, *T5, *T6) {
+	a, i := p.row()
+	return (*T1)(unsafe.Add(a.compPointers[p.ids[0]], i*a.compSizes[p.ids[0]])),
+		(*T2)(unsafe.Add(a.compPointers[p.ids[1]], i*a.compSizes[p.ids[1]])),
+		(*T3)(unsafe.Add(a.compPointers[p.ids[2]], i*a.compSizes[p.ids[2]])),
+		(*T4)(unsafe.Add(a.compPointers[p.ids[3]], i*a.compSizes[p.ids[3]])),
+		(*T5)(unsafe.Add(a.compPointers[p.ids[4]], i*a.compSizes[p.ids[4]])),
+		(*T6)(unsafe.Add(a.compPointers[p.ids[5]], i*a.compSizes[p.ids[5]]))
+}
+
+// Map6 calls fn with pointers to the components T1, T2, T3, T4, T5, T6 of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any, R any](f *Filter6[T1, T2, T3, T4, T5, T6], fn func(*T1, *T2, *T3, *T4, *T5, *T6) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func(c1 *T1, c2 *T2, c3 *T3, c4 *T4, c5 *T5, c6 *T6) {
+		res = append(res, fn(c1, c2, c3, c4, c5, c6))
+	})
+	return res
+}
+
+// SetAll overwrites the components T1, T2, T3, T4, T5, T6 of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent6 per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+//   - v1: The value to store in every matching entity's T1.
+//   - v2: The value to store in every matching entity's T2.
+//   - v3: The value to store in every matching entity's T3.
+//   - v4: The value to store in every matching entity's T4.
+//   - v5: The value to store in every matching entity's T5.
+//   - v6: The value to store in every matching entity's T6.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) SetAll(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		col1 := unsafe.Slice((*T1)(a.compPointers[f.ids[0]]), n)
+		for k := range col1 {
+			col1[k] = v1
+		}
+		col2 := unsafe.Slice((*T2)(a.compPointers[f.ids[1]]), n)
+		for k := range col2 {
+			col2[k] = v2
+		}
+		col3 := unsafe.Slice((*T3)(a.compPointers[f.ids[2]]), n)
+		for k := range col3 {
+			col3[k] = v3
+		}
+		col4 := unsafe.Slice((*T4)(a.compPointers[f.ids[3]]), n)
+		for k := range col4 {
+			col4[k] = v4
+		}
+		col5 := unsafe.Slice((*T5)(a.compPointers[f.ids[4]]), n)
+		for k := range col5 {
+			col5[k] = v5
+		}
+		col6 := unsafe.Slice((*T6)(a.compPointers[f.ids[5]]), n)
+		for k := range col6 {
+			col6[k] = v6
+		}
+		for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of T1, T2, T3, T4, T5, T6 before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) EachSafe(fn func(Entity, *T1, *T2, *T3, *T4, *T5, *T6)) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if !a.mask.has(f.ids[0]) || !a.mask.has(f.ids[1]) || !a.mask.has(f.ids[2]) || !a.mask.has(f.ids[3]) || !a.mask.has(f.ids[4]) || !a.mask.has(f.ids[5]) {
+			w.mu.RUnlock()
+			continue
+		}
+		c1 := (*T1)(unsafe.Add(a.compPointers[f.ids[0]], uintptr(meta.index)*f.compSizes[0]))
+		c2 := (*T2)(unsafe.Add(a.compPointers[f.ids[1]], uintptr(meta.index)*f.compSizes[1]))
+		c3 := (*T3)(unsafe.Add(a.compPointers[f.ids[2]], uintptr(meta.index)*f.compSizes[2]))
+		c4 := (*T4)(unsafe.Add(a.compPointers[f.ids[3]], uintptr(meta.index)*f.compSizes[3]))
+		c5 := (*T5)(unsafe.Add(a.compPointers[f.ids[4]], uintptr(meta.index)*f.compSizes[4]))
+		c6 := (*T6)(unsafe.Add(a.compPointers[f.ids[5]], uintptr(meta.index)*f.compSizes[5]))
+		w.mu.RUnlock()
+		fn(e, c1, c2, c3, c4, c5, c6)
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// T1, T2, T3, T4, T5, T6, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Versions() (v1, v2, v3, v4, v5, v6 uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return f.world.componentVersion(f.ids[0], id), f.world.componentVersion(f.ids[1], id), f.world.componentVersion(f.ids[2], id), f.world.componentVersion(f.ids[3], id), f.world.componentVersion(f.ids[4], id), f.world.componentVersion(f.ids[5], id)
+}
+
+// MarkChanged flags the current entity's tracked components among
+// T1, T2, T3, T4, T5, T6 as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among T1, T2, T3, T4, T5, T6 of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among T1, T2, T3, T4, T5, T6 was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -1170,18 +3969,45 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its T1, T2, T3, T4, T5, T6 components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter6[T1, T2, T3, T4, T5, T6]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, 6)
+	f.Reset()
+	for f.Next() {
+		p1, p2, p3, p4, p5, p6 := f.Get()
+		comps[0] = p1
+		comps[1] = p2
+		comps[2] = p3
+		comps[3] = p4
+		comps[4] = p5
+		comps[5] = p6
+		if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns all entities that match the filter.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Entities() []Entity {
 	return f.queryCache.Entities()
@@ -1194,6 +4020,7 @@ type Query6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [6]uintptr
 	curArchSize    int
 	ids            [6]uint8
@@ -1201,6 +4028,7 @@ type Query6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
 
 // Query returns a new Query6 iterator from the Filter6.
 func (f *Filter6[T1, T2, T3, T4, T5, T6]) Query() Query6[T1, T2, T3, T4, T5, T6] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -1215,12 +4043,12 @@ func (f *Filter6[T1, T2, T3, T4, T5, T6]) Query() Query6[T1, T2, T3, T4, T5, T6]
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		f.curBases[0] = a.compPointers[f.ids[0]]
-		f.curBases[1] = a.compPointers[f.ids[1]]
-		f.curBases[2] = a.compPointers[f.ids[2]]
-		f.curBases[3] = a.compPointers[f.ids[3]]
-		f.curBases[4] = a.compPointers[f.ids[4]]
-		f.curBases[5] = a.compPointers[f.ids[5]]
+		q.curBases[0] = a.compPointers[q.ids[0]]
+		q.curBases[1] = a.compPointers[q.ids[1]]
+		q.curBases[2] = a.compPointers[q.ids[2]]
+		q.curBases[3] = a.compPointers[q.ids[3]]
+		q.curBases[4] = a.compPointers[q.ids[4]]
+		q.curBases[5] = a.compPointers[q.ids[5]]
 		
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -1242,7 +4070,14 @@ func (q *Query6[T1, T2, T3, T4, T5, T6]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query6[T1, T2, T3, T4, T5, T6]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -1260,13 +4095,89 @@ func (q *Query6[T1, T2, T3, T4, T5, T6]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query6[T1, T2, T3, T4, T5, T6]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query6[T1, T2, T3, T4, T5, T6]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query6[T1, T2, T3, T4, T5, T6]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query6[T1, T2, T3, T4, T5, T6]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	q.curBases[0] = a.compPointers[q.ids[0]]
+	q.curBases[1] = a.compPointers[q.ids[1]]
+	q.curBases[2] = a.compPointers[q.ids[2]]
+	q.curBases[3] = a.compPointers[q.ids[3]]
+	q.curBases[4] = a.compPointers[q.ids[4]]
+	q.curBases[5] = a.compPointers[q.ids[5]]
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query6[T1, T2, T3, T4, T5, T6]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query6[T1, T2, T3, T4, T5, T6]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to T1, T2, T3, T4, T5, T6 for the current entity.
 func (q *Query6[T1, T2, T3, T4, T5, T6]) Get() (*T1, *T2, *T3, *T4, *T5, *T6) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return (*T1)(unsafe.Add(q.curBases[0], uintptr(q.curIdx)*q.compSizes[0])),
 		(*T2)(unsafe.Add(q.curBases[1], uintptr(q.curIdx)*q.compSizes[1])),
 		(*T3)(unsafe.Add(q.curBases[2], uintptr(q.curIdx)*q.compSizes[2])),
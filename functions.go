@@ -20,13 +20,21 @@ import (
 func GetComponent[T any](w *World, e Entity) *T {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
+	return getComponentNoLock[T](w, e)
+}
+
+// getComponentNoLock is the no-lock body of GetComponent.
+func getComponentNoLock[T any](w *World, e Entity) *T {
 	if !w.IsValidNoLock(e) {
 		return nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id := w.getCompTypeIDNoLock(reflect.TypeFor[T]())
-	w.components.mu.RUnlock()
+	id := w.getCompTypeID(reflect.TypeFor[T]())
+	w.countRead(id)
+	w.touchEntity(e.ID)
+	if w.transientMask.has(id) {
+		return getTransientNoLock[T](w, id, e)
+	}
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i := id >> 6
 	o := id & 63
@@ -51,14 +59,27 @@ func GetComponent[T any](w *World, e Entity) *T {
 func SetComponent[T any](w *World, e Entity, val T) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if setComponentNoLock(w, e, val) {
+		w.mutationVersion.Add(1)
+	}
+}
+
+// setComponentNoLock is the no-lock body of SetComponent. It does not bump the
+// mutation version and reports whether the entity changed archetype.
+func setComponentNoLock[T any](w *World, e Entity, val T) bool {
 	if !w.IsValidNoLock(e) {
-		return
+		return false
 	}
 	meta := &w.entities.metas[e.ID]
 	t := reflect.TypeFor[T]()
-	w.components.mu.RLock()
-	id := w.getCompTypeIDNoLock(t)
-	w.components.mu.RUnlock()
+	id := w.getCompTypeID(t)
+	w.countWrite(id)
+	w.touchEntity(e.ID)
+	if w.transientMask.has(id) {
+		setTransientNoLock(w, id, e, val)
+		return false
+	}
+	w.markChanged(id, e.ID)
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i := id >> 6
 	o := id & 63
@@ -66,14 +87,14 @@ func SetComponent[T any](w *World, e Entity, val T) {
 		// already has, just set
 		ptr := unsafe.Pointer(uintptr(a.compPointers[id]) + uintptr(meta.index)*a.compSizes[id])
 		*(*T)(ptr) = val
-		return
+		return false
 	}
 	// add new
 	newMask := a.mask
 	newMask.set(id)
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		// build specs only when creating new archetype
 		var tempSpecs [MaxComponentTypes]compSpec
@@ -98,6 +119,7 @@ func SetComponent[T any](w *World, e Entity, val T) {
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
 	// move to target
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -115,7 +137,85 @@ func SetComponent[T any](w *World, e Entity, val T) {
 	// update meta
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	w.markAdded(id, e.ID)
+	return true
+}
+
+// GetOrAdd returns a pointer to the component of type `T` for the given
+// entity, adding it first with the value `init` if the entity does not have it
+// yet. The lookup and the insertion happen under a single lock, so no other
+// goroutine can add or remove the component in between.
+//
+// Adding the component moves the entity to a different archetype, just like
+// SetComponent. If the entity is invalid, this function returns nil.
+//
+// Parameters:
+//   - w: The World where the entity resides.
+//   - e: The Entity to query or modify.
+//   - init: The value used to initialize the component if it is missing.
+//
+// Returns:
+//   - A pointer to the entity's component data (*T), or nil if the entity is
+//     invalid.
+func GetOrAdd[T any](w *World, e Entity, init T) *T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(e) {
+		return nil
+	}
+	meta := &w.entities.metas[e.ID]
+	t := reflect.TypeFor[T]()
+	id := w.getCompTypeID(t)
+	w.countWrite(id)
+	w.touchEntity(e.ID)
+	if w.transientMask.has(id) {
+		if p := getTransientNoLock[T](w, id, e); p != nil {
+			return p
+		}
+		return setTransientNoLock(w, id, e, init)
+	}
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	i := id >> 6
+	o := id & 63
+	if (a.mask[i] & (uint64(1) << uint64(o))) != 0 {
+		return (*T)(unsafe.Add(a.compPointers[id], uintptr(meta.index)*a.compSizes[id]))
+	}
+	newMask := a.mask
+	newMask.set(id)
+	var targetA *archetype
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
+	} else {
+		var tempSpecs [MaxComponentTypes]compSpec
+		count := 0
+		w.components.mu.RLock()
+		for _, cid := range a.compOrder {
+			tempSpecs[count] = compSpec{id: cid, typ: w.components.compIDToType[cid], size: w.components.compIDToSize[cid]}
+			count++
+		}
+		tempSpecs[count] = compSpec{id: id, typ: w.components.compIDToType[id], size: w.components.compIDToSize[id]}
+		count++
+		w.components.mu.RUnlock()
+		specs := tempSpecs[:count]
+		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
+	}
+	w.ensureStorage(targetA)
+	newIdx := targetA.size
+	targetA.entityIDs[newIdx] = e
+	targetA.size++
+	for _, cid := range a.compOrder {
+		src := unsafe.Pointer(uintptr(a.compPointers[cid]) + uintptr(meta.index)*a.compSizes[cid])
+		dst := unsafe.Pointer(uintptr(targetA.compPointers[cid]) + uintptr(newIdx)*targetA.compSizes[cid])
+		memCopy(dst, src, a.compSizes[cid])
+	}
+	ptr := (*T)(unsafe.Add(targetA.compPointers[id], uintptr(newIdx)*targetA.compSizes[id]))
+	*ptr = init
+	w.removeFromArchetype(a, meta)
+	meta.archetypeIndex = targetA.index
+	meta.index = newIdx
+	w.markAdded(id, e.ID)
 	w.mutationVersion.Add(1)
+	return ptr
 }
 
 // RemoveComponent removes the component of type `T` from the specified entity.
@@ -130,26 +230,54 @@ func SetComponent[T any](w *World, e Entity, val T) {
 func RemoveComponent[T any](w *World, e Entity) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if removeComponentNoLock[T](w, e) {
+		w.mutationVersion.Add(1)
+	}
+}
+
+// Get is a shorter name for GetComponent, aligned with the naming of Filter
+// and Builder and of the numbered forms (Get2, Get3, ...).
+func Get[T any](w *World, e Entity) *T {
+	return GetComponent[T](w, e)
+}
+
+// Set is a shorter name for SetComponent, aligned with the naming of Filter
+// and Builder and of the numbered forms (Set2, Set3, ...).
+func Set[T any](w *World, e Entity, val T) {
+	SetComponent(w, e, val)
+}
+
+// Remove is a shorter name for RemoveComponent, aligned with the naming of
+// Filter and Builder and of the numbered forms (Remove2, Remove3, ...).
+func Remove[T any](w *World, e Entity) {
+	RemoveComponent[T](w, e)
+}
+
+// removeComponentNoLock is the no-lock body of RemoveComponent. It does not
+// bump the mutation version and reports whether the entity changed archetype.
+func removeComponentNoLock[T any](w *World, e Entity) bool {
 	if !w.IsValidNoLock(e) {
-		return
+		return false
 	}
 	meta := &w.entities.metas[e.ID]
 	t := reflect.TypeFor[T]()
-	w.components.mu.RLock()
-	id := w.getCompTypeIDNoLock(t)
-	w.components.mu.RUnlock()
+	id := w.getCompTypeID(t)
+	if w.transientMask.has(id) {
+		removeTransientNoLock[T](w, id, e)
+		return false
+	}
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i := id >> 6
 	o := id & 63
 	if (a.mask[i] & (uint64(1) << uint64(o))) == 0 {
-		return
+		return false
 	}
 	// remove
 	newMask := a.mask
 	newMask.unset(id)
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		// build specs only when creating new archetype
 		var tempSpecs [MaxComponentTypes]compSpec
@@ -171,6 +299,7 @@ func RemoveComponent[T any](w *World, e Entity) {
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
 	// move to target
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -188,5 +317,318 @@ func RemoveComponent[T any](w *World, e Entity) {
 	// update meta
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	return true
+}
+
+// ModifyComponents adds (or updates) the component `Add` with the given value
+// and removes the component `Remove` from an entity in a single archetype
+// move. Calling SetComponent then RemoveComponent moves the entity twice,
+// copying every column each time; merging both changes halves the structural
+// work for entities that swap one component for another, such as state
+// machines switching between Walking and Running. For several components or
+// many entities at once, see World.MoveEntities. If the entity is invalid,
+// this function does nothing.
+//
+// Parameters:
+//   - w: The World where the entity resides.
+//   - e: The Entity to modify.
+//   - addVal: The value of the `Add` component to set.
+func ModifyComponents[Add, Remove any](w *World, e Entity, addVal Add) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(e) {
+		return
+	}
+	addID := w.getCompTypeID(reflect.TypeFor[Add]())
+	remID, ok := w.lookupCompTypeID(reflect.TypeFor[Remove]())
+	if !ok || addID == remID || w.transientMask.has(addID) || w.transientMask.has(remID) {
+		// No combined move is possible: apply the changes in turn.
+		moved := setComponentNoLock(w, e, addVal)
+		// An unregistered Remove is on no entity; removing it would register it.
+		if ok && removeComponentNoLock[Remove](w, e) || moved {
+			w.mutationVersion.Add(1)
+		}
+		return
+	}
+	meta := &w.entities.metas[e.ID]
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	mask := a.mask
+	mask.set(addID)
+	mask.unset(remID)
+	w.countWrite(addID)
+	w.touchEntity(e.ID)
+	w.markChanged(addID, e.ID)
+	if mask == a.mask {
+		*(*Add)(unsafe.Add(a.compPointers[addID], uintptr(meta.index)*a.compSizes[addID])) = addVal
+		return
+	}
+	dst := w.transitionNoLock(a, mask)
+	if dst == nil {
+		specs, _ := w.maskSpecs(mask)
+		dst = w.getOrCreateArchetypeNoLock(mask, specs)
+	}
+	w.moveEntityNoLock(meta, dst)
+	*(*Add)(unsafe.Add(dst.compPointers[addID], uintptr(meta.index)*dst.compSizes[addID])) = addVal
+	if !a.mask.has(addID) {
+		w.markAdded(addID, e.ID)
+	}
+	w.mutationVersion.Add(1)
+}
+
+// RemoveAndGet removes the component of type `T` from the entity and returns
+// the value it held, read before the entity moves to its new archetype. The
+// read and the removal happen under a single lock, which avoids the race
+// window of a GetComponent followed by RemoveComponent, e.g. when cleanup code
+// needs a handle stored in the component to release a resource.
+//
+// Parameters:
+//   - w: The World where the entity resides.
+//   - e: The Entity to modify.
+//
+// Returns:
+//   - The removed component value, and true if the entity had the component;
+//     the zero value and false otherwise.
+func RemoveAndGet[T any](w *World, e Entity) (T, bool) {
+	var val T
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(e) {
+		return val, false
+	}
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return val, false
+	}
+	if w.transientMask.has(id) {
+		return removeTransientNoLock[T](w, id, e)
+	}
+	meta := w.entities.metas[e.ID]
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	if !a.mask.has(id) {
+		return val, false
+	}
+	val = *(*T)(unsafe.Add(a.compPointers[id], uintptr(meta.index)*a.compSizes[id]))
+	removeComponentNoLock[T](w, e)
 	w.mutationVersion.Add(1)
+	return val, true
+}
+
+// ComponentSlice returns a copy of every component of type `T` in the world,
+// concatenated across all archetypes that store it. It is the simplest way to
+// collect all values of a component for analytics or serialization, at the
+// cost of an allocation. Archetypes are visited in creation order and entities
+// in storage order.
+//
+// Parameters:
+//   - w: The World to read from.
+//
+// Returns:
+//   - A newly allocated slice of all `T` values, or nil if no entity has `T`.
+func ComponentSlice[T any](w *World) []T {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	i := id >> 6
+	o := id & 63
+	total := 0
+	for _, a := range w.archetypes.archetypes {
+		if (a.mask[i] & (uint64(1) << uint64(o))) != 0 {
+			total += a.size
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	res := make([]T, 0, total)
+	for _, a := range w.archetypes.archetypes {
+		if a.size == 0 || (a.mask[i]&(uint64(1)<<uint64(o))) == 0 {
+			continue
+		}
+		res = append(res, unsafe.Slice((*T)(a.compPointers[id]), a.size)...)
+	}
+	return res
+}
+
+// Prewarm creates the archetype holding only component `T`, without creating
+// any entity, so the first entity of that shape does not pay for allocating
+// its storage. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm[T any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T]())
+}
+
+// AddComponentToAll sets component `T` to `val` on every entity matched by
+// the filter. Entities that already have `T` are updated in place; the others
+// are moved to the archetype extended with `T` in one batched pass per source
+// archetype, instead of one archetype lookup and move per entity as a loop of
+// SetComponent calls would do. The world's mutation version is bumped at most
+// once.
+//
+// Parameters:
+//   - f: The filter selecting the entities to modify.
+//   - val: The component data of type `T` to set.
+func AddComponentToAll[T any](f AnyFilter, val T) {
+	c := f.cache()
+	c.checkWorld()
+	w := c.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	id := w.getCompTypeID(reflect.TypeFor[T]())
+	// Snapshot the matching archetypes: destination archetypes may match the
+	// filter too and must not be visited twice.
+	arches := append([]*archetype(nil), c.matchingArches...)
+	moved := false
+	for _, a := range arches {
+		if a.size == 0 {
+			continue
+		}
+		for _, e := range a.entityIDs[:a.size] {
+			w.markChanged(id, e.ID)
+		}
+		if a.mask.has(id) {
+			col := unsafe.Slice((*T)(a.compPointers[id]), a.size)
+			for i := range col {
+				col[i] = val
+			}
+			continue
+		}
+		dst := w.neighbourArchetypeNoLock(a, id, true)
+		n := a.size
+		start := w.moveAllNoLock(a, dst)
+		col := unsafe.Slice((*T)(unsafe.Add(dst.compPointers[id], uintptr(start)*dst.compSizes[id])), n)
+		for i := range col {
+			col[i] = val
+		}
+		for _, e := range dst.entityIDs[start : start+n] {
+			w.markAdded(id, e.ID)
+		}
+		moved = true
+	}
+	if moved {
+		w.mutationVersion.Add(1)
+	}
+}
+
+// RemoveComponentFromAll removes component `T` from every entity matched by the
+// filter that has it. Entities are moved to the archetype without `T` in one
+// batched pass per source archetype, which makes it the efficient way to clear
+// a per-frame tag component from everything carrying it. The world's mutation
+// version is bumped at most once.
+//
+// Parameters:
+//   - f: The filter selecting the entities to modify.
+func RemoveComponentFromAll[T any](f AnyFilter) {
+	c := f.cache()
+	c.checkWorld()
+	w := c.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return
+	}
+	arches := append([]*archetype(nil), c.matchingArches...)
+	moved := false
+	for _, a := range arches {
+		if a.size == 0 || !a.mask.has(id) {
+			continue
+		}
+		w.moveAllNoLock(a, w.neighbourArchetypeNoLock(a, id, false))
+		moved = true
+	}
+	if moved {
+		w.mutationVersion.Add(1)
+	}
+}
+
+// RemoveComponentBatch removes component `T` from each listed entity that has
+// it. The entities are grouped by source archetype first, so the destination
+// archetype and the list of columns to copy are resolved once per group
+// instead of once per entity as a loop of RemoveComponent calls would do; this
+// is the efficient way to clear a component from an arbitrary set of entities
+// that is not described by a filter. Invalid entities, entities without `T`
+// and duplicates are ignored. The world's mutation version is bumped at most
+// once.
+//
+// Parameters:
+//   - w: The World where the entities reside.
+//   - entities: The entities to modify.
+func RemoveComponentBatch[T any](w *World, entities []Entity) {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.transientMask.has(id) {
+		for _, e := range entities {
+			removeTransientNoLock[T](w, id, e)
+		}
+		return
+	}
+	type group struct {
+		src *archetype
+		ids []uint32
+	}
+	var groups []group
+	groupOf := make(map[int]int) // source archetype index -> position in groups
+	for _, e := range entities {
+		if !w.IsValidNoLock(e) {
+			continue
+		}
+		ai := w.entities.metas[e.ID].archetypeIndex
+		a := w.archetypes.archetypes[ai]
+		if !a.mask.has(id) {
+			continue
+		}
+		g, ok := groupOf[ai]
+		if !ok {
+			g = len(groups)
+			groupOf[ai] = g
+			groups = append(groups, group{src: a})
+		}
+		groups[g].ids = append(groups[g].ids, e.ID)
+	}
+	cols := make([]uint8, 0, MaxComponentTypes)
+	for _, g := range groups {
+		src := g.src
+		dst := w.neighbourArchetypeNoLock(src, id, false)
+		w.ensureStorage(dst)
+		cols = cols[:0]
+		for _, cid := range src.compOrder {
+			if cid != id {
+				cols = append(cols, cid)
+			}
+		}
+		for _, eid := range g.ids {
+			meta := &w.entities.metas[eid]
+			if meta.archetypeIndex != src.index {
+				continue // listed twice
+			}
+			newIdx := dst.size
+			dst.entityIDs[newIdx] = src.entityIDs[meta.index]
+			dst.size++
+			for _, cid := range cols {
+				size := src.compSizes[cid]
+				memCopy(unsafe.Add(dst.compPointers[cid], uintptr(newIdx)*size), unsafe.Add(src.compPointers[cid], uintptr(meta.index)*size), size)
+			}
+			w.removeFromArchetype(src, meta)
+			meta.archetypeIndex = dst.index
+			meta.index = newIdx
+		}
+	}
+	if len(groups) > 0 {
+		w.mutationVersion.Add(1)
+	}
 }
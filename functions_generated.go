@@ -24,15 +24,17 @@ func GetComponent2[T1 any, T2 any](w *World, e Entity) (*T1, *T2) {
 		return nil, nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(reflect.TypeFor[T1]())
-	id2 := w.getCompTypeIDNoLock(reflect.TypeFor[T2]())
+	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
+	id2 := w.getCompTypeID(reflect.TypeFor[T2]())
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 {
 		panic("ecs: duplicate component types in GetComponent2")
 	}
+	w.countRead(id1)
+	w.countRead(id2)
+	w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -68,15 +70,19 @@ func SetComponent2[T1 any, T2 any](w *World, e Entity, v1 T1, v2 T2) {
 	t1 := reflect.TypeFor[T1]()
 	t2 := reflect.TypeFor[T2]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 {
 		panic("ecs: duplicate component types in SetComponent2")
 	}
+	w.countWrite(id1)
+	w.countWrite(id2)
+	w.touchEntity(e.ID)
+	w.markChanged(id1, e.ID)
+	w.markChanged(id2, e.ID)
+	
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -103,8 +109,8 @@ func SetComponent2[T1 any, T2 any](w *World, e Entity, v1 T1, v2 T2) {
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -126,6 +132,7 @@ func SetComponent2[T1 any, T2 any](w *World, e Entity, v1 T1, v2 T2) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -142,6 +149,13 @@ func SetComponent2[T1 any, T2 any](w *World, e Entity, v1 T1, v2 T2) {
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	if !has1 {
+		w.markAdded(id1, e.ID)
+	}
+	if !has2 {
+		w.markAdded(id2, e.ID)
+	}
+	
 	w.mutationVersion.Add(1)
 }
 
@@ -165,11 +179,9 @@ func RemoveComponent2[T1 any, T2 any](w *World, e Entity) {
 	t1 := reflect.TypeFor[T1]()
 	t2 := reflect.TypeFor[T2]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 {
 		panic("ecs: duplicate component types in RemoveComponent2")
@@ -191,8 +203,8 @@ func RemoveComponent2[T1 any, T2 any](w *World, e Entity) {
 	newMask.unset(id2)
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -208,6 +220,7 @@ func RemoveComponent2[T1 any, T2 any](w *World, e Entity) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -225,6 +238,33 @@ func RemoveComponent2[T1 any, T2 any](w *World, e Entity) {
 	w.mutationVersion.Add(1)
 }
 
+// Prewarm2 creates the archetype holding exactly the 2 components
+// (T1, T2), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm2[T1 any, T2 any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T1](), reflect.TypeFor[T2]())
+}
+
+// Get2 is a shorter name for GetComponent2, aligned with the naming of
+// Filter2 and Builder2.
+func Get2[T1 any, T2 any](w *World, e Entity) (*T1, *T2) {
+	return GetComponent2[T1, T2](w, e)
+}
+
+// Set2 is a shorter name for SetComponent2, aligned with the naming of
+// Filter2 and Builder2.
+func Set2[T1 any, T2 any](w *World, e Entity, v1 T1, v2 T2) {
+	SetComponent2(w, e, v1, v2)
+}
+
+// Remove2 is a shorter name for RemoveComponent2, aligned with the
+// naming of Filter2 and Builder2.
+func Remove2[T1 any, T2 any](w *World, e Entity) {
+	RemoveComponent2[T1, T2](w, e)
+}
+
 // GetComponent3 retrieves pointers to the 3 components of type
 // (T1, T2, T3) for the given entity.
 //
@@ -244,16 +284,19 @@ func GetComponent3[T1 any, T2 any, T3 any](w *World, e Entity) (*T1, *T2, *T3) {
 		return nil, nil, nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(reflect.TypeFor[T1]())
-	id2 := w.getCompTypeIDNoLock(reflect.TypeFor[T2]())
-	id3 := w.getCompTypeIDNoLock(reflect.TypeFor[T3]())
+	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
+	id2 := w.getCompTypeID(reflect.TypeFor[T2]())
+	id3 := w.getCompTypeID(reflect.TypeFor[T3]())
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 {
 		panic("ecs: duplicate component types in GetComponent3")
 	}
+	w.countRead(id1)
+	w.countRead(id2)
+	w.countRead(id3)
+	w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -294,16 +337,22 @@ func SetComponent3[T1 any, T2 any, T3 any](w *World, e Entity, v1 T1, v2 T2, v3
 	t2 := reflect.TypeFor[T2]()
 	t3 := reflect.TypeFor[T3]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 {
 		panic("ecs: duplicate component types in SetComponent3")
 	}
+	w.countWrite(id1)
+	w.countWrite(id2)
+	w.countWrite(id3)
+	w.touchEntity(e.ID)
+	w.markChanged(id1, e.ID)
+	w.markChanged(id2, e.ID)
+	w.markChanged(id3, e.ID)
+	
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -338,8 +387,8 @@ func SetComponent3[T1 any, T2 any, T3 any](w *World, e Entity, v1 T1, v2 T2, v3
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -365,6 +414,7 @@ func SetComponent3[T1 any, T2 any, T3 any](w *World, e Entity, v1 T1, v2 T2, v3
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -383,6 +433,16 @@ func SetComponent3[T1 any, T2 any, T3 any](w *World, e Entity, v1 T1, v2 T2, v3
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	if !has1 {
+		w.markAdded(id1, e.ID)
+	}
+	if !has2 {
+		w.markAdded(id2, e.ID)
+	}
+	if !has3 {
+		w.markAdded(id3, e.ID)
+	}
+	
 	w.mutationVersion.Add(1)
 }
 
@@ -407,12 +467,10 @@ func RemoveComponent3[T1 any, T2 any, T3 any](w *World, e Entity) {
 	t2 := reflect.TypeFor[T2]()
 	t3 := reflect.TypeFor[T3]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 {
 		panic("ecs: duplicate component types in RemoveComponent3")
@@ -438,8 +496,8 @@ func RemoveComponent3[T1 any, T2 any, T3 any](w *World, e Entity) {
 	newMask.unset(id3)
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -455,6 +513,7 @@ func RemoveComponent3[T1 any, T2 any, T3 any](w *World, e Entity) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -472,6 +531,33 @@ func RemoveComponent3[T1 any, T2 any, T3 any](w *World, e Entity) {
 	w.mutationVersion.Add(1)
 }
 
+// Prewarm3 creates the archetype holding exactly the 3 components
+// (T1, T2, T3), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm3[T1 any, T2 any, T3 any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T1](), reflect.TypeFor[T2](), reflect.TypeFor[T3]())
+}
+
+// Get3 is a shorter name for GetComponent3, aligned with the naming of
+// Filter3 and Builder3.
+func Get3[T1 any, T2 any, T3 any](w *World, e Entity) (*T1, *T2, *T3) {
+	return GetComponent3[T1, T2, T3](w, e)
+}
+
+// Set3 is a shorter name for SetComponent3, aligned with the naming of
+// Filter3 and Builder3.
+func Set3[T1 any, T2 any, T3 any](w *World, e Entity, v1 T1, v2 T2, v3 T3) {
+	SetComponent3(w, e, v1, v2, v3)
+}
+
+// Remove3 is a shorter name for RemoveComponent3, aligned with the
+// naming of Filter3 and Builder3.
+func Remove3[T1 any, T2 any, T3 any](w *World, e Entity) {
+	RemoveComponent3[T1, T2, T3](w, e)
+}
+
 // GetComponent4 retrieves pointers to the 4 components of type
 // (T1, T2, T3, T4) for the given entity.
 //
@@ -491,17 +577,21 @@ func GetComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) (*T1, *T2
 		return nil, nil, nil, nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(reflect.TypeFor[T1]())
-	id2 := w.getCompTypeIDNoLock(reflect.TypeFor[T2]())
-	id3 := w.getCompTypeIDNoLock(reflect.TypeFor[T3]())
-	id4 := w.getCompTypeIDNoLock(reflect.TypeFor[T4]())
+	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
+	id2 := w.getCompTypeID(reflect.TypeFor[T2]())
+	id3 := w.getCompTypeID(reflect.TypeFor[T3]())
+	id4 := w.getCompTypeID(reflect.TypeFor[T4]())
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 {
 		panic("ecs: duplicate component types in GetComponent4")
 	}
+	w.countRead(id1)
+	w.countRead(id2)
+	w.countRead(id3)
+	w.countRead(id4)
+	w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -547,17 +637,25 @@ func SetComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity, v1 T1, v2
 	t3 := reflect.TypeFor[T3]()
 	t4 := reflect.TypeFor[T4]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 {
 		panic("ecs: duplicate component types in SetComponent4")
 	}
+	w.countWrite(id1)
+	w.countWrite(id2)
+	w.countWrite(id3)
+	w.countWrite(id4)
+	w.touchEntity(e.ID)
+	w.markChanged(id1, e.ID)
+	w.markChanged(id2, e.ID)
+	w.markChanged(id3, e.ID)
+	w.markChanged(id4, e.ID)
+	
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -600,8 +698,8 @@ func SetComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity, v1 T1, v2
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -631,6 +729,7 @@ func SetComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity, v1 T1, v2
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -651,6 +750,19 @@ func SetComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity, v1 T1, v2
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	if !has1 {
+		w.markAdded(id1, e.ID)
+	}
+	if !has2 {
+		w.markAdded(id2, e.ID)
+	}
+	if !has3 {
+		w.markAdded(id3, e.ID)
+	}
+	if !has4 {
+		w.markAdded(id4, e.ID)
+	}
+	
 	w.mutationVersion.Add(1)
 }
 
@@ -676,13 +788,11 @@ func RemoveComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) {
 	t3 := reflect.TypeFor[T3]()
 	t4 := reflect.TypeFor[T4]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 {
 		panic("ecs: duplicate component types in RemoveComponent4")
@@ -712,8 +822,8 @@ func RemoveComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) {
 	newMask.unset(id4)
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -729,6 +839,7 @@ func RemoveComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -746,6 +857,33 @@ func RemoveComponent4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) {
 	w.mutationVersion.Add(1)
 }
 
+// Prewarm4 creates the archetype holding exactly the 4 components
+// (T1, T2, T3, T4), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm4[T1 any, T2 any, T3 any, T4 any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T1](), reflect.TypeFor[T2](), reflect.TypeFor[T3](), reflect.TypeFor[T4]())
+}
+
+// Get4 is a shorter name for GetComponent4, aligned with the naming of
+// Filter4 and Builder4.
+func Get4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) (*T1, *T2, *T3, *T4) {
+	return GetComponent4[T1, T2, T3, T4](w, e)
+}
+
+// Set4 is a shorter name for SetComponent4, aligned with the naming of
+// Filter4 and Builder4.
+func Set4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity, v1 T1, v2 T2, v3 T3, v4 T4) {
+	SetComponent4(w, e, v1, v2, v3, v4)
+}
+
+// Remove4 is a shorter name for RemoveComponent4, aligned with the
+// naming of Filter4 and Builder4.
+func Remove4[T1 any, T2 any, T3 any, T4 any](w *World, e Entity) {
+	RemoveComponent4[T1, T2, T3, T4](w, e)
+}
+
 // GetComponent5 retrieves pointers to the 5 components of type
 // (T1, T2, T3, T4, T5) for the given entity.
 //
@@ -765,18 +903,23 @@ func GetComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity) (
 		return nil, nil, nil, nil, nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(reflect.TypeFor[T1]())
-	id2 := w.getCompTypeIDNoLock(reflect.TypeFor[T2]())
-	id3 := w.getCompTypeIDNoLock(reflect.TypeFor[T3]())
-	id4 := w.getCompTypeIDNoLock(reflect.TypeFor[T4]())
-	id5 := w.getCompTypeIDNoLock(reflect.TypeFor[T5]())
-	
-	w.components.mu.RUnlock()
+	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
+	id2 := w.getCompTypeID(reflect.TypeFor[T2]())
+	id3 := w.getCompTypeID(reflect.TypeFor[T3]())
+	id4 := w.getCompTypeID(reflect.TypeFor[T4]())
+	id5 := w.getCompTypeID(reflect.TypeFor[T5]())
+	
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 {
 		panic("ecs: duplicate component types in GetComponent5")
 	}
+	w.countRead(id1)
+	w.countRead(id2)
+	w.countRead(id3)
+	w.countRead(id4)
+	w.countRead(id5)
+	w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -827,18 +970,28 @@ func SetComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity, v
 	t4 := reflect.TypeFor[T4]()
 	t5 := reflect.TypeFor[T5]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 {
 		panic("ecs: duplicate component types in SetComponent5")
 	}
+	w.countWrite(id1)
+	w.countWrite(id2)
+	w.countWrite(id3)
+	w.countWrite(id4)
+	w.countWrite(id5)
+	w.touchEntity(e.ID)
+	w.markChanged(id1, e.ID)
+	w.markChanged(id2, e.ID)
+	w.markChanged(id3, e.ID)
+	w.markChanged(id4, e.ID)
+	w.markChanged(id5, e.ID)
+	
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -889,8 +1042,8 @@ func SetComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity, v
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -924,6 +1077,7 @@ func SetComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity, v
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -946,6 +1100,22 @@ func SetComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity, v
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	if !has1 {
+		w.markAdded(id1, e.ID)
+	}
+	if !has2 {
+		w.markAdded(id2, e.ID)
+	}
+	if !has3 {
+		w.markAdded(id3, e.ID)
+	}
+	if !has4 {
+		w.markAdded(id4, e.ID)
+	}
+	if !has5 {
+		w.markAdded(id5, e.ID)
+	}
+	
 	w.mutationVersion.Add(1)
 }
 
@@ -972,14 +1142,12 @@ func RemoveComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity
 	t4 := reflect.TypeFor[T4]()
 	t5 := reflect.TypeFor[T5]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 {
 		panic("ecs: duplicate component types in RemoveComponent5")
@@ -1013,8 +1181,8 @@ func RemoveComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity
 	newMask.unset(id5)
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -1030,6 +1198,7 @@ func RemoveComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -1047,6 +1216,33 @@ func RemoveComponent5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity
 	w.mutationVersion.Add(1)
 }
 
+// Prewarm5 creates the archetype holding exactly the 5 components
+// (T1, T2, T3, T4, T5), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T1](), reflect.TypeFor[T2](), reflect.TypeFor[T3](), reflect.TypeFor[T4](), reflect.TypeFor[T5]())
+}
+
+// Get5 is a shorter name for GetComponent5, aligned with the naming of
+// Filter5 and Builder5.
+func Get5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity) (*T1, *T2, *T3, *T4, *T5) {
+	return GetComponent5[T1, T2, T3, T4, T5](w, e)
+}
+
+// Set5 is a shorter name for SetComponent5, aligned with the naming of
+// Filter5 and Builder5.
+func Set5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity, v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) {
+	SetComponent5(w, e, v1, v2, v3, v4, v5)
+}
+
+// Remove5 is a shorter name for RemoveComponent5, aligned with the
+// naming of Filter5 and Builder5.
+func Remove5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World, e Entity) {
+	RemoveComponent5[T1, T2, T3, T4, T5](w, e)
+}
+
 // GetComponent6 retrieves pointers to the 6 components of type
 // (T1, T2, T3, T4, T5, T6) for the given entity.
 //
@@ -1066,19 +1262,25 @@ func GetComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e E
 		return nil, nil, nil, nil, nil, nil
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(reflect.TypeFor[T1]())
-	id2 := w.getCompTypeIDNoLock(reflect.TypeFor[T2]())
-	id3 := w.getCompTypeIDNoLock(reflect.TypeFor[T3]())
-	id4 := w.getCompTypeIDNoLock(reflect.TypeFor[T4]())
-	id5 := w.getCompTypeIDNoLock(reflect.TypeFor[T5]())
-	id6 := w.getCompTypeIDNoLock(reflect.TypeFor[T6]())
-	
-	w.components.mu.RUnlock()
+	id1 := w.getCompTypeID(reflect.TypeFor[T1]())
+	id2 := w.getCompTypeID(reflect.TypeFor[T2]())
+	id3 := w.getCompTypeID(reflect.TypeFor[T3]())
+	id4 := w.getCompTypeID(reflect.TypeFor[T4]())
+	id5 := w.getCompTypeID(reflect.TypeFor[T5]())
+	id6 := w.getCompTypeID(reflect.TypeFor[T6]())
+	
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 || id6 == id1 || id6 == id2 || id6 == id3 || id6 == id4 || id6 == id5 {
 		panic("ecs: duplicate component types in GetComponent6")
 	}
+	w.countRead(id1)
+	w.countRead(id2)
+	w.countRead(id3)
+	w.countRead(id4)
+	w.countRead(id5)
+	w.countRead(id6)
+	w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -1134,19 +1336,31 @@ func SetComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e E
 	t5 := reflect.TypeFor[T5]()
 	t6 := reflect.TypeFor[T6]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
-	id6 := w.getCompTypeIDNoLock(t6)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
+	id6 := w.getCompTypeID(t6)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 || id6 == id1 || id6 == id2 || id6 == id3 || id6 == id4 || id6 == id5 {
 		panic("ecs: duplicate component types in SetComponent6")
 	}
+	w.countWrite(id1)
+	w.countWrite(id2)
+	w.countWrite(id3)
+	w.countWrite(id4)
+	w.countWrite(id5)
+	w.countWrite(id6)
+	w.touchEntity(e.ID)
+	w.markChanged(id1, e.ID)
+	w.markChanged(id2, e.ID)
+	w.markChanged(id3, e.ID)
+	w.markChanged(id4, e.ID)
+	w.markChanged(id5, e.ID)
+	w.markChanged(id6, e.ID)
+	
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	i1 := id1 >> 6
 	o1 := id1 & 63
@@ -1205,8 +1419,8 @@ func SetComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e E
 	}
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -1244,6 +1458,7 @@ func SetComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e E
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -1268,6 +1483,25 @@ func SetComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e E
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	if !has1 {
+		w.markAdded(id1, e.ID)
+	}
+	if !has2 {
+		w.markAdded(id2, e.ID)
+	}
+	if !has3 {
+		w.markAdded(id3, e.ID)
+	}
+	if !has4 {
+		w.markAdded(id4, e.ID)
+	}
+	if !has5 {
+		w.markAdded(id5, e.ID)
+	}
+	if !has6 {
+		w.markAdded(id6, e.ID)
+	}
+	
 	w.mutationVersion.Add(1)
 }
 
@@ -1295,15 +1529,13 @@ func RemoveComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World,
 	t5 := reflect.TypeFor[T5]()
 	t6 := reflect.TypeFor[T6]()
 	
-	w.components.mu.RLock()
-	id1 := w.getCompTypeIDNoLock(t1)
-	id2 := w.getCompTypeIDNoLock(t2)
-	id3 := w.getCompTypeIDNoLock(t3)
-	id4 := w.getCompTypeIDNoLock(t4)
-	id5 := w.getCompTypeIDNoLock(t5)
-	id6 := w.getCompTypeIDNoLock(t6)
+	id1 := w.getCompTypeID(t1)
+	id2 := w.getCompTypeID(t2)
+	id3 := w.getCompTypeID(t3)
+	id4 := w.getCompTypeID(t4)
+	id5 := w.getCompTypeID(t5)
+	id6 := w.getCompTypeID(t6)
 	
-	w.components.mu.RUnlock()
 
 	if id2 == id1 || id3 == id1 || id3 == id2 || id4 == id1 || id4 == id2 || id4 == id3 || id5 == id1 || id5 == id2 || id5 == id3 || id5 == id4 || id6 == id1 || id6 == id2 || id6 == id3 || id6 == id4 || id6 == id5 {
 		panic("ecs: duplicate component types in RemoveComponent6")
@@ -1341,8 +1573,8 @@ func RemoveComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World,
 	newMask.unset(id6)
 	
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -1358,6 +1590,7 @@ func RemoveComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World,
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -1375,3 +1608,30 @@ func RemoveComponent6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World,
 	w.mutationVersion.Add(1)
 }
 
+// Prewarm6 creates the archetype holding exactly the 6 components
+// (T1, T2, T3, T4, T5, T6), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) {
+	w.PrewarmArchetype(reflect.TypeFor[T1](), reflect.TypeFor[T2](), reflect.TypeFor[T3](), reflect.TypeFor[T4](), reflect.TypeFor[T5](), reflect.TypeFor[T6]())
+}
+
+// Get6 is a shorter name for GetComponent6, aligned with the naming of
+// Filter6 and Builder6.
+func Get6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e Entity) (*T1, *T2, *T3, *T4, *T5, *T6) {
+	return GetComponent6[T1, T2, T3, T4, T5, T6](w, e)
+}
+
+// Set6 is a shorter name for SetComponent6, aligned with the naming of
+// Filter6 and Builder6.
+func Set6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e Entity, v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) {
+	SetComponent6(w, e, v1, v2, v3, v4, v5, v6)
+}
+
+// Remove6 is a shorter name for RemoveComponent6, aligned with the
+// naming of Filter6 and Builder6.
+func Remove6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World, e Entity) {
+	RemoveComponent6[T1, T2, T3, T4, T5, T6](w, e)
+}
+
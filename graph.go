@@ -0,0 +1,81 @@
+package teishoku
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Mask is the set of component IDs that identifies an archetype. It is
+// exposed for debugging and tooling, e.g. as the key type returned by
+// ArchetypeGraph.
+type Mask = bitmask256
+
+// ArchetypeGraph describes the archetypes currently existing in the world and
+// the single-component transitions between them. Every archetype is a key of
+// the returned map, and its value lists the archetypes reachable by adding or
+// removing exactly one component. Visualizing this graph helps diagnose
+// archetype fragmentation, such as a component that is added and removed
+// every frame.
+//
+// Returns:
+//   - A map from each archetype's mask to the masks of its neighbours.
+func (w *World) ArchetypeGraph() map[Mask][]Mask {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	arches := w.archetypes.archetypes
+	graph := make(map[Mask][]Mask, len(arches))
+	for _, a := range arches {
+		edges := []Mask{}
+		for _, b := range arches {
+			if maskDistance(a.mask, b.mask) == 1 {
+				edges = append(edges, b.mask)
+			}
+		}
+		graph[a.mask] = edges
+	}
+	return graph
+}
+
+// ArchetypeGraphNames is the human-readable form of ArchetypeGraph. Each
+// archetype is named after its component types in registration order, e.g.
+// "[teishoku.Position teishoku.Velocity]", and the empty archetype is "[]".
+//
+// Returns:
+//   - A map from each archetype's name to the names of its neighbours.
+func (w *World) ArchetypeGraphNames() map[string][]string {
+	graph := w.ArchetypeGraph()
+	names := make(map[string][]string, len(graph))
+	for m, edges := range graph {
+		n := make([]string, len(edges))
+		for i, e := range edges {
+			n[i] = w.maskName(e)
+		}
+		names[w.maskName(m)] = n
+	}
+	return names
+}
+
+// maskName returns the component type names contained in m.
+func (w *World) maskName(m Mask) string {
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for id := 0; id < int(w.components.nextCompTypeID); id++ {
+		if !m.has(uint8(id)) {
+			continue
+		}
+		if sb.Len() > 1 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(w.components.compIDToType[id].String())
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// maskDistance returns the number of components by which a and b differ.
+func maskDistance(a, b Mask) int {
+	return bits.OnesCount64(a[0]^b[0]) + bits.OnesCount64(a[1]^b[1]) +
+		bits.OnesCount64(a[2]^b[2]) + bits.OnesCount64(a[3]^b[3])
+}
@@ -0,0 +1,142 @@
+package teishoku
+
+import "math"
+
+// Grid is a uniform spatial hash of entities, built from a position component
+// with BuildGrid. It answers radius queries by visiting only the cells that
+// overlap the query circle, instead of testing every entity.
+//
+// A Grid is a snapshot: it reflects the positions at the time of the last
+// Rebuild, so a moving world calls Rebuild once per frame before querying. It
+// is not safe for concurrent use while rebuilding.
+type Grid struct {
+	cellSize float64
+	cells    map[gridCell][]gridEntry
+	rebuild  func(g *Grid)
+	count    int
+}
+
+// gridCell is the integer coordinate of a Grid cell.
+type gridCell struct {
+	x, y int64
+}
+
+// gridEntry is an entity stored in a cell, with the position it was indexed
+// at.
+type gridEntry struct {
+	e    Entity
+	x, y float64
+}
+
+// BuildGrid indexes every entity that has the component `P` into a grid of
+// square cells of the given size, using extract to read the entity's position
+// from its component. The grid keeps a filter over `P`, so later calls to
+// Rebuild refresh it without resolving anything again.
+//
+// A cell size close to the typical query radius keeps queries to a handful of
+// cells.
+//
+// Parameters:
+//   - w: The World whose entities to index.
+//   - cellSize: The side length of a cell, in position units. It must be
+//     positive.
+//   - extract: Returns the position stored in the component.
+//
+// Returns:
+//   - The built Grid.
+func BuildGrid[P any](w *World, cellSize float64, extract func(*P) (x, y float64)) *Grid {
+	if !(cellSize > 0) {
+		panic("ecs: BuildGrid requires a positive cell size")
+	}
+	f := NewFilter[P](w)
+	g := &Grid{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]gridEntry),
+		rebuild: func(g *Grid) {
+			f.Reset()
+			for f.Next() {
+				x, y := extract(f.Get())
+				g.insert(f.Entity(), x, y)
+			}
+		},
+	}
+	g.Rebuild()
+	return g
+}
+
+// Rebuild re-indexes the entities from their current positions. Cell storage
+// is reused between rebuilds, so a steady world rebuilds without allocating.
+func (g *Grid) Rebuild() {
+	for c, entries := range g.cells {
+		g.cells[c] = entries[:0]
+	}
+	g.count = 0
+	g.rebuild(g)
+}
+
+// cellOf returns the cell containing the position.
+func (g *Grid) cellOf(x, y float64) gridCell {
+	return gridCell{int64(math.Floor(x / g.cellSize)), int64(math.Floor(y / g.cellSize))}
+}
+
+func (g *Grid) insert(e Entity, x, y float64) {
+	c := g.cellOf(x, y)
+	g.cells[c] = append(g.cells[c], gridEntry{e: e, x: x, y: y})
+	g.count++
+}
+
+// Len returns the number of entities indexed by the last Rebuild.
+func (g *Grid) Len() int {
+	return g.count
+}
+
+// Query returns the entities whose indexed position lies within radius of
+// (x, y), boundary included, in no particular order. A query covering more
+// cells than the grid holds walks the grid's cells instead of the query box,
+// so its cost is bounded by the size of the grid.
+//
+// Parameters:
+//   - x, y: The center of the query circle.
+//   - radius: The radius of the query circle.
+//
+// Returns:
+//   - A newly allocated slice of the entities found, or nil if there are none
+//     or if an argument is not finite.
+func (g *Grid) Query(x, y, radius float64) []Entity {
+	if radius < 0 || !isFinite(x) || !isFinite(y) || !isFinite(radius) {
+		return nil
+	}
+	r2 := radius * radius
+	x0, x1 := math.Floor((x-radius)/g.cellSize), math.Floor((x+radius)/g.cellSize)
+	y0, y1 := math.Floor((y-radius)/g.cellSize), math.Floor((y+radius)/g.cellSize)
+	var found []Entity
+	// Cell coordinates beyond ±2^62 cannot be walked without overflowing.
+	if (x1-x0+1)*(y1-y0+1) > float64(len(g.cells)) || max(-x0, x1, -y0, y1) >= 1<<62 {
+		for _, entries := range g.cells {
+			found = appendWithin(found, entries, x, y, r2)
+		}
+		return found
+	}
+	for cx := int64(x0); cx <= int64(x1); cx++ {
+		for cy := int64(y0); cy <= int64(y1); cy++ {
+			found = appendWithin(found, g.cells[gridCell{cx, cy}], x, y, r2)
+		}
+	}
+	return found
+}
+
+// appendWithin appends to found the entities of entries lying within the
+// squared distance r2 of (x, y).
+func appendWithin(found []Entity, entries []gridEntry, x, y, r2 float64) []Entity {
+	for _, en := range entries {
+		if dx, dy := en.x-x, en.y-y; dx*dx+dy*dy <= r2 {
+			found = append(found, en.e)
+		}
+	}
+	return found
+}
+
+// isFinite reports whether v is neither infinite nor NaN.
+func isFinite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
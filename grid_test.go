@@ -0,0 +1,61 @@
+package teishoku
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestGridQuery(t *testing.T) {
+	w := NewWorld(64)
+	rng := rand.New(rand.NewPCG(7, 0))
+	var ents []Entity
+	for range 200 {
+		e := w.CreateEntity()
+		SetComponent(w, e, Position{X: rng.Float32()*200 - 100, Y: rng.Float32()*200 - 100})
+		ents = append(ents, e)
+	}
+	SetComponent(w, w.CreateEntity(), Velocity{}) // not indexed
+	extract := func(p *Position) (float64, float64) { return float64(p.X), float64(p.Y) }
+	g := BuildGrid(w, 10, extract)
+	if g.Len() != 200 {
+		t.Fatalf("expected 200 indexed entities, got %d", g.Len())
+	}
+
+	brute := func(x, y, r float64) []Entity {
+		var res []Entity
+		for _, e := range ents {
+			px, py := extract(GetComponent[Position](w, e))
+			if (px-x)*(px-x)+(py-y)*(py-y) <= r*r {
+				res = append(res, e)
+			}
+		}
+		return res
+	}
+	byID := func(a, b Entity) int { return int(a.ID) - int(b.ID) }
+	for _, q := range [][3]float64{{0, 0, 15}, {-95, 40, 30}, {50, -50, 0.5}, {0, 0, 300}, {0, 0, 1e6}, {math.MaxFloat64, 0, 1}} {
+		got, want := g.Query(q[0], q[1], q[2]), brute(q[0], q[1], q[2])
+		slices.SortFunc(got, byID)
+		slices.SortFunc(want, byID)
+		if !slices.Equal(got, want) {
+			t.Errorf("Query%v: got %d entities, want %d", q, len(got), len(want))
+		}
+	}
+
+	for _, q := range [][3]float64{{math.Inf(1), 0, 1}, {0, math.NaN(), 1}, {0, 0, math.Inf(1)}} {
+		if got := g.Query(q[0], q[1], q[2]); got != nil {
+			t.Errorf("Query%v: expected nil for a non-finite argument, got %d entities", q, len(got))
+		}
+	}
+
+	// Positions only move in the grid on Rebuild.
+	SetComponent(w, ents[0], Position{X: 500, Y: 500})
+	if slices.Contains(g.Query(500, 500, 1), ents[0]) {
+		t.Error("the grid should be a snapshot until Rebuild")
+	}
+	g.Rebuild()
+	if !slices.Equal(g.Query(500, 500, 1), []Entity{ents[0]}) || g.Len() != 200 {
+		t.Error("expected the moved entity to be found after Rebuild")
+	}
+}
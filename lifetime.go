@@ -0,0 +1,62 @@
+package teishoku
+
+import "unsafe"
+
+// Lifetime is a built-in component for entities that live for a limited
+// time, such as particles, projectiles or temporary buffs. World.Tick counts
+// Remaining down and removes the entity once it reaches zero.
+type Lifetime struct {
+	Remaining float64 // time left to live, in the unit of Tick's dt
+}
+
+// Tick advances the world by dt: it subtracts dt from the Lifetime of every
+// entity that has one and removes the entities whose lifetime ran out, then
+// advances the frame counter used by AuditLeaks. The countdown and the
+// removals happen in a single pass under one acquisition of the write lock,
+// with a cached filter, so calling it once per frame replaces the countdown
+// loop every game otherwise writes by hand. Expired entities are removed like
+// with RemoveEntity, including the entities they own.
+//
+// Parameters:
+//   - dt: The elapsed time since the previous tick.
+//
+// Returns:
+//   - The number of expired entities removed, not counting the entities they
+//     owned.
+func (w *World) Tick(dt float64) int {
+	w.lifetimeOnce.Do(func() {
+		w.lifetimes = NewFilter[Lifetime](w)
+	})
+	f := w.lifetimes
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkOpen()
+	defer w.AdvanceFrame()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	expired := w.expired[:0]
+	for _, a := range f.matchingArches {
+		if a.size == 0 {
+			continue
+		}
+		col := unsafe.Slice((*Lifetime)(a.compPointers[f.compID]), a.size)
+		for k := range col {
+			col[k].Remaining -= dt
+			if col[k].Remaining <= 0 {
+				expired = append(expired, a.entityIDs[k])
+			}
+		}
+	}
+	removed := 0
+	for _, e := range expired {
+		if w.removeEntityNoLock(e) { // false if an earlier owner took it along
+			removed++
+		}
+	}
+	w.expired = expired[:0]
+	if len(expired) > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return removed
+}
@@ -0,0 +1,39 @@
+package teishoku
+
+import "testing"
+
+func TestWorldTickLifetime(t *testing.T) {
+	w := NewWorld(8)
+	short := w.CreateEntity()
+	SetComponent2(w, short, Lifetime{Remaining: 0.5}, Position{})
+	long := w.CreateEntity()
+	SetComponent(w, long, Lifetime{Remaining: 2})
+	owned := w.CreateEntity()
+	w.SetOwner(owned, short)
+	immortal := w.CreateEntity()
+	SetComponent(w, immortal, Position{})
+
+	if n := w.Tick(0.25); n != 0 {
+		t.Fatalf("expected nothing to expire, got %d", n)
+	}
+	if r := GetComponent[Lifetime](w, long).Remaining; r != 1.75 {
+		t.Errorf("expected 1.75 remaining, got %v", r)
+	}
+	version := w.mutationVersion.Load()
+	if n := w.Tick(0.25); n != 1 {
+		t.Fatalf("expected one expiry, got %d", n)
+	}
+	if w.IsValid(short) || w.IsValid(owned) {
+		t.Error("expected the expired entity and the entity it owns to be removed")
+	}
+	if !w.IsValid(long) || !w.IsValid(immortal) {
+		t.Error("expected the other entities to survive")
+	}
+	if w.mutationVersion.Load() != version+1 {
+		t.Error("expected a single version bump")
+	}
+	w.Tick(10)
+	if w.IsValid(long) || w.Frame() != 3 {
+		t.Errorf("expected the long-lived entity to expire by frame 3, frame is %d", w.Frame())
+	}
+}
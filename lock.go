@@ -0,0 +1,195 @@
+package teishoku
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// worldLock is the World's read-write lock. For worlds created with
+// WithSingleThreaded it is disabled and every method is a no-op, so the
+// common single-threaded game loop does not pay for mutex operations.
+//
+// In ecsdebug builds it also detects the two ways the locking contract is
+// broken from a single goroutine, which otherwise end in a deadlock or in
+// silent storage corruption (see reentryGuard).
+type worldLock struct {
+	mu       sync.RWMutex
+	disabled bool
+	guard    reentryGuard
+}
+
+// Lock acquires the write lock.
+func (l *worldLock) Lock() {
+	if !l.disabled {
+		if debugChecks {
+			l.guard.checkWriter()
+		}
+		l.mu.Lock()
+		if debugChecks {
+			l.guard.setWriter(goroutineID())
+		}
+	}
+}
+
+// Unlock releases the write lock.
+func (l *worldLock) Unlock() {
+	if !l.disabled {
+		if debugChecks {
+			l.guard.setWriter(0)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// RLock acquires the read lock.
+func (l *worldLock) RLock() {
+	if !l.disabled {
+		if debugChecks {
+			l.guard.checkWriter()
+		}
+		l.mu.RLock()
+	}
+}
+
+// RUnlock releases the read lock.
+func (l *worldLock) RUnlock() {
+	if !l.disabled {
+		l.mu.RUnlock()
+	}
+}
+
+// registryLock is the ComponentRegistry's read-write lock. Once the registry
+// is frozen by World.FreezeComponents its contents never change again, and
+// the read methods become no-ops. Freezing sets the flag under the write lock,
+// so a reader holding the read lock always sees it unset when releasing; a
+// reader that was queued behind the freeze sees it set once it acquires the
+// lock and releases it at once, leaving RUnlock nothing to undo.
+type registryLock struct {
+	mu     sync.RWMutex
+	frozen atomic.Bool
+}
+
+// Lock acquires the write lock.
+func (l *registryLock) Lock() {
+	l.mu.Lock()
+}
+
+// Unlock releases the write lock.
+func (l *registryLock) Unlock() {
+	l.mu.Unlock()
+}
+
+// RLock acquires the read lock, unless the registry is frozen.
+func (l *registryLock) RLock() {
+	if l.frozen.Load() {
+		return
+	}
+	l.mu.RLock()
+	if l.frozen.Load() {
+		l.mu.RUnlock() // frozen while waiting
+	}
+}
+
+// RUnlock releases the read lock, unless the registry is frozen.
+func (l *registryLock) RUnlock() {
+	if !l.frozen.Load() {
+		l.mu.RUnlock()
+	}
+}
+
+// reentryGuard records, in ecsdebug builds, which goroutine holds the write
+// lock and which goroutines are running a filter callback (Apply, EachMut,
+// EachArchetype, ...), whose iteration runs without the lock:
+//
+//   - a goroutine that calls the World API while holding the write lock, e.g.
+//     from inside Mutate or an OnArchetypeCreated callback, would deadlock;
+//   - a goroutine that makes a structural change from a filter callback would
+//     move rows under the iteration and corrupt it.
+//
+// Both panic with an explanation instead. Goroutines are identified by the ID
+// in their stack trace, which is slow and therefore never done in regular
+// builds.
+type reentryGuard struct {
+	mu        sync.Mutex
+	writer    int64         // goroutine holding the write lock, 0 if none
+	iterating map[int64]int // goroutine -> number of nested callback iterations
+}
+
+func (g *reentryGuard) setWriter(id int64) {
+	g.mu.Lock()
+	g.writer = id
+	g.mu.Unlock()
+}
+
+// checkWriter panics if the calling goroutine already holds the write lock.
+func (g *reentryGuard) checkWriter() {
+	g.mu.Lock()
+	writer := g.writer
+	g.mu.Unlock()
+	if writer != 0 && writer == goroutineID() {
+		panic("ecs: World method called while the same goroutine holds the World's write lock (inside Mutate or an OnArchetypeCreated callback); use the Txn methods instead, this would deadlock")
+	}
+}
+
+// beginIteration marks the calling goroutine as running a filter callback.
+// Only called in ecsdebug builds.
+func (l *worldLock) beginIteration() {
+	g := &l.guard
+	id := goroutineID()
+	g.mu.Lock()
+	if g.iterating == nil {
+		g.iterating = make(map[int64]int)
+	}
+	g.iterating[id]++
+	g.mu.Unlock()
+}
+
+// endIteration undoes beginIteration. Only called in ecsdebug builds.
+func (l *worldLock) endIteration() {
+	g := &l.guard
+	id := goroutineID()
+	g.mu.Lock()
+	if g.iterating[id]--; g.iterating[id] <= 0 {
+		delete(g.iterating, id)
+	}
+	g.mu.Unlock()
+}
+
+// checkStructural panics if the calling goroutine is inside a filter
+// callback. It is called where entities are created, removed or moved
+// between rows, and compiles to nothing outside ecsdebug builds.
+func (w *World) checkStructural() {
+	if !debugChecks {
+		return
+	}
+	g := &w.mu.guard
+	g.mu.Lock()
+	n := len(g.iterating)
+	g.mu.Unlock()
+	if n == 0 {
+		return
+	}
+	id := goroutineID()
+	g.mu.Lock()
+	depth := g.iterating[id]
+	g.mu.Unlock()
+	if depth > 0 {
+		panic("ecs: structural change (creating, removing or moving entities) inside a filter callback such as Apply, EachMut or EachArchetype; collect the entities and change them after the loop, or use EachSafe, EachMut's ActionRemove or Mutate")
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the first
+// line of its stack trace ("goroutine 42 [running]:").
+func goroutineID() int64 {
+	var buf [32]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	var id int64
+	for _, c := range b[len("goroutine "):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + int64(c-'0')
+	}
+	return id
+}
@@ -0,0 +1,6 @@
+//go:build !ecsdebug
+
+package teishoku
+
+// debugChecks enables development-time assertions. See debug.go.
+const debugChecks = false
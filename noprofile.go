@@ -0,0 +1,6 @@
+//go:build !ecsprofile
+
+package teishoku
+
+// accessProfiling enables the component access counters. See profile.go.
+const accessProfiling = false
@@ -0,0 +1,42 @@
+package teishoku
+
+import "slices"
+
+// Ordered returns the entities matched by the filter sorted by the given
+// comparator, across all matching archetypes, for work that needs a global
+// order such as drawing sprites back to front.
+//
+// Every call collects and sorts the entities again, so the order follows both
+// structural changes and changes to the component values the comparator
+// reads. Callers sorting a large filter every frame can keep the result while
+// they know the values it depends on are unchanged.
+//
+// The returned slice is newly allocated and never modified by the filter. The
+// comparator is called without holding the world's lock, so it may read
+// components with GetComponent.
+//
+// Parameters:
+//   - by: A comparator returning a negative number when a sorts before b, a
+//     positive number when it sorts after, and zero when their order does not
+//     matter. Equal entities keep their archetype order.
+//
+// Returns:
+//   - The matching entities in sorted order.
+func (c *queryCache) Ordered(by func(a, b Entity) int) []Entity {
+	c.checkWorld()
+	c.world.mu.RLock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	total := 0
+	for _, a := range c.matchingArches {
+		total += a.size
+	}
+	entities := make([]Entity, 0, total)
+	for _, a := range c.matchingArches {
+		entities = append(entities, a.entityIDs[:a.size]...)
+	}
+	c.world.mu.RUnlock()
+	slices.SortStableFunc(entities, by)
+	return entities
+}
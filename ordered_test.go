@@ -0,0 +1,62 @@
+package teishoku
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestFilterOrdered(t *testing.T) {
+	w := NewWorld(8)
+	for _, z := range []float32{3, 1, 2} {
+		e := w.CreateEntity()
+		SetComponent(w, e, Position{X: z})
+	}
+	for _, z := range []float32{0, 4} {
+		e := w.CreateEntity()
+		SetComponent2(w, e, Position{X: z}, Velocity{})
+	}
+
+	f := NewFilter[Position](w)
+	byZ := func(a, b Entity) int {
+		return cmp.Compare(GetComponent[Position](w, a).X, GetComponent[Position](w, b).X)
+	}
+	got := f.Ordered(byZ)
+	if len(got) != 5 {
+		t.Fatalf("expected 5 entities, got %d", len(got))
+	}
+	for i, e := range got {
+		if x := GetComponent[Position](w, e).X; x != float32(i) {
+			t.Fatalf("position %d: expected X %d, got %v", i, i, x)
+		}
+	}
+
+	// Value changes are not structural, but the order follows them.
+	SetComponent(w, got[0], Position{X: 10})
+	if moved := f.Ordered(byZ); moved[len(moved)-1] != got[0] {
+		t.Error("expected the order to follow a changed component value")
+	}
+
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{X: -1})
+	resorted := f.Ordered(byZ)
+	if len(resorted) != 6 || resorted[0] != e {
+		t.Fatalf("expected the new entity first after a structural change, got %v", resorted)
+	}
+	if got[0] == resorted[0] {
+		t.Error("a previously returned order was modified")
+	}
+
+	// Closures of the same literal with different captures sort differently.
+	sorter := func(desc bool) func(a, b Entity) int {
+		return func(a, b Entity) int {
+			if desc {
+				a, b = b, a
+			}
+			return byZ(a, b)
+		}
+	}
+	asc, desc := f.Ordered(sorter(false)), f.Ordered(sorter(true))
+	if asc[0] != e || desc[len(desc)-1] != e {
+		t.Error("expected each comparator to get its own order")
+	}
+}
@@ -0,0 +1,264 @@
+package teishoku
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// SetOwner makes owner own child. When an owner is removed, by RemoveEntity,
+// RemoveEntities, a Txn or a filter's RemoveEntities, every entity it owns is
+// removed with it, recursively, in the same operation. This keeps dependent
+// entities such as projectile trails or UI sub-elements from outliving the
+// entity they belong to.
+//
+// An entity has at most one owner; setting a new owner replaces the previous
+// one, and passing the zero Entity as owner releases child. Ownership cycles
+// are allowed and are broken when any entity of the cycle is removed.
+//
+// Parameters:
+//   - child: The entity to be owned.
+//   - owner: The owning entity, or the zero Entity to clear ownership.
+//
+// Returns:
+//   - true if ownership was updated, false if either entity is invalid or
+//     child and owner are the same entity.
+func (w *World) SetOwner(child, owner Entity) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(child) || child == owner {
+		return false
+	}
+	if owner != (Entity{}) && !w.IsValidNoLock(owner) {
+		return false
+	}
+	meta := &w.entities.metas[child.ID]
+	if meta.owner != (Entity{}) {
+		w.detachOwnedNoLock(meta.owner, child)
+	}
+	meta.owner = owner
+	if owner != (Entity{}) {
+		if w.owned == nil {
+			w.owned = make(map[uint32][]Entity)
+		}
+		w.owned[owner.ID] = append(w.owned[owner.ID], child)
+	}
+	return true
+}
+
+// Owner returns the entity that owns e, as set by SetOwner.
+//
+// Parameters:
+//   - e: The owned entity.
+//
+// Returns:
+//   - The owner and true, or the zero Entity and false if e is invalid or has
+//     no owner.
+func (w *World) Owner(e Entity) (Entity, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return Entity{}, false
+	}
+	owner := w.entities.metas[e.ID].owner
+	return owner, owner != (Entity{})
+}
+
+// Owned returns the entities directly owned by e, in the order ownership was
+// assigned. The returned slice is a copy and may be modified freely.
+//
+// Parameters:
+//   - e: The owning entity.
+//
+// Returns:
+//   - The entities owned by e, or nil if there are none or e is invalid.
+func (w *World) Owned(e Entity) []Entity {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) || len(w.owned[e.ID]) == 0 {
+		return nil
+	}
+	return append([]Entity(nil), w.owned[e.ID]...)
+}
+
+// detachOwnedNoLock removes child from the list of entities owned by owner.
+func (w *World) detachOwnedNoLock(owner, child Entity) {
+	list := w.owned[owner.ID]
+	for i, e := range list {
+		if e == child {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(w.owned, owner.ID)
+	} else {
+		w.owned[owner.ID] = list
+	}
+}
+
+// releaseOwnershipNoLock detaches the entity ID from its owner and queues the
+// entities it owns for removal. It is called for every released entity.
+func (w *World) releaseOwnershipNoLock(meta *entityMeta, id uint32) {
+	if meta.owner != (Entity{}) {
+		w.detachOwnedNoLock(meta.owner, Entity{ID: id, Version: meta.version})
+		meta.owner = Entity{}
+	}
+	if list, ok := w.owned[id]; ok {
+		delete(w.owned, id)
+		w.pendingOwned = append(w.pendingOwned, list...)
+	}
+}
+
+// removeOwnedNoLock removes every entity queued by releaseOwnershipNoLock,
+// together with the entities they own in turn. Each owner's list is dropped
+// before its children are queued and removed entities fail the validity
+// check, so every entity is visited at most once and cycles terminate.
+// Callers are responsible for bumping the mutation version.
+func (w *World) removeOwnedNoLock() {
+	for len(w.pendingOwned) > 0 {
+		last := len(w.pendingOwned) - 1
+		e := w.pendingOwned[last]
+		w.pendingOwned = w.pendingOwned[:last]
+		if !w.IsValidNoLock(e) {
+			continue
+		}
+		meta := &w.entities.metas[e.ID]
+		w.destroyInArchetype(w.archetypes.archetypes[meta.archetypeIndex], meta)
+		w.releaseEntityNoLock(e.ID)
+	}
+}
+
+// ChildQuery iterates over the entities owned by one parent, as returned by
+// World.QueryChildren. The children may live in any number of archetypes;
+// read their components with GetComponent or a filter's accessors.
+//
+// The query walks the world's ownership index directly instead of copying
+// it, so ownership of the parent's children must not change (through
+// SetOwner or removing a child) while iterating.
+type ChildQuery struct {
+	world    *World
+	children []Entity
+	idx      int
+}
+
+// QueryChildren returns a query over the entities directly owned by parent,
+// in the order ownership was assigned. It is the traversal counterpart to
+// SetOwner and, unlike Owned, does not allocate.
+//
+// Parameters:
+//   - parent: The owning entity.
+//
+// Returns:
+//   - A ChildQuery positioned before the first child. It yields nothing if
+//     parent is invalid or owns no entity.
+func (w *World) QueryChildren(parent Entity) ChildQuery {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	q := ChildQuery{world: w, idx: -1}
+	if w.IsValidNoLock(parent) {
+		q.children = w.owned[parent.ID]
+	}
+	return q
+}
+
+// Next advances the query to the next child. It returns true if a child was
+// found, and false if the iteration is complete.
+//
+// Returns:
+//   - true if another child was found, false otherwise.
+func (q *ChildQuery) Next() bool {
+	q.idx++
+	return q.idx < len(q.children)
+}
+
+// Entity returns the current child. This should only be called after `Next()`
+// has returned true.
+//
+// Returns:
+//   - The current child Entity.
+func (q *ChildQuery) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.idx, len(q.children))
+	}
+	return q.children[q.idx]
+}
+
+// Len returns the number of children the query iterates over.
+func (q *ChildQuery) Len() int {
+	return len(q.children)
+}
+
+// Reset rewinds the query to before its first child.
+func (q *ChildQuery) Reset() {
+	q.idx = -1
+}
+
+// OwnerLookup reads a component of type `P` from the owner of an entity, for
+// systems that combine a child's data with its owner's in one pass, such as
+// computing a transform relative to the parent transform:
+//
+//	parents := NewOwnerLookup[Transform](w)
+//	for f.Next() {
+//		local, _ := f.Get()
+//		if parent := parents.Get(f.Entity()); parent != nil {
+//			...
+//		}
+//	}
+//
+// Each Get costs an extra metadata lookup for the owner plus a read lock,
+// compared with reading the child's own components from the filter. The
+// owner's component address is cached, so consecutive children of the same
+// owner, the common case when siblings share an archetype, skip resolving the
+// owner's archetype again until the next structural change.
+type OwnerLookup[P any] struct {
+	world   *World
+	id      uint8
+	owner   Entity // owner resolved by the last Get
+	ptr     *P     // owner's component, valid while version is current
+	version uint32 // world.mutationVersion when ptr was resolved
+}
+
+// NewOwnerLookup creates an OwnerLookup for components of type `P`.
+//
+// Parameters:
+//   - w: The World containing the entities.
+//
+// Returns:
+//   - A pointer to the new OwnerLookup.
+func NewOwnerLookup[P any](w *World) *OwnerLookup[P] {
+	return &OwnerLookup[P]{world: w, id: w.getCompTypeID(reflect.TypeFor[P]())}
+}
+
+// Get returns the `P` component of e's owner, as set by SetOwner.
+//
+// Parameters:
+//   - e: The owned entity.
+//
+// Returns:
+//   - A pointer to the owner's component, or nil if e is invalid, has no
+//     owner, or its owner lacks `P`.
+func (o *OwnerLookup[P]) Get(e Entity) *P {
+	w := o.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return nil
+	}
+	owner := w.entities.metas[e.ID].owner
+	if owner == (Entity{}) {
+		return nil
+	}
+	version := w.mutationVersion.Load()
+	if owner == o.owner && version == o.version {
+		return o.ptr
+	}
+	o.owner, o.version, o.ptr = owner, version, nil
+	if w.IsValidNoLock(owner) {
+		meta := w.entities.metas[owner.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if a.mask.has(o.id) {
+			o.ptr = (*P)(unsafe.Add(a.compPointers[o.id], uintptr(meta.index)*a.compSizes[o.id]))
+		}
+	}
+	return o.ptr
+}
@@ -0,0 +1,162 @@
+package teishoku
+
+import "testing"
+
+func TestOwnershipCascade(t *testing.T) {
+	w := NewWorld(8)
+	root := w.CreateEntity()
+	child := w.CreateEntity()
+	SetComponent(w, child, Position{})
+	grandchild := w.CreateEntity()
+	SetComponent(w, grandchild, Velocity{})
+	other := w.CreateEntity()
+
+	if !w.SetOwner(child, root) || !w.SetOwner(grandchild, child) {
+		t.Fatal("SetOwner failed")
+	}
+	if w.SetOwner(root, root) {
+		t.Fatal("an entity must not own itself")
+	}
+	if o, ok := w.Owner(grandchild); !ok || o != child {
+		t.Fatalf("Owner: expected %v, got %v %v", child, o, ok)
+	}
+	if got := w.Owned(root); len(got) != 1 || got[0] != child {
+		t.Fatalf("Owned: got %v", got)
+	}
+
+	before := w.mutationVersion.Load()
+	w.RemoveEntity(root)
+	if w.mutationVersion.Load() != before+1 {
+		t.Fatal("expected a single mutation version bump for the cascade")
+	}
+	for _, e := range []Entity{root, child, grandchild} {
+		if w.IsValid(e) {
+			t.Errorf("expected %v to be removed", e)
+		}
+	}
+	if !w.IsValid(other) {
+		t.Fatal("unowned entity was removed")
+	}
+	if NewFilter[Position](w).Count() != 0 || NewFilter[Velocity](w).Count() != 0 {
+		t.Fatal("owned entities left in their archetypes")
+	}
+}
+
+func TestOwnershipCycleAndRelease(t *testing.T) {
+	w := NewWorld(4)
+	a := w.CreateEntity()
+	b := w.CreateEntity()
+	c := w.CreateEntity()
+	w.SetOwner(b, a)
+	w.SetOwner(c, b)
+	w.SetOwner(a, c)
+	w.RemoveEntity(b)
+	if w.IsValid(a) || w.IsValid(b) || w.IsValid(c) {
+		t.Fatal("expected the whole ownership cycle to be removed")
+	}
+
+	// Releasing ownership or removing the child independently detaches it.
+	owner := w.CreateEntity()
+	kept := w.CreateEntity()
+	gone := w.CreateEntity()
+	w.SetOwner(kept, owner)
+	w.SetOwner(gone, owner)
+	w.SetOwner(kept, Entity{})
+	w.RemoveEntity(gone)
+	if got := w.Owned(owner); len(got) != 0 {
+		t.Fatalf("expected no owned entities, got %v", got)
+	}
+	w.RemoveEntity(owner)
+	if !w.IsValid(kept) {
+		t.Fatal("released entity was removed with its former owner")
+	}
+}
+
+func TestOwnershipFilterRemoveEntities(t *testing.T) {
+	w := NewWorld(4)
+	owner := w.CreateEntity()
+	SetComponent(w, owner, Position{})
+	child := w.CreateEntity()
+	SetComponent(w, child, Velocity{})
+	w.SetOwner(child, owner)
+	NewFilter[Position](w).RemoveEntities()
+	if w.IsValid(child) {
+		t.Fatal("expected filter removal to cascade to owned entities")
+	}
+}
+
+func TestQueryChildren(t *testing.T) {
+	w := NewWorld(8)
+	parent := w.CreateEntity()
+	a := w.CreateEntity()
+	SetComponent(w, a, Position{X: 1})
+	b := w.CreateEntity()
+	SetComponent2(w, b, Position{X: 2}, Velocity{})
+	w.SetOwner(a, parent)
+	w.SetOwner(b, parent)
+
+	q := w.QueryChildren(parent)
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 children, got %d", q.Len())
+	}
+	var sum float32
+	for q.Next() {
+		sum += GetComponent[Position](w, q.Entity()).X
+	}
+	if sum != 3 {
+		t.Errorf("expected children across archetypes to be visited, sum %v", sum)
+	}
+	q.Reset()
+	if !q.Next() || q.Entity() != a {
+		t.Error("expected Reset to rewind to the first child")
+	}
+
+	w.RemoveEntity(parent)
+	if q := w.QueryChildren(parent); q.Next() {
+		t.Error("expected no children for a removed parent")
+	}
+}
+
+func TestOwnerLookup(t *testing.T) {
+	w := NewWorld(8)
+	parent := w.CreateEntity()
+	SetComponent(w, parent, Position{X: 10})
+	a := w.CreateEntity()
+	SetComponent(w, a, Velocity{DX: 1})
+	b := w.CreateEntity()
+	SetComponent(w, b, Velocity{DX: 2})
+	orphan := w.CreateEntity()
+	SetComponent(w, orphan, Velocity{})
+	w.SetOwner(a, parent)
+	w.SetOwner(b, parent)
+
+	parents := NewOwnerLookup[Position](w)
+	found := 0
+	for f := NewFilter[Velocity](w); f.Next(); {
+		p := parents.Get(f.Entity())
+		if f.Entity() == orphan {
+			if p != nil {
+				t.Error("expected nil for an entity without owner")
+			}
+			continue
+		}
+		if p == nil || p.X != 10 {
+			t.Fatalf("expected the owner's Position, got %v", p)
+		}
+		found++
+	}
+	if found != 2 {
+		t.Fatalf("expected 2 children with an owner component, got %d", found)
+	}
+
+	// Moving the owner to another archetype must not leave a stale pointer.
+	SetComponent(w, parent, Health{})
+	GetComponent[Position](w, parent).X = 20
+	if p := parents.Get(a); p == nil || p.X != 20 {
+		t.Fatalf("expected the moved owner's Position, got %v", p)
+	}
+	RemoveComponent[Position](w, parent)
+	if p := parents.Get(b); p != nil {
+		t.Error("expected nil once the owner lacks the component")
+	}
+}
@@ -0,0 +1,110 @@
+package teishoku
+
+// pageSpan is the range [start, end) of rows of one archetype covered by a
+// page.
+type pageSpan struct {
+	arch       *archetype
+	start, end int
+}
+
+// pageCursor resolves and walks the archetype ranges of a filter page. It is
+// embedded by Page and the numbered Page types, which add typed access to the
+// current row.
+type pageCursor struct {
+	world   *World
+	version uint32
+	spans   []pageSpan
+	span    int // index into spans
+	idx     int // row of the current entity in spans[span].arch, or -1
+}
+
+// newPageCursor resolves the page [offset, offset+limit) of the entities
+// matched by c, in iteration order, against the current state of the world.
+func (c *queryCache) newPageCursor(offset, limit int) pageCursor {
+	c.checkWorld()
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	c.sortBySize()
+	p := pageCursor{world: c.world, version: c.world.mutationVersion.Load(), idx: -1}
+	offset = max(offset, 0)
+	for _, a := range c.matchingArches {
+		if limit <= 0 {
+			break
+		}
+		if offset >= a.size {
+			offset -= a.size
+			continue
+		}
+		n := min(a.size-offset, limit)
+		p.spans = append(p.spans, pageSpan{arch: a, start: offset, end: offset + n})
+		limit -= n
+		offset = 0
+	}
+	return p
+}
+
+// Valid reports whether the page still reflects the world, i.e. whether no
+// structural change has happened since it was created.
+func (p *pageCursor) Valid() bool {
+	return p.world != nil && p.world.mutationVersion.Load() == p.version
+}
+
+// Len returns the number of entities in the page. It is smaller than the
+// requested limit for the last page of the filter.
+func (p *pageCursor) Len() int {
+	n := 0
+	for _, s := range p.spans {
+		n += s.end - s.start
+	}
+	return n
+}
+
+// Reset rewinds the page, so that it can be iterated again.
+func (p *pageCursor) Reset() {
+	p.span = 0
+	p.idx = -1
+}
+
+// Next advances to the next entity of the page.
+//
+// Returns:
+//   - true if another entity was found, false if the page is exhausted.
+//
+// It panics if the page is no longer valid.
+func (p *pageCursor) Next() bool {
+	if !p.Valid() {
+		panic("ecs: Page used after a structural change")
+	}
+	for p.span < len(p.spans) {
+		s := &p.spans[p.span]
+		if p.idx < 0 {
+			p.idx = s.start
+		} else {
+			p.idx++
+		}
+		if p.idx < s.end {
+			return true
+		}
+		p.span++
+		p.idx = -1
+	}
+	return false
+}
+
+// Entity returns the current entity. This should only be called after Next
+// has returned true.
+func (p *pageCursor) Entity() Entity {
+	a, i := p.row()
+	return a.entityIDs[i]
+}
+
+// row returns the archetype and row of the current entity.
+func (p *pageCursor) row() (*archetype, uintptr) {
+	if debugChecks && (p.span >= len(p.spans) || p.idx < 0) {
+		checkCursor(-1, 0)
+	}
+	return p.spans[p.span].arch, uintptr(p.idx)
+}
@@ -0,0 +1,70 @@
+package teishoku
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterPage(t *testing.T) {
+	w := NewWorld(16)
+	for i := range 4 {
+		SetComponent2(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{})
+	}
+	for i := 4; i < 10; i++ {
+		SetComponent3(w, w.CreateEntity(), Position{X: float32(i)}, Velocity{}, Health{})
+	}
+	f := NewFilter2[Position, Velocity](w)
+	var all []Entity
+	for f.Next() {
+		all = append(all, f.Entity())
+	}
+
+	var paged []Entity
+	for offset := 0; offset < f.Count(); offset += 3 {
+		p := f.Page(offset, 3)
+		if want := min(3, f.Count()-offset); p.Len() != want {
+			t.Errorf("page at %d: expected %d entities, got %d", offset, want, p.Len())
+		}
+		for p.Next() {
+			pos, _ := p.Get()
+			if want := GetComponent[Position](w, p.Entity()); pos != want {
+				t.Errorf("page at %d: Get does not match the entity %v", offset, p.Entity())
+			}
+			paged = append(paged, p.Entity())
+		}
+	}
+	if !slices.Equal(paged, all) {
+		t.Errorf("pages do not cover the iteration order:\n got %v\nwant %v", paged, all)
+	}
+
+	p := f.Page(2, 4) // spans both archetypes
+	n := 0
+	for p.Next() {
+		n++
+	}
+	p.Reset()
+	for p.Next() {
+		n++
+	}
+	if n != 8 {
+		t.Errorf("expected 4 entities twice, got %d", n)
+	}
+	if p := f.Page(20, 5); p.Len() != 0 || p.Next() {
+		t.Error("expected an empty page past the end")
+	}
+	if p := NewFilter[Position](w).Page(9, 5); p.Len() != 1 || !p.Next() || p.Get().X != 9 {
+		t.Error("expected the last Position in a one-entity page")
+	}
+
+	w.RemoveEntity(all[0])
+	if p.Valid() {
+		t.Error("page still valid after a structural change")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic iterating a stale page")
+		}
+	}()
+	p.Reset()
+	p.Next()
+}
@@ -0,0 +1,9 @@
+//go:build ecsprofile
+
+package teishoku
+
+// accessProfiling enables the component access counters behind
+// World.AccessProfile. It is true when building with the ecsprofile build tag
+// (go build -tags ecsprofile) and false otherwise, in which case the compiler
+// removes the counting entirely.
+const accessProfiling = true
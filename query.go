@@ -1,5 +1,12 @@
 package teishoku
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+)
+
 // queryCache provides a reusable mechanism for caching the results of a filter
 // query. It stores a list of matching archetypes and entities, and tracks the
 // world's version numbers to detect when the cache needs to be updated. This
@@ -10,8 +17,36 @@ type queryCache struct {
 	matchingArches      []*archetype
 	cachedEntities      []Entity
 	mask                bitmask256
-	lastVersion         uint32 // world.archetypes.archetypeVersion when matchingArches was last updated
-	lastMutationVersion uint32 // world.mutationVersion when cachedEntities was last updated
+	onStale             func()
+	scanned             int           // number of world archetypes already tested against mask
+	exact               bool          // match archetypes whose mask equals mask, instead of supersets
+	parts               []*queryCache // for composite filters: the combined sources
+	union               bool          // for composite filters: match any part instead of all
+	shared              bool          // cachedEntities was handed out by EntitiesCached
+	matchSet            *matchSet     // archetype list shared with identical filters
+	closed              bool          // set by Close
+	bySize              bool          // iterate archetypes largest-first, see BySize
+	sizeOrder           []*archetype  // private copy of matchingArches sorted by size
+	sized               bool          // sizeOrder reflects the current matchingArches
+	sizedMutation       uint32        // world.mutationVersion when sizeOrder was sorted
+	iterVersion         uint32        // world.mutationVersion when the iteration started, in ecsdebug builds
+	lastVersion         uint32        // world.archetypes.archetypeVersion when matchingArches was last updated
+	lastMutationVersion uint32        // world.mutationVersion when cachedEntities was last updated
+	notifiedVersion     uint32        // world.archetypes.archetypeVersion when onStale last fired
+	notifiedMutation    uint32        // world.mutationVersion when onStale last fired
+}
+
+// AnyFilter is implemented by every filter type (Filter, Filter0, Filter2 and
+// up). It lets World methods accept a filter regardless of the component types
+// it iterates. The interface is sealed: only filters created by this package
+// implement it.
+type AnyFilter interface {
+	cache() *queryCache
+}
+
+// cache returns the filter's underlying query cache.
+func (c *queryCache) cache() *queryCache {
+	return c
 }
 
 // newQueryCache creates and initializes a new `queryCache`. It sets up the
@@ -24,7 +59,12 @@ type queryCache struct {
 //
 // Returns:
 //   - An initialized `queryCache` instance.
+//
+// It panics if m holds a transient component, which no archetype stores.
 func newQueryCache(w *World, m bitmask256) queryCache {
+	if t := w.transientInMaskNoLock(m); t != nil {
+		panic(fmt.Sprintf("ecs: a filter cannot match transient component %v; use JoinTransient", t))
+	}
 	return queryCache{
 		world:          w,
 		mask:           m,
@@ -33,35 +73,164 @@ func newQueryCache(w *World, m bitmask256) queryCache {
 	}
 }
 
-// updateMatching rebuilds the filter's list of archetypes that match its
-// component mask. This is called automatically when the filter detects that
-// the world's archetype layout has changed.
+// updateMatching brings the filter's list of archetypes that match its
+// component mask up to date. This is called automatically when the filter
+// detects that the world's archetype layout has changed.
+//
+// The world's archetype list is append-only, so only archetypes created since
+// the last update are tested against the mask. Empty archetypes are kept in
+// the list, since they may be populated later without the archetype layout
+// changing; iterators skip them. If the list ever shrinks (e.g. the world was
+// closed), the cache is rebuilt from scratch.
+//
+// Filters with the same mask and matching mode share one list through a
+// matchSet, so the scan happens once per world change instead of once per
+// filter. Composite filters keep a list of their own.
 func (c *queryCache) updateMatching() {
-	c.matchingArches = c.matchingArches[:0]
-	isZeroMask := c.mask == bitmask256{}
+	c.notifyStale()
+	c.world.stats.filterRescans.Add(1)
+	if c.bySize {
+		c.sized = false
+		defer c.sortBySize()
+	}
+	if c.parts == nil {
+		c.updateShared()
+		return
+	}
+	arches := c.world.archetypes.archetypes
+	if c.scanned > len(arches) {
+		c.matchingArches = c.matchingArches[:0]
+		c.scanned = 0
+	}
+	for _, a := range arches[c.scanned:] {
+		if c.matches(a) {
+			c.matchingArches = append(c.matchingArches, a)
+		}
+	}
+	c.scanned = len(arches)
+	c.lastVersion = c.world.archetypes.archetypeVersion.Load()
+}
 
-	for _, a := range c.world.archetypes.archetypes {
-		if a.size > 0 {
-			if (isZeroMask && a.mask == c.mask) || (!isZeroMask && a.mask.contains(c.mask)) {
-				c.matchingArches = append(c.matchingArches, a)
-			}
+// matchKey identifies the filters that can share a matchSet.
+type matchKey struct {
+	mask  bitmask256
+	exact bool
+}
+
+// matchSet is the list of archetypes matching a matchKey, shared by every
+// filter of the world with that key. It is reference-counted: filters acquire
+// it on their first update and release it with Close.
+type matchSet struct {
+	mu      sync.Mutex // updates may run concurrently under the world's read lock
+	arches  []*archetype
+	scanned int
+	refs    int
+}
+
+// updateShared brings the filter's shared matchSet up to date and adopts its
+// archetype list. The set only ever appends, so slices handed out earlier stay
+// valid; a rebuild allocates a fresh list.
+func (c *queryCache) updateShared() {
+	if c.matchSet == nil {
+		c.matchSet = c.world.acquireMatchSet(matchKey{mask: c.mask, exact: c.exact})
+	}
+	ms := c.matchSet
+	ms.mu.Lock()
+	arches := c.world.archetypes.archetypes
+	if ms.scanned > len(arches) {
+		ms.arches = nil
+		ms.scanned = 0
+	}
+	for _, a := range arches[ms.scanned:] {
+		if c.matches(a) {
+			ms.arches = append(ms.arches, a)
 		}
 	}
+	ms.scanned = len(arches)
+	c.matchingArches = ms.arches
+	c.scanned = ms.scanned
+	ms.mu.Unlock()
 	c.lastVersion = c.world.archetypes.archetypeVersion.Load()
 }
 
+// acquireMatchSet returns the world's matchSet for key, creating it if needed,
+// and takes a reference to it.
+func (w *World) acquireMatchSet(key matchKey) *matchSet {
+	w.matchMu.Lock()
+	defer w.matchMu.Unlock()
+	ms, ok := w.matchSets[key]
+	if !ok {
+		if w.matchSets == nil {
+			w.matchSets = make(map[matchKey]*matchSet)
+		}
+		ms = &matchSet{}
+		w.matchSets[key] = ms
+	}
+	ms.refs++
+	return ms
+}
+
+// releaseMatchSet drops a reference to the matchSet for key, forgetting the
+// set once no filter uses it.
+func (w *World) releaseMatchSet(key matchKey, ms *matchSet) {
+	w.matchMu.Lock()
+	defer w.matchMu.Unlock()
+	ms.refs--
+	if ms.refs == 0 && w.matchSets[key] == ms {
+		delete(w.matchSets, key)
+	}
+}
+
+// Close releases the filter's reference to the archetype list it shares with
+// other filters of the same component types, so the world can forget the list
+// once no filter uses it. Closing is optional; it matters for programs that
+// create many short-lived filters of distinct shapes. A closed filter must not
+// be used again; doing so panics.
+func (c *queryCache) Close() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	if c.matchSet != nil {
+		c.world.releaseMatchSet(matchKey{mask: c.mask, exact: c.exact}, c.matchSet)
+		c.matchSet = nil
+	}
+	c.matchingArches = nil
+}
+
+// matches reports whether archetype a satisfies the cache's query. Composite
+// caches defer to their parts, so an archetype is tested once and a union never
+// lists it twice.
+func (c *queryCache) matches(a *archetype) bool {
+	if c.parts != nil {
+		for _, p := range c.parts {
+			if p.matches(a) == c.union {
+				return c.union
+			}
+		}
+		return !c.union
+	}
+	if c.exact || c.mask == (bitmask256{}) {
+		return a.mask == c.mask
+	}
+	return a.mask.contains(c.mask)
+}
+
 // updateCachedEntities rebuilds the cached list of entities by collecting all
 // entity IDs from the archetypes currently matching the filter's query. This
 // method is called when the cache is stale to ensure the entity list is
 // up-to-date with the world state. After rebuilding, it updates the cache's
 // mutation version to match the world's current version.
 func (c *queryCache) updateCachedEntities() {
+	c.notifyStale()
 	total := 0
 	for _, a := range c.matchingArches {
 		total += a.size
 	}
-	if cap(c.cachedEntities) < total {
+	if c.shared || cap(c.cachedEntities) < total {
+		// A slice returned by EntitiesCached is never rewritten in place.
 		c.cachedEntities = make([]Entity, total)
+		c.shared = false
 	} else {
 		c.cachedEntities = c.cachedEntities[:total]
 	}
@@ -73,26 +242,224 @@ func (c *queryCache) updateCachedEntities() {
 	c.lastMutationVersion = c.world.mutationVersion.Load()
 }
 
+// World returns the World the filter is bound to. A filter only ever reads
+// the archetypes of the world it was created for; to query another world,
+// create a new filter for it (for example with the filter's New method).
+//
+// Returns:
+//   - The World this filter queries.
+func (c *queryCache) World() *World {
+	return c.world
+}
+
+// checkWorld panics if the cache was never bound to a World, which happens
+// when a zero-value filter is used instead of one returned by a NewFilter
+// function, or if its world has been closed.
+func (c *queryCache) checkWorld() {
+	if c.world == nil {
+		panic("ecs: filter is not bound to a World; create it with a NewFilter function")
+	}
+	if c.closed {
+		panic("ecs: use of a closed filter")
+	}
+	c.world.checkOpen()
+}
+
+// checkCursor panics when an iterator accessor such as Get or Entity is used
+// while the iterator has no current entity, i.e. before the first Next or
+// after Next returned false. It is only called in ecsdebug builds.
+func checkCursor(idx, size int) {
+	if idx < 0 || idx >= size {
+		panic("ecs: iterator accessed without a current entity; call Next and check that it returned true first")
+	}
+}
+
+// checkIteration is called by Next in ecsdebug builds. Iterators cache the
+// size and storage of the archetype they are walking, so a structural change
+// made during the iteration, by the iterating goroutine or by another one,
+// leaves them reading rows that moved or no longer exist. The world's
+// mutation version is recorded by the first Next after a reset, and a later
+// Next panics if it changed.
+func (c *queryCache) checkIteration(first bool) {
+	v := c.world.mutationVersion.Load()
+	if first {
+		c.iterVersion = v
+		return
+	}
+	if v != c.iterVersion {
+		panic("ecs: structural change (creating, removing or moving entities) during a filter iteration; Reset the filter after changing the world, or collect the entities and change them after the loop")
+	}
+}
+
 func (c *queryCache) isArchetypeStale() bool {
 	return c.world.archetypes.archetypeVersion.Load() != c.lastVersion
 }
 
+// refreshBeforeStart is called by iterators that reach the end of their first
+// archetype without having yielded anything since they were reset. The reset
+// may have happened before the first matching entity existed, typically for a
+// filter created at startup and iterated without another Reset, so the
+// archetype list is brought up to date and the iterator restarts from its
+// first archetype, whose size it re-reads. Like Next, it takes no lock: the
+// caller guarantees that the world is not changed structurally during the
+// loop, as for ResetUnlocked.
+func (c *queryCache) refreshBeforeStart() {
+	if c.isArchetypeStale() {
+		c.updateMatching()
+		c.sortBySize()
+	}
+}
+
 func (c *queryCache) isMutationStale() bool {
 	return c.world.mutationVersion.Load() != c.lastMutationVersion
 }
 
+// notifyStale fires the OnStale callback once per observed world state.
+func (c *queryCache) notifyStale() {
+	if c.onStale == nil {
+		return
+	}
+	av := c.world.archetypes.archetypeVersion.Load()
+	mv := c.world.mutationVersion.Load()
+	if av == c.notifiedVersion && mv == c.notifiedMutation {
+		return
+	}
+	c.notifiedVersion = av
+	c.notifiedMutation = mv
+	c.onStale()
+}
+
 // IsStale checks if the cache is out of sync with the world's state by
 // comparing the cache's last known version numbers with the world's current
 // versions. A cache is considered stale if either the archetype structure has
 // changed (e.g., a new archetype was created) or if entities have been created
 // or deleted.
 //
+// IsStale is available on every filter type, so callers can decide when to
+// rebuild a long-lived filter with Refresh.
+//
 // Returns:
 //   - true if the cache is stale and needs to be updated, false otherwise.
 func (c *queryCache) IsStale() bool {
 	return c.isArchetypeStale() || c.isMutationStale()
 }
 
+// Refresh forcibly rebuilds the filter's list of matching archetypes and its
+// cached entity list, instead of waiting for Reset or Entities to detect
+// staleness lazily. Refresh does not rewind an iteration in progress.
+//
+// Returns:
+//   - true if the filter was stale, i.e. the world changed since the filter
+//     was last updated, false otherwise.
+func (c *queryCache) Refresh() bool {
+	c.checkWorld()
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	stale := c.IsStale()
+	c.updateMatching()
+	c.updateCachedEntities()
+	return stale
+}
+
+// BySize makes the filter visit its archetypes in descending order of
+// entity count, largest first, instead of in archetype creation order.
+// Processing the biggest archetypes first improves load balance when
+// splitting the work into parallel chunks, and lets the hottest columns warm
+// the cache. The order is a sort of the matching archetypes, taken when the
+// filter refreshes and cached until the next structural change; archetypes of
+// equal size keep their creation order, so the iteration stays reproducible
+// for deterministic simulations. It changes the order of every iteration
+// form, Entities included, and cannot be undone.
+func (c *queryCache) BySize() {
+	c.checkWorld()
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	c.bySize = true
+	c.updateMatching()
+	c.updateCachedEntities()
+}
+
+// sortBySize orders matchingArches by descending size for a BySize filter, at
+// most once per world mutation. The archetype list may be shared with other
+// filters, so the sort works on a private copy.
+func (c *queryCache) sortBySize() {
+	if !c.bySize {
+		return
+	}
+	v := c.world.mutationVersion.Load()
+	if c.sized && c.sizedMutation == v {
+		return
+	}
+	c.sizeOrder = append(c.sizeOrder[:0], c.matchingArches...)
+	slices.SortStableFunc(c.sizeOrder, func(a, b *archetype) int {
+		return cmp.Compare(b.size, a.size)
+	})
+	c.matchingArches = c.sizeOrder
+	c.sized, c.sizedMutation = true, v
+}
+
+// OnStale registers fn to be called when the filter notices that the world
+// changed and rebuilds its cached state, whether from Reset, Entities, Query
+// or Refresh. Passing nil removes the callback.
+//
+// fn runs while the world's read lock is held, so it must not modify the
+// world; it is meant for bookkeeping such as marking dependent caches dirty.
+//
+// Parameters:
+//   - fn: The function to call when the filter becomes stale.
+func (c *queryCache) OnStale(fn func()) {
+	c.onStale = fn
+	if c.world != nil {
+		c.notifiedVersion = c.world.archetypes.archetypeVersion.Load()
+		c.notifiedMutation = c.world.mutationVersion.Load()
+	}
+}
+
+// Count returns the number of entities currently matching the filter.
+//
+// Returns:
+//   - The total number of matching entities across all archetypes.
+func (c *queryCache) Count() int {
+	c.checkWorld()
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	total := 0
+	for _, a := range c.matchingArches {
+		total += a.size
+	}
+	return total
+}
+
+// MatchCount returns the number of archetypes matching the filter, whether or
+// not they currently hold entities.
+//
+// Returns:
+//   - The number of matching archetypes.
+func (c *queryCache) MatchCount() int {
+	c.checkWorld()
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	return len(c.matchingArches)
+}
+
+// Empty reports whether no archetype matches the filter at all, e.g. because
+// one of its components has never been used in the world. This differs from a
+// Count of zero, where matching archetypes exist but hold no entity: an empty
+// filter can only start matching after a new archetype is created, so systems
+// can skip their work entirely without entering the Next loop.
+//
+// Returns:
+//   - true if no archetype matches, false otherwise.
+func (c *queryCache) Empty() bool {
+	return c.MatchCount() == 0
+}
+
 // Entities returns a slice of all entities that match the cached query. If the
 // cache is detected as stale (i.e., out of sync with the world state), it will
 // first update its internal lists of matching archetypes and entities before
@@ -101,6 +468,7 @@ func (c *queryCache) IsStale() bool {
 // Returns:
 //   - A slice of `Entity` objects that match the query.
 func (c *queryCache) Entities() []Entity {
+	c.checkWorld()
 	c.world.mu.RLock()
 	defer c.world.mu.RUnlock()
 	update := c.isArchetypeStale()
@@ -112,3 +480,48 @@ func (c *queryCache) Entities() []Entity {
 	}
 	return c.cachedEntities
 }
+
+// EntitiesCached is like Entities, but is meant for filters read several times
+// per frame. While the world has no structural change, it returns the cached
+// slice without taking the world's lock or rescanning archetypes. The returned
+// slice is never modified by the filter: after a structural change the next
+// call builds a new one, so a slice held by the caller keeps describing the
+// world as it was when it was returned.
+//
+// The slice is only accurate until the next structural change, so callers
+// must not retain it across frames and must not modify it.
+//
+// Returns:
+//   - A slice of `Entity` objects that match the query.
+func (c *queryCache) EntitiesCached() []Entity {
+	c.checkWorld()
+	if !c.IsStale() {
+		c.shared = true
+		return c.cachedEntities
+	}
+	c.world.mu.RLock()
+	defer c.world.mu.RUnlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	c.updateCachedEntities()
+	c.shared = true
+	return c.cachedEntities
+}
+
+// TakeEntities returns up to the first n entities matched by the filter, in
+// the same order as Entities, e.g. to pick a handful of idle workers. Like
+// Entities, the slice is only valid until the next structural change; it is
+// clipped to its length, so appending to it does not overwrite the filter's
+// cache.
+//
+// Parameters:
+//   - n: The maximum number of entities to return.
+//
+// Returns:
+//   - A slice of at most n matching entities.
+func (c *queryCache) TakeEntities(n int) []Entity {
+	ents := c.Entities()
+	n = min(max(n, 0), len(ents))
+	return ents[:n:n]
+}
@@ -0,0 +1,89 @@
+package teishoku
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// PatchComponent overwrites n bytes at the given offset inside the entity's
+// component of type t, leaving the rest of the component untouched. A network
+// layer receiving delta-compressed state can use it to apply only the fields
+// that changed, with offsets taken from reflect.StructField.Offset.
+//
+// The patched range must only cover plain data (numbers, bools, arrays of
+// them): writing raw bytes over pointers, slices, strings, maps or interfaces
+// corrupts the component.
+//
+// It panics if t is not a registered component type or if offset+n exceeds the
+// component's size.
+//
+// Parameters:
+//   - e: The Entity to modify.
+//   - t: The component type to patch.
+//   - offset: The byte offset inside the component where the write starts.
+//   - src: The bytes to write; it must point to at least n readable bytes.
+//   - n: The number of bytes to write.
+//
+// Returns:
+//   - true if the bytes were written, false if the entity is invalid or does
+//     not have the component.
+func (w *World) PatchComponent(e Entity, t reflect.Type, offset uintptr, src unsafe.Pointer, n uintptr) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ptr, id := w.componentFieldNoLock(e, t, offset, n)
+	if ptr == nil {
+		return false
+	}
+	w.markChanged(id, e.ID)
+	memCopy(ptr, src, n)
+	return true
+}
+
+// GetComponentRaw copies n bytes at the given offset inside the entity's
+// component of type t into dst. It is the read counterpart of PatchComponent
+// and panics in the same cases.
+//
+// Parameters:
+//   - e: The Entity to read from.
+//   - t: The component type to read.
+//   - offset: The byte offset inside the component where the read starts.
+//   - dst: The destination; it must point to at least n writable bytes.
+//   - n: The number of bytes to read.
+//
+// Returns:
+//   - true if the bytes were copied, false if the entity is invalid or does
+//     not have the component.
+func (w *World) GetComponentRaw(e Entity, t reflect.Type, offset uintptr, dst unsafe.Pointer, n uintptr) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	ptr, _ := w.componentFieldNoLock(e, t, offset, n)
+	if ptr == nil {
+		return false
+	}
+	memCopy(dst, ptr, n)
+	return true
+}
+
+// componentFieldNoLock returns, with no-lock, a pointer to the byte at offset
+// inside the entity's component of type t and the component's ID, after
+// checking that n bytes from there stay within the component. The pointer is
+// nil if the entity is invalid or lacks the component.
+func (w *World) componentFieldNoLock(e Entity, t reflect.Type, offset, n uintptr) (unsafe.Pointer, uint8) {
+	id, ok := w.lookupCompTypeID(t)
+	if !ok {
+		panic(fmt.Sprintf("ecs: component type %v is not registered", t))
+	}
+	if size := t.Size(); offset > size || n > size-offset {
+		panic(fmt.Sprintf("ecs: range [%d, %d) is outside component %v of size %d", offset, offset+n, t, size))
+	}
+	if !w.IsValidNoLock(e) {
+		return nil, id
+	}
+	meta := w.entities.metas[e.ID]
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	if !a.mask.has(id) {
+		return nil, id
+	}
+	return unsafe.Add(a.compPointers[id], uintptr(meta.index)*a.compSizes[id]+offset), id
+}
@@ -0,0 +1,40 @@
+package teishoku
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestPatchComponent(t *testing.T) {
+	w := NewWorld(2)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{X: 1, Y: 2})
+	pt := reflect.TypeFor[Position]()
+	field, _ := pt.FieldByName("Y")
+
+	y := float32(9)
+	if !w.PatchComponent(e, pt, field.Offset, unsafe.Pointer(&y), unsafe.Sizeof(y)) {
+		t.Fatal("expected the patch to apply")
+	}
+	if p := GetComponent[Position](w, e); p.X != 1 || p.Y != 9 {
+		t.Fatalf("expected only Y to change, got %+v", *p)
+	}
+	var got float32
+	if !w.GetComponentRaw(e, pt, field.Offset, unsafe.Pointer(&got), unsafe.Sizeof(got)) || got != 9 {
+		t.Fatalf("expected to read back 9, got %v", got)
+	}
+
+	other := w.CreateEntity()
+	SetComponent(w, other, Velocity{})
+	if w.PatchComponent(other, pt, 0, unsafe.Pointer(&y), unsafe.Sizeof(y)) {
+		t.Error("expected no patch on an entity without the component")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a range outside the component")
+		}
+	}()
+	w.PatchComponent(e, pt, field.Offset, unsafe.Pointer(&y), 8)
+}
@@ -0,0 +1,147 @@
+package teishoku
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// ReadWorld is a read-only view of a World, obtained with World.ReadOnly. It
+// only exposes queries: entity validity and metadata, component reads and
+// iteration. Passing a ReadWorld instead of a *World to a subsystem, such as
+// rendering or an AI read pass, guarantees at compile time that it cannot
+// create or remove entities or change their components, and documents that
+// intent in the subsystem's API.
+type ReadWorld struct {
+	w *World
+}
+
+// ReadOnly returns a read-only view of the world.
+//
+// Returns:
+//   - A ReadWorld backed by w.
+func (w *World) ReadOnly() *ReadWorld {
+	return &ReadWorld{w: w}
+}
+
+// IsValid checks if the given entity is currently alive.
+func (r *ReadWorld) IsValid(e Entity) bool {
+	return r.w.IsValid(e)
+}
+
+// EntityState reports the lifecycle state of the entity handle. See
+// World.EntityState.
+func (r *ReadWorld) EntityState(e Entity) EntityState {
+	return r.w.EntityState(e)
+}
+
+// Lookup resolves a packed entity and checks that it is still alive. See
+// World.Lookup.
+func (r *ReadWorld) Lookup(id EntityID) (Entity, bool) {
+	return r.w.Lookup(id)
+}
+
+// GetUserData returns the value attached to the entity with SetUserData.
+func (r *ReadWorld) GetUserData(e Entity) uint64 {
+	return r.w.GetUserData(e)
+}
+
+// Owner returns the entity that owns e. See World.Owner.
+func (r *ReadWorld) Owner(e Entity) (Entity, bool) {
+	return r.w.Owner(e)
+}
+
+// Owned returns the entities directly owned by e. See World.Owned.
+func (r *ReadWorld) Owned(e Entity) []Entity {
+	return r.w.Owned(e)
+}
+
+// ComponentInfo reports how the world knows the component type t. See
+// World.ComponentInfo.
+func (r *ReadWorld) ComponentInfo(t reflect.Type) (id uint8, size uintptr, registered bool) {
+	return r.w.ComponentInfo(t)
+}
+
+// RegisteredComponents lists every registered component type, indexed by
+// component ID.
+func (r *ReadWorld) RegisteredComponents() []reflect.Type {
+	return r.w.RegisteredComponents()
+}
+
+// ReadComponent returns a copy of the entity's component of type `T`. Because
+// the result is a copy, the caller cannot modify the stored component.
+//
+// Parameters:
+//   - r: The read-only world view.
+//   - e: The Entity to read from.
+//
+// Returns:
+//   - The component value and true, or the zero value and false if the entity
+//     is invalid or has no `T`.
+func ReadComponent[T any](r *ReadWorld, e Entity) (T, bool) {
+	var zero T
+	if _, ok := r.w.lookupCompTypeID(reflect.TypeFor[T]()); !ok {
+		return zero, false // never registered, so no entity has it
+	}
+	if c := GetComponent[T](r.w, e); c != nil {
+		return *c, true
+	}
+	return zero, false
+}
+
+// ReadHas reports whether the entity is alive and has a component of
+// type `T`.
+//
+// Parameters:
+//   - r: The read-only world view.
+//   - e: The Entity to check.
+//
+// Returns:
+//   - true if the entity has `T`, false otherwise.
+func ReadHas[T any](r *ReadWorld, e Entity) bool {
+	w := r.w
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return false
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return false
+	}
+	return w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex].mask.has(id)
+}
+
+// ReadEach calls fn with a copy of the `T` component of every entity that has
+// one. The matching archetypes are collected under the world's read lock,
+// which is released before fn is first called, like Filter.Apply; the world
+// must not be changed structurally while ReadEach runs.
+//
+// Parameters:
+//   - r: The read-only world view.
+//   - fn: The function to call for each entity.
+func ReadEach[T any](r *ReadWorld, fn func(e Entity, c T)) {
+	w := r.w
+	w.checkOpen()
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return
+	}
+	w.mu.RLock()
+	var arches []*archetype
+	for _, a := range w.archetypes.archetypes {
+		if a.size > 0 && a.mask.has(id) {
+			arches = append(arches, a)
+		}
+	}
+	w.mu.RUnlock()
+	if debugChecks {
+		w.mu.beginIteration()
+		defer w.mu.endIteration()
+	}
+	for _, a := range arches {
+		col := unsafe.Slice((*T)(a.compPointers[id]), a.size)
+		for k, e := range a.entityIDs[:a.size] {
+			fn(e, col[k])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package teishoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+type unreadComp struct{ V int }
+
+func TestReadWorld(t *testing.T) {
+	w := NewWorld(4)
+	e := w.CreateEntity()
+	SetComponent(w, e, Position{X: 2})
+	SetComponent2(w, w.CreateEntity(), Position{X: 3}, Velocity{})
+	bare := w.CreateEntity()
+
+	r := w.ReadOnly()
+	if !r.IsValid(e) || r.EntityState(e) != EntityAlive {
+		t.Fatal("expected the entity to be alive through the view")
+	}
+	if p, ok := ReadComponent[Position](r, e); !ok || p.X != 2 {
+		t.Fatalf("unexpected ReadComponent result %v %v", p, ok)
+	}
+	if _, ok := ReadComponent[Velocity](r, e); ok {
+		t.Fatal("expected no Velocity on the entity")
+	}
+	if !ReadHas[Position](r, e) || ReadHas[Position](r, bare) || ReadHas[Health](r, e) {
+		t.Fatal("unexpected ReadHas results")
+	}
+	sum := float32(0)
+	ReadEach(r, func(_ Entity, p Position) {
+		sum += p.X
+	})
+	if sum != 5 {
+		t.Fatalf("expected ReadEach to visit both positions, got sum %v", sum)
+	}
+	if _, ok := ReadComponent[unreadComp](r, e); ok {
+		t.Fatal("expected no unreadComp on the entity")
+	}
+	if _, ok := w.lookupCompTypeID(reflect.TypeFor[unreadComp]()); ok {
+		t.Fatal("ReadComponent registered an unknown type")
+	}
+	// fn runs without the read lock, so it may take the write lock.
+	ReadEach(r, func(e Entity, p Position) {
+		w.SetUserData(e, uint64(p.X))
+	})
+	if r.GetUserData(e) != 2 {
+		t.Fatal("expected ReadEach's callback to set the user data")
+	}
+}
@@ -0,0 +1,155 @@
+package teishoku
+
+import (
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// Relation is a component linking its entity to a target entity. The marker
+// type `T` distinguishes relation kinds, so an entity can hold one relation
+// of each kind at once:
+//
+//	type Targets struct{}
+//	type FollowedBy struct{}
+//	Link[Targets](w, turret, enemy)
+//	if r := GetComponent[Relation[Targets]](w, turret); r != nil && r.Target != (Entity{}) { ... }
+//
+// Relations set with Link are indexed from their target: when the target is
+// removed, by any removal path, the Target of every entity linking to it is
+// reset to the zero Entity, so no component is left holding a dangling
+// handle. A Relation set directly with SetComponent is not indexed and gets no
+// such cleanup.
+type Relation[T any] struct {
+	Target Entity
+}
+
+// relationIndex maps target entity IDs to the entities linking to them
+// through one relation kind. Entries are not dropped when a source unlinks or
+// is removed; every entry is checked against the source's current component
+// before use, and stale entries are pruned when a list grows.
+type relationIndex map[uint32][]Entity
+
+// Link sets the relation of kind `T` from `from` to `to`, adding the Relation
+// component to `from` if needed and replacing any previous target of that
+// kind.
+//
+// Parameters:
+//   - w: The World where the entities reside.
+//   - from: The entity holding the relation.
+//   - to: The target entity.
+//
+// Returns:
+//   - false if either entity is invalid, in which case nothing changes.
+func Link[T any](w *World, from, to Entity) bool {
+	id := w.getCompTypeID(reflect.TypeFor[Relation[T]]())
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(from) || !w.IsValidNoLock(to) {
+		return false
+	}
+	prev := w.relationTargetNoLock(id, from)
+	relinked := prev != nil && *prev == to
+	if setComponentNoLock(w, from, Relation[T]{Target: to}) {
+		w.mutationVersion.Add(1)
+	}
+	if relinked && slices.Contains(w.relations[id][to.ID], from) {
+		return true // already indexed
+	}
+	if w.relations == nil {
+		w.relations = make(map[uint8]relationIndex)
+	}
+	idx := w.relations[id]
+	if idx == nil {
+		idx = make(relationIndex)
+		w.relations[id] = idx
+	}
+	list := idx[to.ID]
+	if len(list) == cap(list) {
+		list = w.pruneRelationNoLock(id, to, list)
+	}
+	idx[to.ID] = append(list, from)
+	return true
+}
+
+// Unlink removes the relation of kind `T` from an entity, i.e. its
+// Relation[T] component. It does nothing if the entity is invalid or has no
+// such relation.
+//
+// Parameters:
+//   - w: The World where the entity resides.
+//   - from: The entity holding the relation.
+func Unlink[T any](w *World, from Entity) {
+	RemoveComponent[Relation[T]](w, from)
+}
+
+// relationTargetNoLock returns a pointer to the target stored in e's relation
+// of component ID id, or nil if e is invalid or has no such relation. Every
+// Relation[T] has the same layout, so the target is read without knowing `T`.
+func (w *World) relationTargetNoLock(id uint8, e Entity) *Entity {
+	if !w.IsValidNoLock(e) {
+		return nil
+	}
+	meta := w.entities.metas[e.ID]
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	if !a.mask.has(id) {
+		return nil
+	}
+	return (*Entity)(unsafe.Add(a.compPointers[id], uintptr(meta.index)*a.compSizes[id]))
+}
+
+// pruneRelationNoLock drops from list the sources that no longer link to
+// target through the relation id, and the duplicates left by linking the same
+// source to target several times.
+func (w *World) pruneRelationNoLock(id uint8, target Entity, list []Entity) []Entity {
+	kept := list[:0]
+	seen := make(map[Entity]struct{}, len(list))
+	for _, src := range list {
+		if _, dup := seen[src]; dup {
+			continue
+		}
+		if t := w.relationTargetNoLock(id, src); t != nil && *t == target {
+			kept = append(kept, src)
+			seen[src] = struct{}{}
+		}
+	}
+	clear(list[len(kept):])
+	return kept
+}
+
+// releaseRelationsNoLock resets the target of every relation pointing to the
+// entity being released, and marks each reset relation as changed. It is
+// called from releaseEntityNoLock, before the entity's metadata is
+// invalidated; it writes in place and never moves entities, so it is safe in
+// the middle of batch removals.
+func (w *World) releaseRelationsNoLock(target Entity) {
+	for id, idx := range w.relations {
+		list, ok := idx[target.ID]
+		if !ok {
+			continue
+		}
+		delete(idx, target.ID)
+		for _, src := range list {
+			if t := w.relationTargetNoLock(id, src); t != nil && *t == target {
+				*t = Entity{}
+				w.markChanged(id, src.ID)
+			}
+		}
+	}
+}
+
+// copyRelations replaces the relation indexes in dst with a copy of src, for
+// Checkpoint and Restore.
+func copyRelations(dst *map[uint8]relationIndex, src map[uint8]relationIndex) {
+	clear(*dst)
+	for id, idx := range src {
+		if *dst == nil {
+			*dst = make(map[uint8]relationIndex, len(src))
+		}
+		cp := make(relationIndex, len(idx))
+		for target, list := range idx {
+			cp[target] = append([]Entity(nil), list...)
+		}
+		(*dst)[id] = cp
+	}
+}
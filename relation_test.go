@@ -0,0 +1,119 @@
+package teishoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+type targets struct{}
+type follows struct{}
+
+func TestRelationCleanup(t *testing.T) {
+	w := NewWorld(16)
+	enemy := w.CreateEntity()
+	leader := w.CreateEntity()
+	var turrets []Entity
+	for range 3 {
+		e := w.CreateEntity()
+		SetComponent(w, e, Position{})
+		if !Link[targets](w, e, enemy) {
+			t.Fatal("Link failed")
+		}
+		Link[follows](w, e, leader)
+		turrets = append(turrets, e)
+	}
+	Link[targets](w, turrets[2], leader) // retargeted away from enemy
+
+	w.RemoveEntity(enemy)
+	for i, e := range turrets[:2] {
+		if r := GetComponent[Relation[targets]](w, e); r == nil || r.Target != (Entity{}) {
+			t.Errorf("turret %d still targets the removed enemy: %v", i, r)
+		}
+		if r := GetComponent[Relation[follows]](w, e); r == nil || r.Target != leader {
+			t.Errorf("turret %d lost an unrelated relation kind: %v", i, r)
+		}
+	}
+	if r := GetComponent[Relation[targets]](w, turrets[2]); r == nil || r.Target != leader {
+		t.Errorf("retargeted turret was reset: %v", r)
+	}
+
+	Unlink[follows](w, turrets[0])
+	if GetComponent[Relation[follows]](w, turrets[0]) != nil {
+		t.Error("Unlink kept the relation")
+	}
+	NewFilter[Position](w).RemoveEntities()
+	w.RemoveEntity(leader) // sources already gone
+	if Link[targets](w, turrets[0], w.CreateEntity()) {
+		t.Error("Link succeeded from a removed entity")
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRelationRestore(t *testing.T) {
+	w := NewWorld(8)
+	target := w.CreateEntity()
+	src := w.CreateEntity()
+	Link[targets](w, src, target)
+	c := w.Checkpoint()
+	newcomer := w.CreateEntity()
+	Link[targets](w, newcomer, target)
+	Unlink[targets](w, src)
+	w.Restore(c)
+	w.RemoveEntity(target)
+	if r := GetComponent[Relation[targets]](w, src); r == nil || r.Target != (Entity{}) {
+		t.Errorf("restored relation not cleaned up: %v", r)
+	}
+}
+
+func TestRelationRelinkDoesNotGrowIndex(t *testing.T) {
+	w := NewWorld(8)
+	a, b, c := w.CreateEntity(), w.CreateEntity(), w.CreateEntity()
+	for range 1000 {
+		Link[targets](w, a, b)
+	}
+	for range 1000 {
+		Link[targets](w, a, c)
+		Link[targets](w, a, b)
+	}
+	id, _ := w.lookupCompTypeID(reflect.TypeFor[Relation[targets]]())
+	if n := len(w.relations[id][b.ID]); n > 2 {
+		t.Errorf("expected relinking to keep the index small, got %d entries", n)
+	}
+	w.RemoveEntity(b)
+	if r := GetComponent[Relation[targets]](w, a); r.Target != (Entity{}) {
+		t.Errorf("relation not cleaned up: %v", r)
+	}
+}
+
+func TestRelationRestoreKeepsDirectRelationsUnindexed(t *testing.T) {
+	w := NewWorld(8)
+	target := w.CreateEntity()
+	direct := w.CreateEntity()
+	SetComponent(w, direct, Relation[targets]{Target: target})
+	Link[targets](w, w.CreateEntity(), target)
+	w.Restore(w.Checkpoint())
+	w.RemoveEntity(target)
+	if r := GetComponent[Relation[targets]](w, direct); r == nil || r.Target != target {
+		t.Errorf("a relation set with SetComponent was indexed by Restore: %v", r)
+	}
+}
+
+func TestRelationCleanupMarksChanged(t *testing.T) {
+	w := NewWorld(8)
+	TrackChanges[Relation[targets]](w)
+	TrackVersions[Relation[targets]](w)
+	target, src := w.CreateEntity(), w.CreateEntity()
+	Link[targets](w, src, target)
+	w.EndFrame()
+	version := ComponentVersion[Relation[targets]](w, src)
+	w.RemoveEntity(target)
+	id, _ := w.lookupCompTypeID(reflect.TypeFor[Relation[targets]]())
+	if !w.isChanged(id, src.ID) {
+		t.Error("expected the reset relation to be marked as changed")
+	}
+	if ComponentVersion[Relation[targets]](w, src) == version {
+		t.Error("expected the reset relation's version to advance")
+	}
+}
@@ -0,0 +1,125 @@
+package teishoku
+
+import (
+	"reflect"
+	"sync"
+)
+
+// system is a function registered with RegisterSystem, together with the
+// components it reads and writes.
+type system struct {
+	reads  bitmask256
+	writes bitmask256
+	fn     func()
+}
+
+// conflicts reports whether s and o cannot run at the same time, i.e. one of
+// them writes a component the other reads or writes.
+func (s *system) conflicts(o *system) bool {
+	return s.writes.intersects(o.reads) || s.writes.intersects(o.writes) || o.writes.intersects(s.reads)
+}
+
+// scheduler stores the registered systems and the stages they run in.
+type scheduler struct {
+	mu      sync.Mutex
+	systems []system
+	stages  [][]int // indexes into systems; the systems of a stage run concurrently
+}
+
+// RegisterSystem adds fn to the systems run by RunSystems, declaring the
+// component types it reads and the ones it writes. The declarations let
+// RunSystems run systems concurrently when they cannot interfere: systems that
+// only read, or that touch disjoint components, share a stage, while a system
+// that writes a component another one reads or writes always runs after the
+// conflicting systems registered before it.
+//
+// The declarations are trusted: a system accessing components it did not
+// declare may race with the systems it runs alongside.
+//
+// Parameters:
+//   - reads: The component types fn reads.
+//   - writes: The component types fn writes. A type listed in both is
+//     treated as written.
+//   - fn: The system function.
+func (w *World) RegisterSystem(reads []reflect.Type, writes []reflect.Type, fn func()) {
+	s := system{fn: fn}
+	for _, t := range reads {
+		s.reads.set(w.getCompTypeID(t))
+	}
+	for _, t := range writes {
+		s.writes.set(w.getCompTypeID(t))
+	}
+	sch := &w.systems
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	sch.systems = append(sch.systems, s)
+	idx := len(sch.systems) - 1
+	// Place the system in the stage after the last one holding a conflicting
+	// system, so conflicting systems keep their registration order.
+	stage := 0
+	for i := len(sch.stages) - 1; i >= 0 && stage == 0; i-- {
+		for _, j := range sch.stages[i] {
+			if sch.systems[j].conflicts(&sch.systems[idx]) {
+				stage = i + 1
+				break
+			}
+		}
+	}
+	if stage == len(sch.stages) {
+		sch.stages = append(sch.stages, nil)
+	}
+	sch.stages[stage] = append(sch.stages[stage], idx)
+}
+
+// RunSystems runs every system registered with RegisterSystem once. Systems
+// are grouped into stages run one after another; the systems of a stage have
+// no conflicting component access and run concurrently, each on its own
+// goroutine. Worlds created with WithSingleThreaded run all systems
+// sequentially on the calling goroutine, in registration order.
+//
+// If a system panics, RunSystems waits for the rest of its stage and then
+// re-panics with the same value on the calling goroutine. The systems are run
+// without holding the scheduler's lock, so a system may call RegisterSystem;
+// the new system runs from the next RunSystems on.
+func (w *World) RunSystems() {
+	sch := &w.systems
+	sch.mu.Lock()
+	// Snapshot the stages; single-threaded worlds run one system per stage.
+	var stages [][]func()
+	if w.mu.disabled {
+		for i := range sch.systems {
+			stages = append(stages, []func(){sch.systems[i].fn})
+		}
+	} else {
+		stages = make([][]func(), len(sch.stages))
+		for k, stage := range sch.stages {
+			for _, i := range stage {
+				stages[k] = append(stages[k], sch.systems[i].fn)
+			}
+		}
+	}
+	sch.mu.Unlock()
+	for _, stage := range stages {
+		if len(stage) == 1 {
+			stage[0]()
+			continue
+		}
+		var wg sync.WaitGroup
+		var once sync.Once
+		var failure any
+		for _, fn := range stage {
+			wg.Go(func() {
+				defer func() {
+					if r := recover(); r != nil {
+						once.Do(func() { failure = r })
+					}
+				}()
+				fn()
+			})
+		}
+		wg.Wait()
+		if failure != nil {
+			panic(failure)
+		}
+	}
+}
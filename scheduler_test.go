@@ -0,0 +1,84 @@
+package teishoku
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSchedulerStages(t *testing.T) {
+	w := NewWorld(1)
+	pos := reflect.TypeFor[Position]()
+	vel := reflect.TypeFor[Velocity]()
+	hp := reflect.TypeFor[Health]()
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+	w.RegisterSystem([]reflect.Type{pos}, nil, record("readPos"))
+	w.RegisterSystem([]reflect.Type{vel}, nil, record("readVel"))
+	w.RegisterSystem([]reflect.Type{vel}, []reflect.Type{pos}, record("move"))
+	w.RegisterSystem(nil, []reflect.Type{hp}, record("heal"))
+	w.RegisterSystem([]reflect.Type{pos}, nil, record("render"))
+
+	stages := w.systems.stages
+	want := [][]int{{0, 1, 3}, {2}, {4}}
+	if !slices.EqualFunc(stages, want, slices.Equal) {
+		t.Fatalf("expected stages %v, got %v", want, stages)
+	}
+	w.RunSystems()
+	if len(order) != 5 {
+		t.Fatalf("expected 5 systems to run, got %v", order)
+	}
+	if i := slices.Index(order, "move"); i < 3 || order[4] != "render" {
+		t.Fatalf("conflicting systems ran out of order: %v", order)
+	}
+}
+
+func TestSchedulerPanicAndSingleThreaded(t *testing.T) {
+	w := NewWorld(1)
+	ran := false
+	w.RegisterSystem(nil, nil, func() { panic("boom") })
+	w.RegisterSystem(nil, nil, func() { ran = true })
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("expected the system's panic to propagate, got %v", r)
+			}
+		}()
+		w.RunSystems()
+	}()
+	if !ran {
+		t.Fatal("expected the rest of the stage to finish")
+	}
+
+	st := NewWorld(1, WithSingleThreaded())
+	var order []int
+	for i := range 3 {
+		st.RegisterSystem(nil, nil, func() { order = append(order, i) })
+	}
+	st.RunSystems()
+	if !slices.Equal(order, []int{0, 1, 2}) {
+		t.Fatalf("expected sequential registration order, got %v", order)
+	}
+
+	// A system may register another one, which runs from the next call on.
+	added, registered := 0, false
+	st.RegisterSystem(nil, nil, func() {
+		if !registered {
+			registered = true
+			st.RegisterSystem(nil, nil, func() { added++ })
+		}
+	})
+	st.RunSystems()
+	st.RunSystems()
+	if added != 1 {
+		t.Fatal("expected the system registered during RunSystems to run")
+	}
+}
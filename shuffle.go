@@ -0,0 +1,40 @@
+package teishoku
+
+import "math/rand/v2"
+
+// Shuffle randomly permutes the storage order of the entities within each
+// archetype matched by the filter, moving every component column in lockstep
+// with the entity list. Filters, Apply and the other iterators then visit the
+// entities of an archetype in the new order, which lets AI and spawn systems
+// process entities in a randomized order without bias towards creation order.
+// Archetypes themselves keep their relative order.
+//
+// The permutation is drawn from rng only, so the same seed and the same world
+// state produce the same order. It costs O(n·s), where n is the number of
+// matched entities and s the size in bytes of all the components of their
+// archetypes, since every row is swapped as a whole. Reordering invalidates
+// cached entity lists and iterators, like any structural change.
+//
+// Parameters:
+//   - rng: The source of randomness.
+func (c *queryCache) Shuffle(rng *rand.Rand) {
+	c.checkWorld()
+	w := c.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+	shuffled := false
+	for _, a := range c.matchingArches {
+		for i := a.size - 1; i > 0; i-- {
+			if j := rng.IntN(i + 1); j != i {
+				w.swapRows(a, i, j)
+			}
+		}
+		shuffled = shuffled || a.size > 1
+	}
+	if shuffled {
+		w.mutationVersion.Add(1)
+	}
+}
@@ -0,0 +1,46 @@
+package teishoku
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestFilterShuffle(t *testing.T) {
+	order := func(seed uint64) []Entity {
+		w := NewWorld(64, WithDeterministicIDs())
+		for i := range 50 {
+			e := w.CreateEntity()
+			SetComponent2(w, e, Position{X: float32(e.ID)}, Health{HP: i})
+		}
+		f := NewFilter2[Position, Health](w)
+		f.Shuffle(rand.New(rand.NewPCG(seed, 0)))
+		f.Reset()
+		var ents []Entity
+		for f.Next() {
+			p, _ := f.Get()
+			if p.X != float32(f.Entity().ID) {
+				t.Fatalf("components of %v were not moved with it", f.Entity())
+			}
+			if GetComponent[Position](w, f.Entity()) != p {
+				t.Fatalf("metadata of %v is stale", f.Entity())
+			}
+			ents = append(ents, f.Entity())
+		}
+		if err := w.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		return ents
+	}
+	a, b := order(1), order(1)
+	if !slices.Equal(a, b) {
+		t.Error("the same seed should produce the same order")
+	}
+	sorted := slices.SortedFunc(slices.Values(a), func(x, y Entity) int { return int(x.ID) - int(y.ID) })
+	if slices.Equal(a, sorted) {
+		t.Error("expected the order to change")
+	}
+	if len(a) != 50 || slices.Equal(a, order(2)) {
+		t.Error("expected a permutation depending on the seed")
+	}
+}
@@ -0,0 +1,107 @@
+package teishoku
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"sync/atomic"
+)
+
+// Stats holds counters of the World's structural work, as returned by
+// World.Stats. Each counter covers the time since the World was created or
+// since the last call to ResetStats.
+type Stats struct {
+	ArchetypeCreations uint64 // archetypes created for a new component layout
+	EntityMoves        uint64 // entities moved between archetypes by adding or removing components
+	Expansions         uint64 // times the entity storage of the world grew
+	FilterRescans      uint64 // times a filter refreshed its archetype list after a layout change
+}
+
+// worldStats stores the counters behind Stats. They are atomic because filter
+// rescans happen while the world's lock is only held for reading.
+type worldStats struct {
+	archetypeCreations atomic.Uint64
+	entityMoves        atomic.Uint64
+	expansions         atomic.Uint64
+	filterRescans      atomic.Uint64
+}
+
+// Stats returns the counters of structural work done by the World, making the
+// cost of archetype churn, storage growth and stale filters measurable. Sample
+// it once per frame, followed by ResetStats, to get per-frame numbers.
+//
+// Returns:
+//   - A snapshot of the counters.
+func (w *World) Stats() Stats {
+	return Stats{
+		ArchetypeCreations: w.stats.archetypeCreations.Load(),
+		EntityMoves:        w.stats.entityMoves.Load(),
+		Expansions:         w.stats.expansions.Load(),
+		FilterRescans:      w.stats.filterRescans.Load(),
+	}
+}
+
+// ResetStats sets every counter returned by Stats back to zero.
+func (w *World) ResetStats() {
+	w.stats.archetypeCreations.Store(0)
+	w.stats.entityMoves.Store(0)
+	w.stats.expansions.Store(0)
+	w.stats.filterRescans.Store(0)
+}
+
+// FilterInfo describes one group of live filters sharing the same query, as
+// returned by World.ActiveFilters.
+type FilterInfo struct {
+	Mask       Mask           // components the filters require
+	Types      []reflect.Type // the component types of Mask, in ID order
+	Exact      bool           // the filters match archetypes equal to Mask only
+	Filters    int            // number of live filters with this query
+	Archetypes int            // matching archetypes, as of the filters' last refresh
+	Entities   int            // entities stored in those archetypes
+}
+
+// ActiveFilters lists the queries of the world's live filters, for debugging
+// why a filter is empty and for spotting systems that build redundant
+// filters. Filters with the same component types and matching mode share one
+// archetype list, and are reported as one entry with their count; a filter
+// is live from its creation until Close, so filters dropped without Close are
+// still counted. Composite filters (see Union and Intersect) keep lists of
+// their own and are not reported. Entries are sorted by mask.
+//
+// Returns:
+//   - One FilterInfo per distinct query.
+func (w *World) ActiveFilters() []FilterInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	w.matchMu.Lock()
+	defer w.matchMu.Unlock()
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	infos := make([]FilterInfo, 0, len(w.matchSets))
+	for key, ms := range w.matchSets {
+		info := FilterInfo{Mask: key.mask, Types: w.maskTypesNoLock(key.mask), Exact: key.exact}
+		ms.mu.Lock()
+		info.Filters = ms.refs
+		info.Archetypes = len(ms.arches)
+		for _, a := range ms.arches {
+			info.Entities += a.size
+		}
+		ms.mu.Unlock()
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b FilterInfo) int {
+		for i := len(a.Mask) - 1; i >= 0; i-- {
+			if c := cmp.Compare(a.Mask[i], b.Mask[i]); c != 0 {
+				return c
+			}
+		}
+		switch {
+		case a.Exact == b.Exact:
+			return 0
+		case a.Exact:
+			return 1
+		}
+		return -1
+	})
+	return infos
+}
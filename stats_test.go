@@ -0,0 +1,80 @@
+package teishoku
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWorldStats(t *testing.T) {
+	w := NewWorld(2)
+	f := NewFilter[Position](w)
+	w.CreateEntity()
+	w.ResetStats()
+
+	e := w.CreateEntity()                  // existing empty archetype
+	SetComponent(w, e, Position{})         // new archetype, one move
+	SetComponent(w, e, Velocity{})         // new archetype, one move
+	NewBuilder[Position](w).NewEntities(4) // grows past the initial capacity
+	f.Reset()
+
+	s := w.Stats()
+	if s.ArchetypeCreations != 2 {
+		t.Errorf("ArchetypeCreations: expected 2, got %d", s.ArchetypeCreations)
+	}
+	if s.EntityMoves != 2 {
+		t.Errorf("EntityMoves: expected 2, got %d", s.EntityMoves)
+	}
+	if s.Expansions == 0 {
+		t.Error("Expansions: expected the world to have grown")
+	}
+	if s.FilterRescans != 1 {
+		t.Errorf("FilterRescans: expected 1, got %d", s.FilterRescans)
+	}
+
+	w.RemoveEntity(e)
+	if got := w.Stats().EntityMoves; got != 2 {
+		t.Errorf("removing an entity must not count as a move, got %d", got)
+	}
+
+	w.ResetStats()
+	if s := w.Stats(); s != (Stats{}) {
+		t.Errorf("expected zero stats after ResetStats, got %+v", s)
+	}
+}
+
+func TestActiveFilters(t *testing.T) {
+	w := NewWorld(8)
+	for range 3 {
+		SetComponent2(w, w.CreateEntity(), Position{}, Velocity{})
+	}
+	SetComponent(w, w.CreateEntity(), Position{})
+	a := NewFilter[Position](w)
+	NewFilter[Position](w)
+	NewFilter2[Position, Velocity](w)
+	NewFilterExact[Position](w)
+
+	infos := w.ActiveFilters()
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 distinct queries, got %+v", infos)
+	}
+	byKey := make(map[string]FilterInfo)
+	for _, info := range infos {
+		key := fmt.Sprint(info.Types, info.Exact)
+		byKey[key] = info
+	}
+	pos := byKey[fmt.Sprint([]reflect.Type{reflect.TypeFor[Position]()}, false)]
+	if pos.Filters != 2 || pos.Archetypes != 2 || pos.Entities != 4 {
+		t.Errorf("unexpected Position entry %+v", pos)
+	}
+	if exact := byKey[fmt.Sprint([]reflect.Type{reflect.TypeFor[Position]()}, true)]; exact.Filters != 1 || exact.Entities != 1 {
+		t.Errorf("unexpected exact Position entry %+v", exact)
+	}
+
+	a.Close()
+	for _, info := range w.ActiveFilters() {
+		if !info.Exact && len(info.Types) == 1 && info.Filters != 1 {
+			t.Errorf("expected Close to drop a filter, got %+v", info)
+		}
+	}
+}
@@ -19,10 +19,8 @@ type Builder{{.N}}[{{.Types}}] struct {
 func NewBuilder{{.N}}[{{.Types}}](w *World) *Builder{{.N}}[{{.TypeVars}}] {
 	{{range .Components}}t{{.Index}} := reflect.TypeFor[{{.TypeName}}]()
 	{{end}}
-	w.components.mu.RLock()
-	{{range .Components}}id{{.Index}} := w.getCompTypeIDNoLock(t{{.Index}})
+	{{range .Components}}id{{.Index}} := w.getCompTypeID(t{{.Index}})
 	{{end}}
-	w.components.mu.RUnlock()
 
 	if {{.DuplicateIDs}} {
 		panic("ecs: duplicate component types in Builder{{.N}}")
@@ -51,11 +49,48 @@ func (b *Builder{{.N}}[{{.TypeVars}}]) New(w *World) *Builder{{.N}}[{{.TypeVars}
 // any garbage collection overhead.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
 func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntity() Entity {
 	return b.world.createEntity(b.arch)
 }
 
+// TryNewEntity is like NewEntity but reports explicitly whether the entity
+// could be created, i.e. whether the limit set by World.SetMaxEntities still
+// allows it.
+//
+// Returns:
+//   - The newly created Entity and true, or the zero Entity and false.
+func (b *Builder{{.N}}[{{.TypeVars}}]) TryNewEntity() (Entity, bool) {
+	e := b.world.createEntity(b.arch)
+	return e, e.Version != 0
+}
+
+// NewEntityWith creates a single new entity with the {{.N}} components
+// initialized to the provided values, under one acquisition of the World's
+// lock.
+//
+// Parameters:
+{{range .Components}}//   - comp{{.Index}}: The initial value for the component {{.TypeName}}.
+{{end}}//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntityWith({{.BuilderVars}}) Entity {
+	w := b.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	a := b.arch
+	e := w.createEntityNoLock(a)
+	if e.Version == 0 {
+		return e
+	}
+	idx := uintptr(a.size - 1)
+	{{range .Components}}*(*{{.TypeName}})(unsafe.Add(a.compPointers[b.id{{.Index}}], idx*a.compSizes[b.id{{.Index}}])) = {{.BuilderVarName}}
+	{{end}}w.mutationVersion.Add(1)
+	return e
+}
+
 // NewEntities creates a batch of `count` entities with the {{.N}} components
 // defined by the builder. This is the most performant method for creating many
 // entities at once. This method does not return the created entities to avoid
@@ -63,32 +98,23 @@ func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by World.SetMaxEntities is reached.
+func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
-	}
-	w.mutationVersion.Add(1)
+	return n
 }
 
 // NewEntitiesWithValueSet creates a batch of `count` entities and initializes
@@ -97,34 +123,63 @@ func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntities(count int) {
 // Parameters:
 //   - count: The number of entities to create.
 {{range .Components}}//   - comp{{.Index}}: The initial value for the component {{.TypeName}}.
-{{end}}func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntitiesWithValueSet(count int, {{.BuilderVars}}) {
+{{end}}//
+// Returns:
+//   - The number of entities created (see NewEntities).
+func (b *Builder{{.N}}[{{.TypeVars}}]) NewEntitiesWithValueSet(count int, {{.BuilderVars}}) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	w := b.world
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	a := b.arch
-	for len(w.entities.freeIDs) < count {
-		w.expand()
-	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		{{range .Components}}*(*{{.TypeName}})(unsafe.Pointer(uintptr(a.compPointers[b.id{{.Index}}]) + uintptr(startSize+k)*a.compSizes[b.id{{.Index}}])) = {{.BuilderVarName}}
-		{{end}}
-		w.entities.nextEntityVer++
+	startSize, n := w.createEntitiesNoLock(a, count)
+	{{range .Components}}fillColumn(a, b.id{{.Index}}, startSize, n, {{.BuilderVarName}})
+	{{end -}}
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	w.mutationVersion.Add(1)
+	return n
+}
+
+// AsyncBuilder{{.N}} queues the creation of entities with the components
+// {{.TypeVars}} from any goroutine. Requests are applied when the world's
+// owner calls World.FlushAsync, so workers such as asset loaders can ask for
+// entities without contending on the world's lock.
+type AsyncBuilder{{.N}}[{{.Types}}] struct {
+	builder *Builder{{.N}}[{{.TypeVars}}]
+}
+
+// NewAsyncBuilder{{.N}} creates an AsyncBuilder{{.N}} for the components
+// {{.TypeVars}}. The returned builder's methods are safe for concurrent use even
+// on a world created WithSingleThreaded, since they never touch the world.
+//
+// Parameters:
+//   - w: The World in which to create entities.
+//
+// Returns:
+//   - A pointer to the configured `AsyncBuilder{{.N}}`.
+func NewAsyncBuilder{{.N}}[{{.Types}}](w *World) *AsyncBuilder{{.N}}[{{.TypeVars}}] {
+	return &AsyncBuilder{{.N}}[{{.TypeVars}}]{builder: NewBuilder{{.N}}[{{.TypeVars}}](w)}
+}
+
+// NewEntity queues the creation of a single entity with zero-valued (or
+// defaulted) components. It is safe for concurrent use.
+func (b *AsyncBuilder{{.N}}[{{.TypeVars}}]) NewEntity() {
+	b.builder.world.enqueueAsync(func() { b.builder.NewEntity() })
+}
+
+// NewEntitiesWithValueSet queues the creation of `count` entities initialized
+// to the provided values. It is safe for concurrent use.
+//
+// Parameters:
+//   - count: The number of entities to create.
+{{range .Components}}//   - comp{{.Index}}: The initial value for the component {{.TypeName}}.
+{{end}}func (b *AsyncBuilder{{.N}}[{{.TypeVars}}]) NewEntitiesWithValueSet(count int, {{.BuilderVars}}) {
+	b.builder.world.enqueueAsync(func() {
+		b.builder.NewEntitiesWithValueSet(count, {{range $i, $e := .Components}}{{if $i}}, {{end}}{{$e.BuilderVarName}}{{end}})
+	})
 }
 
 // Get retrieves pointers to the components for the given entity.
@@ -188,8 +243,8 @@ func (b *Builder{{.N}}[{{.TypeVars}}]) Get(e Entity) ({{.ReturnTypes}}) {
 	}
 	{{end}}
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -207,6 +262,7 @@ func (b *Builder{{.N}}[{{.TypeVars}}]) Get(e Entity) ({{.ReturnTypes}}) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
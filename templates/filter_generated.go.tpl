@@ -6,8 +6,11 @@ type Filter{{.N}}[{{.Types}}] struct {
 	curEntityIDs []Entity
 	curMatchIdx  int // index into matchingArches
 	curIdx       int // index into the current archetype's entity/component array
+	curOffset    int // number of entities in the archetypes already iterated
 	compSizes    [{{.N}}]uintptr
 	curArchSize  int
+	snapshot     []Entity // reused by EachSafe
+	limit        int      // total entities to yield after Take, or -1
 	ids          [{{.N}}]uint8
 }
 
@@ -20,6 +23,23 @@ type Filter{{.N}}[{{.Types}}] struct {
 // Returns:
 //   - A pointer to the newly created `Filter{{.N}}`.
 func NewFilter{{.N}}[{{.Types}}](w *World) *Filter{{.N}}[{{.TypeVars}}] {
+	return newFilter{{.N}}[{{.TypeVars}}](w, false)
+}
+
+// NewFilterExact{{.N}} creates a new `Filter` that iterates only over entities
+// whose components are exactly {{.TypeVars}}, with no additional components.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - A pointer to the newly created `Filter{{.N}}`.
+func NewFilterExact{{.N}}[{{.Types}}](w *World) *Filter{{.N}}[{{.TypeVars}}] {
+	return newFilter{{.N}}[{{.TypeVars}}](w, true)
+}
+
+func newFilter{{.N}}[{{.Types}}](w *World, exact bool) *Filter{{.N}}[{{.TypeVars}}] {
+	w.checkOpen()
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	{{range .Components}}id{{.Index}} := w.getCompTypeID(reflect.TypeFor[{{.TypeName}}]())
@@ -38,6 +58,7 @@ func NewFilter{{.N}}[{{.Types}}](w *World) *Filter{{.N}}[{{.TypeVars}}] {
 	}
 	{{range $i, $e := .Components}}f.compSizes[{{$i}}] = w.components.compIDToSize[id{{$e.Index}}]
 	{{end}}
+	f.exact = exact
 	f.updateMatching()
 	f.updateCachedEntities()
 	f.doReset()
@@ -45,26 +66,44 @@ func NewFilter{{.N}}[{{.Types}}](w *World) *Filter{{.N}}[{{.TypeVars}}] {
 }
 
 // New is a convenience method that constructs a new `Filter` instance for the
-// same component types, equivalent to calling `NewFilter{{.N}}`.
+// same component types and matching mode, equivalent to calling
+// `NewFilter{{.N}}` (or `NewFilterExact{{.N}}`). The returned filter is bound to
+// `w`; the receiver keeps querying its original world.
 func (f *Filter{{.N}}[{{.TypeVars}}]) New(w *World) *Filter{{.N}}[{{.TypeVars}}] {
-	return NewFilter{{.N}}[{{.TypeVars}}](w)
+	return newFilter{{.N}}[{{.TypeVars}}](w, f.exact)
 }
 
 // Reset rewinds the filter's iterator to the beginning. It should be called if
 // you need to iterate over the same set of entities multiple times.
 func (f *Filter{{.N}}[{{.TypeVars}}]) Reset() {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	f.doReset()
 }
 
+// ResetUnlocked rewinds the filter like Reset, without taking the world's
+// read lock. Next and Get never lock, so a loop of ResetUnlocked and Next runs
+// entirely lock-free, which removes the per-frame locking cost of filters
+// iterated on a hot path. It must only be used when the caller guarantees
+// that no other goroutine mutates the world during the call, e.g. worlds
+// driven from a single goroutine that were not created with
+// WithSingleThreaded.
+func (f *Filter{{.N}}[{{.TypeVars}}]) ResetUnlocked() {
+	f.checkWorld()
+	f.doReset()
+}
+
 func (f *Filter{{.N}}[{{.TypeVars}}]) doReset() {
 	if f.IsStale() {
 		f.updateMatching()
 		f.updateCachedEntities()
 	}
+	f.sortBySize()
+	f.limit = -1
 	f.curMatchIdx = 0
 	f.curIdx = -1
+	f.curOffset = 0
 	if len(f.matchingArches) > 0 {
 		a := f.matchingArches[0]
 		{{range $i, $e := .Components}}f.curBases[{{$i}}] = a.compPointers[f.ids[{{$i}}]]
@@ -76,14 +115,38 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) doReset() {
 	}
 }
 
+// Take rewinds the filter like Reset, but limits the iteration to the first n
+// matching entities: Next returns false once n entities have been yielded,
+// even in the middle of an archetype. The limit lasts until the next Reset.
+//
+// Parameters:
+//   - n: The maximum number of entities to yield.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Take(n int) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	defer f.world.mu.RUnlock()
+	f.doReset()
+	f.limit = max(n, 0)
+	f.curArchSize = min(f.curArchSize, f.limit)
+}
+
 // Next advances the filter to the next matching entity. It returns true if an
 // entity was found, and false if the iteration is complete. This method must
 // be called before accessing the entity or its components.
 //
+// Next takes no lock: the size and storage of the current archetype are read
+// when the iteration enters it. Structural changes (creating, removing or
+// moving entities) must therefore not happen between Reset and the end of the
+// loop, from this goroutine or any other; component writes are fine. Builds
+// with the ecsdebug tag panic on the first Next after such a change.
+//
 // Returns:
 //   - true if another matching entity was found, false otherwise.
 func (f *Filter{{.N}}[{{.TypeVars}}]) Next() bool {
 	f.curIdx++
+	if debugChecks {
+		f.checkIteration(f.curIdx == 0 && f.curOffset == 0 && f.curMatchIdx == 0)
+	}
 	if f.curIdx < f.curArchSize {
 		return true
 	}
@@ -91,7 +154,18 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) Next() bool {
 }
 
 func (f *Filter{{.N}}[{{.TypeVars}}]) nextArchetype() bool {
+	f.curOffset += f.curArchSize
+	if f.limit >= 0 && f.curOffset >= f.limit {
+		return false
+	}
+	if f.curMatchIdx == 0 && f.curOffset == 0 && f.curArchSize == 0 {
+		f.refreshBeforeStart() // nothing yielded since the reset
+		f.curMatchIdx = -1
+	}
 	f.curMatchIdx++
+	for f.curMatchIdx < len(f.matchingArches) && f.matchingArches[f.curMatchIdx].size == 0 {
+		f.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if f.curMatchIdx >= len(f.matchingArches) {
 		return false
 	}
@@ -102,16 +176,34 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) nextArchetype() bool {
 	
 	f.curEntityIDs = a.entityIDs
 	f.curArchSize = a.size
+	if f.limit >= 0 {
+		f.curArchSize = min(a.size, f.limit-f.curOffset)
+	}
 	f.curIdx = 0
 	return true
 }
 
+// Index returns the position of the current entity in the whole iteration:
+// 0 for the first entity yielded after Reset, 1 for the second, and so on
+// across archetype boundaries. It lines up with the order of Entities, so it
+// can index a flat buffer of per-entity results. This should only be called
+// after `Next()` has returned true.
+//
+// Returns:
+//   - The 0-based index of the current entity.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Index() int {
+	return f.curOffset + f.curIdx
+}
+
 // Entity returns the current `Entity` in the iteration. This should only be
 // called after `Next()` has returned true.
 //
 // Returns:
 //   - The current Entity.
 func (f *Filter{{.N}}[{{.TypeVars}}]) Entity() Entity {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
 	return f.curEntityIDs[f.curIdx]
 }
 
@@ -122,14 +214,365 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) Entity() Entity {
 // Returns:
 //   - Pointers to the component data ({{.ReturnTypes}}).
 func (f *Filter{{.N}}[{{.TypeVars}}]) Get() ({{.ReturnTypes}}) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	if accessProfiling {
+		for _, id := range f.ids {
+			f.world.countRead(id)
+		}
+		f.world.touchEntity(f.curEntityIDs[f.curIdx].ID)
+	}
 	return {{range $i, $e := .Components}}{{if $i}},
 		{{end}}(*{{$e.TypeName}})(unsafe.Add(f.curBases[{{$i}}], uintptr(f.curIdx)*f.compSizes[{{$i}}])){{end}}
 }
 
+// GetValues returns copies of the {{.N}} components ({{.TypeVars}}) for the
+// current entity in the iteration. This should only be called after `Next()`
+// has returned true.
+//
+// Unlike Get, the results do not point into archetype storage, so they stay
+// safe to keep after later structural changes.
+//
+// Returns:
+//   - Copies of the component data ({{.TypeVars}}).
+func (f *Filter{{.N}}[{{.TypeVars}}]) GetValues() ({{.TypeVars}}) {
+	if debugChecks {
+		checkCursor(f.curIdx, f.curArchSize)
+	}
+	return {{range $i, $e := .Components}}{{if $i}},
+		{{end}}*(*{{$e.TypeName}})(unsafe.Add(f.curBases[{{$i}}], uintptr(f.curIdx)*f.compSizes[{{$i}}])){{end}}
+}
+
+// Apply calls fn with pointers to the components {{.TypeVars}} of every
+// matching entity. It walks the archetype columns directly instead of going
+// through Next and Get, which makes it the fastest way to run a simple system
+// over a filter. Apply does not use or disturb the filter's iterator state.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Apply(fn func({{.ReturnTypes}})) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		{{range $i, $e := .Components}}col{{$e.Index}} := unsafe.Slice((*{{$e.TypeName}})(a.compPointers[f.ids[{{$i}}]]), n)
+		{{end}}for k := range n {
+			fn({{range $i, $e := .Components}}{{if $i}}, {{end}}&col{{$e.Index}}[k]{{end}})
+		}
+	}
+}
+
+// EachMut calls fn with pointers to the components {{.TypeVars}} of every
+// matching entity, like Apply, and removes the entities for which fn returns
+// ActionRemove. Removals are collected and applied in one batch after the
+// loop, so the iteration never observes them; this folds the common "iterate,
+// conditionally delete" pattern into a single safe call.
+//
+// fn must not perform structural changes itself.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter{{.N}}[{{.TypeVars}}]) EachMut(fn func(Entity, {{.ReturnTypes}}) Action) {
+	f.checkWorld()
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	var removed []Entity
+	func() {
+		if debugChecks {
+			f.world.mu.beginIteration()
+			defer f.world.mu.endIteration()
+		}
+		for _, a := range arches {
+			n := a.size
+			if n == 0 {
+				continue
+			}
+			{{range $i, $e := .Components}}col{{$e.Index}} := unsafe.Slice((*{{$e.TypeName}})(a.compPointers[f.ids[{{$i}}]]), n)
+			{{end}}for k, e := range a.entityIDs[:n] {
+				if fn(e, {{range $i, $e := .Components}}{{if $i}}, {{end}}&col{{$e.Index}}[k]{{end}}) == ActionRemove {
+					removed = append(removed, e)
+				}
+			}
+		}
+	}()
+	if len(removed) > 0 {
+		f.world.RemoveEntities(removed)
+	}
+}
+
+// EachWithResource{{.N}} calls fn for every entity matched by f, like Apply,
+// passing the world's resource `R` along with the entity and its components
+// {{.TypeVars}}. The resource is looked up once per call instead of once per
+// entity, which suits systems parameterized by global data such as gravity
+// or the frame's delta time.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: The function to call for each matching entity.
+//
+// It panics if the world has no resource of type `R` (added as a *R).
+func EachWithResource{{.N}}[R any, {{.Types}}](f *Filter{{.N}}[{{.TypeVars}}], fn func(*R, Entity, {{.ReturnTypes}})) {
+	f.checkWorld()
+	res, _ := GetResource[R](f.world.Resources())
+	if res == nil {
+		panic("ecs: EachWithResource{{.N}} requires a resource of type *" + reflect.TypeFor[R]().String())
+	}
+	f.world.mu.RLock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	arches := f.matchingArches
+	f.world.mu.RUnlock()
+	if debugChecks {
+		f.world.mu.beginIteration()
+		defer f.world.mu.endIteration()
+	}
+	for _, a := range arches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		{{range $i, $e := .Components}}col{{$e.Index}} := unsafe.Slice((*{{$e.TypeName}})(a.compPointers[f.ids[{{$i}}]]), n)
+		{{end}}for k, e := range a.entityIDs[:n] {
+			fn(res, e, {{range $i, $e := .Components}}{{if $i}}, {{end}}&col{{$e.Index}}[k]{{end}})
+		}
+	}
+}
+
+// Page{{.N}} is a page of the entities matched by a Filter{{.N}}, created by
+// Filter{{.N}}.Page. It records the archetype row ranges covering the page, so
+// it can be iterated, and re-iterated after Reset, independently of the filter.
+// A page is tied to the world's mutation version at the time it was created:
+// any structural change invalidates it, Valid reports whether it can still be
+// used, and Next panics on a stale page.
+type Page{{.N}}[{{.Types}}] struct {
+	pageCursor
+	ids [{{.N}}]uint8
+}
+
+// Page returns the entities at positions [offset, offset+limit) of the
+// filter's iteration order, resolved against the current state of the world.
+// Combined with Count, it lets UI lists and debug inspectors show the
+// entities one page at a time without walking the skipped ones entity by
+// entity. The page is shorter than limit, or empty, past the end of the
+// filter.
+//
+// Parameters:
+//   - offset: The position of the first entity of the page.
+//   - limit: The maximum number of entities in the page.
+//
+// Returns:
+//   - The page, ready to be iterated with Next.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Page(offset, limit int) Page{{.N}}[{{.TypeVars}}] {
+	return Page{{.N}}[{{.TypeVars}}]{pageCursor: f.newPageCursor(offset, limit), ids: f.ids}
+}
+
+// Get returns pointers to {{.TypeVars}} for the current entity of the page.
+func (p *Page{{.N}}[{{.TypeVars}}]) Get() ({{.ReturnTypes}}) {
+	a, i := p.row()
+	return {{range $i, $e := .Components}}{{if $i}},
+		{{end}}(*{{$e.TypeName}})(unsafe.Add(a.compPointers[p.ids[{{$i}}]], i*a.compSizes[p.ids[{{$i}}]])){{end}}
+}
+
+// Map{{.N}} calls fn with pointers to the components {{.TypeVars}} of every
+// entity matched by f and collects the results, in iteration order, e.g. to
+// extract every enemy position for a minimap. The result is preallocated with
+// the filter's count. It allocates on every call, so it suits tooling and
+// one-off computations better than per-frame systems.
+//
+// fn must not perform structural changes.
+//
+// Parameters:
+//   - f: The filter to iterate.
+//   - fn: Computes the value for one entity.
+//
+// Returns:
+//   - One result per matching entity.
+func Map{{.N}}[{{.Types}}, R any](f *Filter{{.N}}[{{.TypeVars}}], fn func({{.ReturnTypes}}) R) []R {
+	res := make([]R, 0, f.Count())
+	f.Apply(func({{range $i, $e := .Components}}{{if $i}}, {{end}}c{{$e.Index}} *{{$e.TypeName}}{{end}}) {
+		res = append(res, fn({{range $i, $e := .Components}}{{if $i}}, {{end}}c{{$e.Index}}{{end}}))
+	})
+	return res
+}
+
+// SetAll overwrites the components {{.TypeVars}} of every matching entity with
+// the given values. The columns are written directly, which is much faster
+// than calling SetComponent{{.N}} per entity.
+//
+// SetAll only writes values, so it is not a structural change and does not
+// bump the world's mutation version; it marks the components as changed when
+// they are tracked (see TrackChanges).
+//
+// Parameters:
+{{range .Components}}//   - {{.VarName}}: The value to store in every matching entity's {{.TypeName}}.
+{{end}}func (f *Filter{{.N}}[{{.TypeVars}}]) SetAll({{.Vars}}) {
+	f.checkWorld()
+	w := f.world
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f.isArchetypeStale() {
+		f.updateMatching()
+	}
+	for _, a := range f.matchingArches {
+		n := a.size
+		if n == 0 {
+			continue
+		}
+		{{range $i, $e := .Components}}col{{$e.Index}} := unsafe.Slice((*{{$e.TypeName}})(a.compPointers[f.ids[{{$i}}]]), n)
+		for k := range col{{$e.Index}} {
+			col{{$e.Index}}[k] = {{$e.VarName}}
+		}
+		{{end}}for _, id := range f.ids {
+			if w.changes[id] != nil || w.versions[id] != nil {
+				for _, e := range a.entityIDs[:n] {
+					w.markChanged(id, e.ID)
+				}
+			}
+		}
+	}
+}
+
+// EachSafe calls fn for every matching entity, like Apply, but iterates over
+// a snapshot of the matching entities taken up front. fn may therefore make
+// structural changes, including removing the entity it is visiting or others
+// the filter matches. Entities that died or lost any of {{.TypeVars}} before
+// being visited are skipped. The pointers passed to fn are only valid until fn
+// makes a structural change.
+//
+// Parameters:
+//   - fn: The function to call for each matching entity.
+func (f *Filter{{.N}}[{{.TypeVars}}]) EachSafe(fn func(Entity, {{.ReturnTypes}})) {
+	ents := append(f.snapshot[:0], f.Entities()...)
+	f.snapshot = nil // a nested EachSafe gets its own buffer
+	w := f.world
+	for _, e := range ents {
+		w.mu.RLock()
+		if !w.IsValidNoLock(e) {
+			w.mu.RUnlock()
+			continue
+		}
+		meta := w.entities.metas[e.ID]
+		a := w.archetypes.archetypes[meta.archetypeIndex]
+		if {{range $i, $e := .Components}}{{if $i}} || {{end}}!a.mask.has(f.ids[{{$i}}]){{end}} {
+			w.mu.RUnlock()
+			continue
+		}
+		{{range $i, $e := .Components}}c{{$e.Index}} := (*{{$e.TypeName}})(unsafe.Add(a.compPointers[f.ids[{{$i}}]], uintptr(meta.index)*f.compSizes[{{$i}}]))
+		{{end}}w.mu.RUnlock()
+		fn(e, {{range $i, $e := .Components}}{{if $i}}, {{end}}c{{$e.Index}}{{end}})
+	}
+	f.snapshot = ents[:0]
+}
+
+// Versions returns the write counters of the current entity's components
+// {{.TypeVars}}, in that order, so a system can skip entities whose components
+// have not been written since it last processed them. Components whose
+// versions are not tracked (see TrackVersions) report 0. This should only be
+// called after `Next()` has returned true.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Versions() ({{range $i, $e := .Components}}{{if $i}}, {{end}}v{{$e.Index}}{{end}} uint32) {
+	id := f.curEntityIDs[f.curIdx].ID
+	return {{range $i, $e := .Components}}{{if $i}}, {{end}}f.world.componentVersion(f.ids[{{$i}}], id){{end}}
+}
+
+// MarkChanged flags the current entity's tracked components among
+// {{.TypeVars}} as changed, for systems that modified them in place through
+// Get. Components whose changes are not tracked (see TrackChanges) are
+// ignored. This should only be called after `Next()` has returned true.
+func (f *Filter{{.N}}[{{.TypeVars}}]) MarkChanged() {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		f.world.markChanged(cid, id)
+	}
+}
+
+// Changed reports whether any tracked component among {{.TypeVars}} of the
+// current entity changed since the last World.ClearChanged.
+//
+// Returns:
+//   - true if a component is flagged as changed, false otherwise.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Changed() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isChanged(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChanged advances the filter to the next matching entity for which
+// Changed is true, skipping the others. It is used in place of Next by
+// reactive systems.
+//
+// Returns:
+//   - true if another changed entity was found, false otherwise.
+func (f *Filter{{.N}}[{{.TypeVars}}]) NextChanged() bool {
+	for f.Next() {
+		if f.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Added reports whether any tracked component among {{.TypeVars}} was added
+// to the current entity during the previous frame, as delimited by
+// World.BeginFrame.
+//
+// Returns:
+//   - true if a component was recently added, false otherwise.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Added() bool {
+	id := f.curEntityIDs[f.curIdx].ID
+	for _, cid := range f.ids {
+		if f.world.isAdded(cid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAdded advances the filter to the next matching entity for which Added
+// is true, skipping the others. It lets a system run one-time initialization
+// for newly spawned entities without a marker component.
+//
+// Returns:
+//   - true if another recently added entity was found, false otherwise.
+func (f *Filter{{.N}}[{{.TypeVars}}]) NextAdded() bool {
+	for f.Next() {
+		if f.Added() {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveEntities efficiently removes all entities that match the filter's
 // query. This operation is performed in a batch, invalidating all matching
 // entities and recycling their IDs without moving any memory.
 func (f *Filter{{.N}}[{{.TypeVars}}]) RemoveEntities() {
+	f.checkWorld()
 	f.world.mu.Lock()
 	defer f.world.mu.Unlock()
 	if f.IsStale() {
@@ -138,18 +581,40 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) RemoveEntities() {
 	for _, a := range f.matchingArches {
 		for i := 0; i < a.size; i++ {
 			ent := a.entityIDs[i]
-			meta := &f.world.entities.metas[ent.ID]
-			meta.archetypeIndex = -1
-			meta.index = -1
-			meta.version = 0
-			f.world.entities.freeIDs = append(f.world.entities.freeIDs, ent.ID)
+			f.world.releaseEntityNoLock(ent.ID)
 		}
+		a.clearDeadSlots(0, a.size)
 		a.size = 0
 	}
+	f.world.removeOwnedNoLock()
 	f.world.mutationVersion.Add(1)
 	f.doReset()
 }
 
+// Encode streams every matching entity through codec to w, passing the
+// entity and pointers to its {{.TypeVars}} components, e.g. to dump the
+// world's state while debugging or to write a simple save file. It resets the
+// filter and iterates it to the end, stopping at the first error.
+//
+// Parameters:
+//   - w: The destination stream.
+//   - codec: The format to write, such as JSONCodec.
+//
+// Returns:
+//   - The first error returned by codec, or nil.
+func (f *Filter{{.N}}[{{.TypeVars}}]) Encode(w io.Writer, codec Codec) error {
+	comps := make([]any, {{.N}})
+	f.Reset()
+	for f.Next() {
+		{{range $i, $e := .Components}}{{if $i}}, {{end}}p{{$e.Index}}{{end}} := f.Get()
+		{{range $i, $e := .Components}}comps[{{$i}}] = p{{$e.Index}}
+		{{end}}if err := codec.Encode(w, f.Entity(), comps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Entities returns all entities that match the filter.
 func (f *Filter{{.N}}[{{.TypeVars}}]) Entities() []Entity {
 	return f.queryCache.Entities()
@@ -162,6 +627,7 @@ type Query{{.N}}[{{.Types}}] struct {
 	curEntityIDs   []Entity
 	curMatchIdx    int
 	curIdx         int
+	curOffset      int
 	compSizes      [{{.N}}]uintptr
 	curArchSize    int
 	ids            [{{.N}}]uint8
@@ -169,6 +635,7 @@ type Query{{.N}}[{{.Types}}] struct {
 
 // Query returns a new Query{{.N}} iterator from the Filter{{.N}}.
 func (f *Filter{{.N}}[{{.TypeVars}}]) Query() Query{{.N}}[{{.TypeVars}}] {
+	f.checkWorld()
 	f.world.mu.RLock()
 	defer f.world.mu.RUnlock()
 	if f.isArchetypeStale() {
@@ -183,7 +650,7 @@ func (f *Filter{{.N}}[{{.TypeVars}}]) Query() Query{{.N}}[{{.TypeVars}}] {
 	}
 	if len(q.matchingArches) > 0 {
 		a := q.matchingArches[0]
-		{{range $i, $e := .Components}}f.curBases[{{$i}}] = a.compPointers[f.ids[{{$i}}]]
+		{{range $i, $e := .Components}}q.curBases[{{$i}}] = a.compPointers[q.ids[{{$i}}]]
 		{{end}}
 		q.curEntityIDs = a.entityIDs
 		q.curArchSize = a.size
@@ -205,7 +672,14 @@ func (q *Query{{.N}}[{{.TypeVars}}]) Next() bool {
 // nextArchetype advances to the next archetype in the query.
 // This is separated from Next to allow Next to be inlined.
 func (q *Query{{.N}}[{{.TypeVars}}]) nextArchetype() bool {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return false // already exhausted
+	}
+	q.curOffset += q.curArchSize
 	q.curMatchIdx++
+	for q.curMatchIdx < len(q.matchingArches) && q.matchingArches[q.curMatchIdx].size == 0 {
+		q.curMatchIdx++ // skip archetypes that are currently empty
+	}
 	if q.curMatchIdx >= len(q.matchingArches) {
 		return false
 	}
@@ -220,13 +694,85 @@ func (q *Query{{.N}}[{{.TypeVars}}]) nextArchetype() bool {
 	return true
 }
 
+// Remaining returns the number of matching entities the query has not yielded
+// yet.
+func (q *Query{{.N}}[{{.TypeVars}}]) Remaining() int {
+	if q.curMatchIdx >= len(q.matchingArches) {
+		return 0
+	}
+	n := q.curArchSize - q.curIdx - 1
+	for i := q.curMatchIdx + 1; i < len(q.matchingArches); i++ {
+		n += q.matchingArches[i].size
+	}
+	return n
+}
+
+// Skip advances the query past the next n entities without yielding them,
+// crossing archetype boundaries as needed. Skipping more entities than
+// Remaining exhausts the query.
+func (q *Query{{.N}}[{{.TypeVars}}]) Skip(n int) {
+	for n > 0 {
+		left := q.curArchSize - q.curIdx - 1
+		if n <= left {
+			q.curIdx += n
+			return
+		}
+		n -= left
+		q.curIdx = q.curArchSize
+		if !q.nextArchetype() {
+			return
+		}
+		n-- // nextArchetype positions the query on the archetype's first entity
+	}
+}
+
+// Cursor returns the query's current position as (archetype index, element
+// index). See Query.Cursor.
+func (q *Query{{.N}}[{{.TypeVars}}]) Cursor() (archIdx, elemIdx int) {
+	return q.curMatchIdx, q.curIdx
+}
+
+// SeekTo moves the query to a position returned by Cursor, so that Entity
+// and Get refer to it and Next continues after it. See Query.SeekTo.
+func (q *Query{{.N}}[{{.TypeVars}}]) SeekTo(archIdx, elemIdx int) {
+	archIdx = max(archIdx, 0)
+	q.curOffset = 0
+	for i := 0; i < archIdx && i < len(q.matchingArches); i++ {
+		q.curOffset += q.matchingArches[i].size
+	}
+	q.curMatchIdx = archIdx
+	if archIdx >= len(q.matchingArches) {
+		q.curArchSize = 0
+		q.curIdx = -1
+		return
+	}
+	a := q.matchingArches[archIdx]
+	{{range $i, $e := .Components}}q.curBases[{{$i}}] = a.compPointers[q.ids[{{$i}}]]
+	{{end -}}
+	q.curEntityIDs = a.entityIDs
+	q.curArchSize = a.size
+	q.curIdx = min(max(elemIdx, -1), a.size-1)
+}
+
+// Index returns the 0-based position of the current entity in the whole
+// iteration, counting across archetype boundaries.
+func (q *Query{{.N}}[{{.TypeVars}}]) Index() int {
+	return q.curOffset + q.curIdx
+}
+
 // Entity returns the current entity in the query.
 func (q *Query{{.N}}[{{.TypeVars}}]) Entity() Entity {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return q.curEntityIDs[q.curIdx]
 }
 
 // Get returns pointers to {{.TypeVars}} for the current entity.
 func (q *Query{{.N}}[{{.TypeVars}}]) Get() ({{.ReturnTypes}}) {
+	if debugChecks {
+		checkCursor(q.curIdx, q.curArchSize)
+	}
 	return {{range $i, $e := .Components}}{{if $i}},
 		{{end}}(*{{$e.TypeName}})(unsafe.Add(q.curBases[{{$i}}], uintptr(q.curIdx)*q.compSizes[{{$i}}])){{end}}
 }
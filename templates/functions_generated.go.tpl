@@ -17,14 +17,15 @@ func GetComponent{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
 		return {{.ReturnNil}}
 	}
 	meta := w.entities.metas[e.ID]
-	w.components.mu.RLock()
-	{{range .Components}}id{{.Index}} := w.getCompTypeIDNoLock(reflect.TypeFor[{{.TypeName}}]())
+	{{range .Components}}id{{.Index}} := w.getCompTypeID(reflect.TypeFor[{{.TypeName}}]())
 	{{end}}
-	w.components.mu.RUnlock()
 
 	if {{.DuplicateIDs}} {
 		panic("ecs: duplicate component types in GetComponent{{.N}}")
 	}
+	{{range .Components}}w.countRead(id{{.Index}})
+	{{end}}w.touchEntity(e.ID)
+
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	{{range .Components}}i{{.Index}} := id{{.Index}} >> 6
 	o{{.Index}} := id{{.Index}} & 63
@@ -56,14 +57,16 @@ func GetComponent{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
 	meta := &w.entities.metas[e.ID]
 	{{range .Components}}t{{.Index}} := reflect.TypeFor[{{.TypeName}}]()
 	{{end}}
-	w.components.mu.RLock()
-	{{range .Components}}id{{.Index}} := w.getCompTypeIDNoLock(t{{.Index}})
+	{{range .Components}}id{{.Index}} := w.getCompTypeID(t{{.Index}})
 	{{end}}
-	w.components.mu.RUnlock()
 
 	if {{.DuplicateIDs}} {
 		panic("ecs: duplicate component types in SetComponent{{.N}}")
 	}
+	{{range .Components}}w.countWrite(id{{.Index}})
+	{{end}}w.touchEntity(e.ID)
+	{{range .Components}}w.markChanged(id{{.Index}}, e.ID)
+	{{end}}
 	a := w.archetypes.archetypes[meta.archetypeIndex]
 	{{range .Components}}i{{.Index}} := id{{.Index}} >> 6
 	o{{.Index}} := id{{.Index}} & 63
@@ -82,8 +85,8 @@ func GetComponent{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
 	}
 	{{end}}
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -101,6 +104,7 @@ func GetComponent{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -115,6 +119,10 @@ func GetComponent{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
 	w.removeFromArchetype(a, meta)
 	meta.archetypeIndex = targetA.index
 	meta.index = newIdx
+	{{range .Components}}if !has{{.Index}} {
+		w.markAdded(id{{.Index}}, e.ID)
+	}
+	{{end}}
 	w.mutationVersion.Add(1)
 }
 
@@ -137,10 +145,8 @@ func RemoveComponent{{.N}}[{{.Types}}](w *World, e Entity) {
 	meta := &w.entities.metas[e.ID]
 	{{range .Components}}t{{.Index}} := reflect.TypeFor[{{.TypeName}}]()
 	{{end}}
-	w.components.mu.RLock()
-	{{range .Components}}id{{.Index}} := w.getCompTypeIDNoLock(t{{.Index}})
+	{{range .Components}}id{{.Index}} := w.getCompTypeID(t{{.Index}})
 	{{end}}
-	w.components.mu.RUnlock()
 
 	if {{.DuplicateIDs}} {
 		panic("ecs: duplicate component types in RemoveComponent{{.N}}")
@@ -158,8 +164,8 @@ func RemoveComponent{{.N}}[{{.Types}}](w *World, e Entity) {
 	{{range .Components}}newMask.unset(id{{.Index}})
 	{{end}}
 	var targetA *archetype
-	if idx, ok := w.archetypes.maskToArcIndex[newMask]; ok {
-		targetA = w.archetypes.archetypes[idx]
+	if t := w.transitionNoLock(a, newMask); t != nil {
+		targetA = t
 	} else {
 		var tempSpecs [MaxComponentTypes]compSpec
 		count := 0
@@ -175,6 +181,7 @@ func RemoveComponent{{.N}}[{{.Types}}](w *World, e Entity) {
 		specs := tempSpecs[:count]
 		targetA = w.getOrCreateArchetypeNoLock(newMask, specs)
 	}
+	w.ensureStorage(targetA)
 	newIdx := targetA.size
 	targetA.entityIDs[newIdx] = e
 	targetA.size++
@@ -191,3 +198,30 @@ func RemoveComponent{{.N}}[{{.Types}}](w *World, e Entity) {
 	meta.index = newIdx
 	w.mutationVersion.Add(1)
 }
+
+// Prewarm{{.N}} creates the archetype holding exactly the {{.N}} components
+// ({{.TypeVars}}), without creating any entity. See World.PrewarmArchetype.
+//
+// Parameters:
+//   - w: The World in which to create the archetype.
+func Prewarm{{.N}}[{{.Types}}](w *World) {
+	w.PrewarmArchetype({{range $i, $e := .Components}}{{if $i}}, {{end}}reflect.TypeFor[{{$e.TypeName}}](){{end}})
+}
+
+// Get{{.N}} is a shorter name for GetComponent{{.N}}, aligned with the naming of
+// Filter{{.N}} and Builder{{.N}}.
+func Get{{.N}}[{{.Types}}](w *World, e Entity) ({{.ReturnTypes}}) {
+	return GetComponent{{.N}}[{{.TypeVars}}](w, e)
+}
+
+// Set{{.N}} is a shorter name for SetComponent{{.N}}, aligned with the naming of
+// Filter{{.N}} and Builder{{.N}}.
+func Set{{.N}}[{{.Types}}](w *World, e Entity, {{.Vars}}) {
+	SetComponent{{.N}}(w, e, {{range $i, $e := .Components}}{{if $i}}, {{end}}{{$e.VarName}}{{end}})
+}
+
+// Remove{{.N}} is a shorter name for RemoveComponent{{.N}}, aligned with the
+// naming of Filter{{.N}} and Builder{{.N}}.
+func Remove{{.N}}[{{.Types}}](w *World, e Entity) {
+	RemoveComponent{{.N}}[{{.TypeVars}}](w, e)
+}
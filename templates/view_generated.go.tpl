@@ -0,0 +1,75 @@
+// View{{.N}} bundles a Builder{{.N}} and a Filter{{.N}} for the component types
+// {{.TypeVars}} behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View{{.N}}[{{.Types}}] struct {
+	builder *Builder{{.N}}[{{.TypeVars}}]
+	filter  *Filter{{.N}}[{{.TypeVars}}]
+}
+
+// NewView{{.N}} creates a new `View{{.N}}` for entities with the components
+// {{.TypeVars}}.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View{{.N}}`.
+func NewView{{.N}}[{{.Types}}](w *World) *View{{.N}}[{{.TypeVars}}] {
+	return &View{{.N}}[{{.TypeVars}}]{
+		builder: NewBuilder{{.N}}[{{.TypeVars}}](w),
+		filter:  NewFilter{{.N}}[{{.TypeVars}}](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components {{.TypeVars}}.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View{{.N}}[{{.TypeVars}}]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View{{.N}}[{{.TypeVars}}]) SpawnWith({{.Vars}}) Entity {
+	return v.builder.NewEntityWith({{range $i, $e := .Components}}{{if $i}}, {{end}}{{$e.VarName}}{{end}})
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View{{.N}}[{{.TypeVars}}]) Get(e Entity) ({{.ReturnTypes}}) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View{{.N}}[{{.TypeVars}}]) Set(e Entity, {{.Vars}}) {
+	v.builder.Set(e, {{range $i, $e := .Components}}{{if $i}}, {{end}}{{$e.VarName}}{{end}})
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View{{.N}}[{{.TypeVars}}]) Each(fn func(Entity, {{.ReturnTypes}})) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		{{range $i, $e := .Components}}{{if $i}}, {{end}}c{{$e.Index}}{{end}} := f.Get()
+		fn(f.Entity(), {{range $i, $e := .Components}}{{if $i}}, {{end}}c{{$e.Index}}{{end}})
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View{{.N}}[{{.TypeVars}}]) Filter() *Filter{{.N}}[{{.TypeVars}}] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View{{.N}}[{{.TypeVars}}]) Builder() *Builder{{.N}}[{{.TypeVars}}] {
+	return v.builder
+}
@@ -0,0 +1,274 @@
+package teishoku
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+)
+
+// transientStore holds the values of one transient component type, keyed by
+// entity instead of living in archetype columns. Entries of removed entities
+// are not dropped eagerly: every slot remembers the handle that set it, and a
+// slot whose handle is no longer alive is treated as absent and reused.
+type transientStore[T any] struct {
+	index  map[uint32]int32 // entity ID -> slot
+	owners []Entity
+	values []T
+}
+
+// slot returns the slot holding e's value, or -1 if it has none.
+func (s *transientStore[T]) slot(w *World, e Entity) int32 {
+	i, ok := s.index[e.ID]
+	if !ok || s.owners[i] != e || !w.IsValidNoLock(e) {
+		return -1
+	}
+	return i
+}
+
+// RegisterTransient marks the component type `T` as transient in the world.
+// Transient components are stored in a side table keyed by entity rather than
+// in the archetype's columns, and are not part of the archetype mask: adding
+// or removing one never moves the entity to another archetype. This avoids
+// archetype churn for frame-scoped flags and markers (Hit, Selected,
+// JustSpawned, ...) that are set and cleared every frame, at the cost of a map
+// lookup per access and of cache locality.
+//
+// Once registered, SetComponent, GetComponent, GetOrAdd, RemoveComponent,
+// RemoveAndGet and their short forms read and write the side table. The
+// numbered setters, builders and filters work on archetype columns and panic
+// when given a transient type: use JoinTransient to iterate a filter's
+// entities together with their transient component. Registering a type twice
+// is a no-op.
+//
+// Parameters:
+//   - w: The World in which to register the component.
+//
+// It panics if an entity already stores `T` in an archetype.
+func RegisterTransient[T any](w *World) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.getCompTypeID(reflect.TypeFor[T]())
+	if w.transientMask.has(id) {
+		return
+	}
+	for _, a := range w.archetypes.archetypes {
+		if a.mask.has(id) {
+			panic(fmt.Sprintf("ecs: RegisterTransient[%v] called after the component was stored in an archetype", reflect.TypeFor[T]()))
+		}
+	}
+	w.transients[id] = &transientStore[T]{index: make(map[uint32]int32)}
+	w.transientMask.set(id)
+}
+
+// transientInMaskNoLock returns the first transient component type in m, or
+// nil if m holds none.
+func (w *World) transientInMaskNoLock(m bitmask256) reflect.Type {
+	if !m.intersects(w.transientMask) {
+		return nil
+	}
+	var t bitmask256
+	for i := range m {
+		t[i] = m[i] & w.transientMask[i]
+	}
+	return w.maskTypesNoLock(t)[0]
+}
+
+// IsTransient reports whether the component type `T` was registered with
+// RegisterTransient.
+//
+// Parameters:
+//   - w: The World to query.
+//
+// Returns:
+//   - true if `T` is stored in a side table, false otherwise.
+func IsTransient[T any](w *World) bool {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return false
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.transientMask.has(id)
+}
+
+// ClearTransient removes the transient component `T` from every entity, e.g.
+// at the end of a frame for per-frame flags. It does nothing if `T` is not
+// transient.
+//
+// Parameters:
+//   - w: The World to modify.
+func ClearTransient[T any](w *World) {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.transients[id].(*transientStore[T]); ok {
+		clear(s.index)
+		clear(s.values)
+		s.owners = s.owners[:0]
+		s.values = s.values[:0]
+	}
+}
+
+// transientSlot is implemented by every transientStore, for the checks that do
+// not know the component's Go type.
+type transientSlot interface {
+	slot(w *World, e Entity) int32
+}
+
+// hasTransientsNoLock reports whether the live entity e holds every transient
+// component in mask.
+func (w *World) hasTransientsNoLock(e Entity, mask bitmask256) bool {
+	for i, word := range mask {
+		for word != 0 {
+			id := i*64 + bits.TrailingZeros64(word)
+			if w.transients[id].(transientSlot).slot(w, e) < 0 {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+// getTransientNoLock returns e's value of the transient component id, or nil.
+func getTransientNoLock[T any](w *World, id uint8, e Entity) *T {
+	s := w.transients[id].(*transientStore[T])
+	i := s.slot(w, e)
+	if i < 0 {
+		return nil
+	}
+	return &s.values[i]
+}
+
+// setTransientNoLock stores val as e's transient component id and returns a
+// pointer to it. e must be alive.
+func setTransientNoLock[T any](w *World, id uint8, e Entity, val T) *T {
+	s := w.transients[id].(*transientStore[T])
+	i, ok := s.index[e.ID]
+	if !ok {
+		i = int32(len(s.owners))
+		s.owners = append(s.owners, e)
+		s.values = append(s.values, val)
+		s.index[e.ID] = i
+		return &s.values[i]
+	}
+	s.owners[i] = e // reclaims a slot left by a removed entity
+	s.values[i] = val
+	return &s.values[i]
+}
+
+// removeTransientNoLock deletes e's transient component id. It reports
+// whether the entity had it and returns the removed value.
+func removeTransientNoLock[T any](w *World, id uint8, e Entity) (T, bool) {
+	var val T
+	s := w.transients[id].(*transientStore[T])
+	i := s.slot(w, e)
+	if i < 0 {
+		return val, false
+	}
+	val = s.values[i]
+	last := int32(len(s.owners) - 1)
+	if i != last {
+		s.owners[i] = s.owners[last]
+		s.values[i] = s.values[last]
+		s.index[s.owners[i].ID] = i
+	}
+	var zero T
+	s.values[last] = zero
+	s.owners = s.owners[:last]
+	s.values = s.values[:last]
+	delete(s.index, e.ID)
+	return val, true
+}
+
+// TransientJoin iterates the entities matched by a filter that also hold a
+// transient component `T`, created with JoinTransient. It yields the entity
+// and a pointer to its transient value; the filter's own components are
+// reached through the filter's types or the generic accessors.
+type TransientJoin[T any] struct {
+	world  *World
+	filter *queryCache
+	store  *transientStore[T]
+	rows   []int32
+	cur    int
+}
+
+// JoinTransient returns an iterator over the entities matched by f that hold
+// the transient component `T`. The join is computed on Reset, by walking the
+// side table and keeping the entities whose archetype the filter matches, so
+// its cost is proportional to the number of transient values rather than to
+// the number of matched entities.
+//
+// Parameters:
+//   - f: The filter selecting the entities by their archetype components.
+//
+// Returns:
+//   - A TransientJoin, already reset.
+//
+// It panics if `T` was not registered with RegisterTransient.
+func JoinTransient[T any](f AnyFilter) *TransientJoin[T] {
+	c := f.cache()
+	c.checkWorld()
+	w := c.world
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	w.mu.RLock()
+	var s *transientStore[T]
+	if ok {
+		s, _ = w.transients[id].(*transientStore[T])
+	}
+	w.mu.RUnlock()
+	if s == nil {
+		panic(fmt.Sprintf("ecs: JoinTransient[%v] requires a transient component", reflect.TypeFor[T]()))
+	}
+	j := &TransientJoin[T]{world: w, filter: c, store: s}
+	j.Reset()
+	return j
+}
+
+// Reset rewinds the iterator and recomputes the join against the current
+// state of the world. It must be called before re-iterating.
+func (j *TransientJoin[T]) Reset() {
+	w := j.world
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	j.rows = j.rows[:0]
+	for i, e := range j.store.owners {
+		if !w.IsValidNoLock(e) {
+			continue
+		}
+		a := w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex]
+		if j.filter.matches(a) {
+			j.rows = append(j.rows, int32(i))
+		}
+	}
+	j.cur = -1
+}
+
+// Next advances to the next joined entity.
+//
+// Returns:
+//   - true if another entity was found, false if the iteration is complete.
+func (j *TransientJoin[T]) Next() bool {
+	j.cur++
+	return j.cur < len(j.rows)
+}
+
+// Entity returns the current entity. This should only be called after Next
+// has returned true.
+func (j *TransientJoin[T]) Entity() Entity {
+	return j.store.owners[j.rows[j.cur]]
+}
+
+// Get returns a pointer to the current entity's transient component. The
+// pointer is valid until `T` is next set on or removed from any entity.
+func (j *TransientJoin[T]) Get() *T {
+	return &j.store.values[j.rows[j.cur]]
+}
+
+// Len returns the number of entities in the join.
+func (j *TransientJoin[T]) Len() int {
+	return len(j.rows)
+}
@@ -0,0 +1,108 @@
+package teishoku
+
+import "testing"
+
+type hitFlag struct{ Damage int }
+
+func TestTransientComponents(t *testing.T) {
+	w := NewWorld(8)
+	RegisterTransient[hitFlag](w)
+	RegisterTransient[hitFlag](w) // no-op
+	if !IsTransient[hitFlag](w) || IsTransient[Position](w) {
+		t.Fatal("unexpected IsTransient result")
+	}
+	e1 := w.CreateEntity()
+	SetComponent(w, e1, Position{X: 1})
+	e2 := w.CreateEntity()
+	SetComponent(w, e2, Position{X: 2})
+	e3 := w.CreateEntity() // no Position
+
+	arches := len(w.archetypes.archetypes)
+	version := w.mutationVersion.Load()
+	SetComponent(w, e1, hitFlag{Damage: 5})
+	SetComponent(w, e3, hitFlag{Damage: 7})
+	*GetOrAdd(w, e2, hitFlag{Damage: 1}) = hitFlag{Damage: 2}
+	if len(w.archetypes.archetypes) != arches || w.mutationVersion.Load() != version {
+		t.Fatal("transient components must not create archetypes or move entities")
+	}
+	if h := GetComponent[hitFlag](w, e1); h == nil || h.Damage != 5 {
+		t.Fatalf("expected e1 to be hit for 5, got %v", h)
+	}
+	if GetComponent[hitFlag](w, e2).Damage != 2 {
+		t.Fatal("GetOrAdd should return the stored value")
+	}
+
+	f := NewFilter[Position](w)
+	j := JoinTransient[hitFlag](f)
+	got := map[Entity]int{}
+	for j.Next() {
+		got[j.Entity()] = j.Get().Damage
+	}
+	if len(got) != 2 || got[e1] != 5 || got[e2] != 2 {
+		t.Fatalf("unexpected join %v", got)
+	}
+
+	if v, ok := RemoveAndGet[hitFlag](w, e1); !ok || v.Damage != 5 {
+		t.Fatalf("RemoveAndGet = %v, %v", v, ok)
+	}
+	if GetComponent[hitFlag](w, e1) != nil {
+		t.Fatal("component should be removed")
+	}
+	if GetComponent[hitFlag](w, e2).Damage != 2 || GetComponent[hitFlag](w, e3).Damage != 7 {
+		t.Fatal("removal must not disturb other entities")
+	}
+
+	// A removed entity's value is not inherited by the entity reusing its ID.
+	w.RemoveEntity(e3)
+	e4 := w.CreateEntity()
+	if e4.ID == e3.ID && GetComponent[hitFlag](w, e4) != nil {
+		t.Fatal("recycled ID must not see the previous entity's value")
+	}
+	SetComponent(w, e4, hitFlag{Damage: 9})
+	if GetComponent[hitFlag](w, e4).Damage != 9 {
+		t.Fatal("expected the new entity's value")
+	}
+
+	ClearTransient[hitFlag](w)
+	j.Reset()
+	if j.Len() != 0 || GetComponent[hitFlag](w, e2) != nil {
+		t.Fatal("ClearTransient should drop every value")
+	}
+}
+
+func TestRegisterTransientAfterUsePanics(t *testing.T) {
+	w := NewWorld(4)
+	SetComponent(w, w.CreateEntity(), hitFlag{})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	RegisterTransient[hitFlag](w)
+}
+
+func TestTransientRejectedByColumnAPIs(t *testing.T) {
+	w := NewWorld(4)
+	RegisterTransient[hitFlag](w)
+	e := w.CreateEntity()
+	cases := map[string]func(){
+		"SetComponent2": func() { SetComponent2(w, e, Position{}, hitFlag{}) },
+		"NewBuilder":    func() { NewBuilder[hitFlag](w) },
+		"NewBuilder2":   func() { NewBuilder2[Position, hitFlag](w) },
+		"NewFilter":     func() { NewFilter[hitFlag](w) },
+		"NewFilter2":    func() { NewFilter2[Position, hitFlag](w) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			fn()
+		})
+	}
+	if len(w.ComponentsOf(e)) != 0 {
+		t.Fatal("the rejected setter must not store anything")
+	}
+}
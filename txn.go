@@ -0,0 +1,113 @@
+package teishoku
+
+// Txn groups several structural changes so they are applied under a single
+// acquisition of the World's lock. It is only valid inside the callback passed
+// to World.Mutate and must not be retained or used after the callback returns.
+//
+// All changes made through a Txn bump the world's mutation version exactly
+// once, when the transaction ends, so filters observe the whole batch as one
+// structural change instead of one change per edit.
+type Txn struct {
+	world   *World
+	changed bool
+}
+
+// Mutate runs fn with a transaction that holds the World's write lock for its
+// entire duration. Use the Txn methods and the Tx-prefixed generic functions
+// (TxSetComponent, TxRemoveComponent, TxGetComponent) to edit the world from
+// inside fn.
+//
+// The callback must not call the regular World API (SetComponent,
+// RemoveEntity, filters' Reset, ...), because those acquire the same lock and
+// would deadlock.
+//
+// Parameters:
+//   - fn: The function performing the batched changes.
+func (w *World) Mutate(fn func(tx *Txn)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx := &Txn{world: w}
+	defer func() {
+		if tx.changed {
+			w.mutationVersion.Add(1)
+		}
+		tx.world = nil
+	}()
+	fn(tx)
+}
+
+// CreateEntity creates a new entity with no components.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (tx *Txn) CreateEntity() Entity {
+	w := tx.world
+	var mask bitmask256
+	a := w.getOrCreateArchetypeNoLock(mask, []compSpec{})
+	e := w.createEntityNoLock(a)
+	if e.Version != 0 {
+		tx.changed = true
+	}
+	return e
+}
+
+// RemoveEntity invalidates the entity and recycles its ID. Invalid entities
+// are ignored.
+//
+// Parameters:
+//   - e: The Entity to remove.
+func (tx *Txn) RemoveEntity(e Entity) {
+	if tx.world.removeEntityNoLock(e) {
+		tx.changed = true
+	}
+}
+
+// IsValid checks if the given entity is currently alive.
+//
+// Parameters:
+//   - e: The Entity to validate.
+//
+// Returns:
+//   - true if the entity is valid, false otherwise.
+func (tx *Txn) IsValid(e Entity) bool {
+	return tx.world.IsValidNoLock(e)
+}
+
+// TxSetComponent is the transactional form of SetComponent. It adds or updates
+// the component of type `T` on the entity.
+//
+// Parameters:
+//   - tx: The active transaction.
+//   - e: The Entity to modify.
+//   - val: The component data of type `T` to set.
+func TxSetComponent[T any](tx *Txn, e Entity, val T) {
+	if setComponentNoLock(tx.world, e, val) {
+		tx.changed = true
+	}
+}
+
+// TxRemoveComponent is the transactional form of RemoveComponent. It removes
+// the component of type `T` from the entity.
+//
+// Parameters:
+//   - tx: The active transaction.
+//   - e: The Entity to modify.
+func TxRemoveComponent[T any](tx *Txn, e Entity) {
+	if removeComponentNoLock[T](tx.world, e) {
+		tx.changed = true
+	}
+}
+
+// TxGetComponent is the transactional form of GetComponent. The returned
+// pointer is only valid until the next structural change in the transaction.
+//
+// Parameters:
+//   - tx: The active transaction.
+//   - e: The Entity from which to retrieve the component.
+//
+// Returns:
+//   - A pointer to the component data (*T), or nil if not found.
+func TxGetComponent[T any](tx *Txn, e Entity) *T {
+	return getComponentNoLock[T](tx.world, e)
+}
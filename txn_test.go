@@ -0,0 +1,52 @@
+package teishoku
+
+import "testing"
+
+func TestMutateBumpsVersionOnce(t *testing.T) {
+	w := NewWorld(TestCap)
+	b := NewBuilder[Position](w)
+	e1 := b.NewEntity()
+	e2 := b.NewEntity()
+	before := w.mutationVersion.Load()
+
+	var created Entity
+	w.Mutate(func(tx *Txn) {
+		TxSetComponent(tx, e1, Velocity{DX: 1})
+		TxRemoveComponent[Position](tx, e1)
+		tx.RemoveEntity(e2)
+		created = tx.CreateEntity()
+		TxSetComponent(tx, created, Health{HP: 10})
+		if hp := TxGetComponent[Health](tx, created); hp == nil || hp.HP != 10 {
+			t.Errorf("expected health 10 inside the transaction, got %v", hp)
+		}
+	})
+
+	if got := w.mutationVersion.Load() - before; got != 1 {
+		t.Errorf("expected mutation version to advance by 1, got %d", got)
+	}
+	if GetComponent[Position](w, e1) != nil || GetComponent[Velocity](w, e1) == nil {
+		t.Error("expected e1 to have swapped position for velocity")
+	}
+	if w.IsValid(e2) {
+		t.Error("expected e2 to be removed")
+	}
+	if hp := GetComponent[Health](w, created); hp == nil || hp.HP != 10 {
+		t.Errorf("expected created entity to keep its health, got %v", hp)
+	}
+}
+
+func TestMutateWithoutChanges(t *testing.T) {
+	w := NewWorld(TestCap)
+	e := NewBuilder[Position](w).NewEntity()
+	before := w.mutationVersion.Load()
+	w.Mutate(func(tx *Txn) {
+		TxSetComponent(tx, e, Position{X: 3})
+		tx.RemoveEntity(Entity{ID: 12345, Version: 1})
+	})
+	if w.mutationVersion.Load() != before {
+		t.Error("expected in-place writes to leave the mutation version alone")
+	}
+	if GetComponent[Position](w, e).X != 3 {
+		t.Error("expected in-place write to be applied")
+	}
+}
@@ -0,0 +1,47 @@
+package teishoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorldValidate(t *testing.T) {
+	w := NewWorld(4)
+	var alive []Entity
+	for i := range 50 {
+		e := w.CreateEntity()
+		alive = append(alive, e)
+		SetComponent(w, e, Position{X: float32(i)})
+		if i%2 == 0 {
+			SetComponent(w, e, Velocity{DX: 1})
+		}
+		if i%3 == 0 {
+			RemoveComponent[Position](w, e)
+		}
+		if i%5 == 0 {
+			w.RemoveEntity(alive[i/2])
+		}
+	}
+	NewFilter[Velocity](w).RemoveEntities()
+	if err := w.Validate(); err != nil {
+		t.Fatalf("expected a consistent world, got %v", err)
+	}
+
+	f := NewFilter[Position](w)
+	f.Reset()
+	if !f.Next() {
+		t.Fatal("expected an entity")
+	}
+	e := f.Entity()
+	w.entities.metas[e.ID].index++ // corrupt the slot mapping
+	w.entities.freeIDs = append(w.entities.freeIDs, e.ID)
+	err := w.Validate()
+	if err == nil {
+		t.Fatal("expected corruption to be reported")
+	}
+	for _, want := range []string{"metadata points to", "belongs to a live entity"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
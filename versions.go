@@ -0,0 +1,85 @@
+package teishoku
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// versionSet holds, for one versioned component type, a write counter per
+// entity ID. It grows with the world like a changeSet.
+type versionSet struct {
+	counts []uint32
+}
+
+// TrackVersions enables write counters for component `T` in the world. Every
+// write that change tracking would flag (SetComponent, SetComponentN, the
+// transactional setters, AddComponentToAll, SetAll and MarkChanged) also
+// increments the entity's counter for `T`. A system can remember the version
+// it last processed per entity and skip the entity on the next frame while
+// the version has not advanced, which is finer-grained than reacting to
+// structural changes.
+//
+// In-place writes through a filter's Get are only counted when followed by
+// MarkChanged. Versions are opt-in per component type because every counted
+// write costs an extra atomic increment. Enabling it again for the same type
+// is a no-op.
+//
+// Parameters:
+//   - w: The World in which to count writes.
+func TrackVersions[T any](w *World) {
+	id := w.getCompTypeID(reflect.TypeFor[T]())
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.versions[id] != nil {
+		return
+	}
+	w.versions[id] = &versionSet{counts: make([]uint32, w.entities.capacity)}
+	w.versionedIDs = append(w.versionedIDs, id)
+}
+
+// ComponentVersion returns the write counter of component `T` for the entity.
+// The counter only ever grows while the entity is alive; compare it with a
+// previously recorded value to detect writes in between.
+//
+// Parameters:
+//   - w: The World containing the entity.
+//   - e: The Entity to inspect.
+//
+// Returns:
+//   - The current version, or 0 if versions of `T` are not tracked or the
+//     entity is invalid.
+func ComponentVersion[T any](w *World, e Entity) uint32 {
+	id, ok := w.lookupCompTypeID(reflect.TypeFor[T]())
+	if !ok {
+		return 0
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return 0
+	}
+	return w.componentVersion(id, e.ID)
+}
+
+// componentVersion returns the write counter of component id for the entity
+// ID, or 0 if the component is not versioned.
+func (w *World) componentVersion(id uint8, entityID uint32) uint32 {
+	vs := w.versions[id]
+	if vs == nil {
+		return 0
+	}
+	return atomic.LoadUint32(&vs.counts[entityID])
+}
+
+// growVersionSets resizes every version set to the world's capacity. It is
+// called with the write lock held whenever the world expands.
+func (w *World) growVersionSets() {
+	for _, id := range w.versionedIDs {
+		vs := w.versions[id]
+		if len(vs.counts) < w.entities.capacity {
+			grown := make([]uint32, w.entities.capacity)
+			copy(grown, vs.counts)
+			vs.counts = grown
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package teishoku
+
+import "testing"
+
+func TestComponentVersions(t *testing.T) {
+	w := NewWorld(2)
+	TrackVersions[Position](w)
+	a := w.CreateEntity()
+	SetComponent2(w, a, Position{}, Velocity{})
+	b := w.CreateEntity()
+	SetComponent2(w, b, Position{}, Velocity{})
+
+	f := NewFilter2[Position, Velocity](w)
+	seen := map[Entity]uint32{}
+	for f.Next() {
+		vp, vv := f.Versions()
+		if vp == 0 || vv != 0 {
+			t.Fatalf("expected a counted Position and an untracked Velocity, got %d %d", vp, vv)
+		}
+		seen[f.Entity()] = vp
+	}
+
+	SetComponent(w, a, Position{X: 1})
+	for range 4 {
+		w.CreateEntity() // grow the world past its initial capacity
+	}
+	f.Reset()
+	var changed []Entity
+	for f.Next() {
+		if v, _ := f.Versions(); v != seen[f.Entity()] {
+			changed = append(changed, f.Entity())
+		}
+	}
+	if len(changed) != 1 || changed[0] != a {
+		t.Fatalf("expected only %v to have advanced, got %v", a, changed)
+	}
+
+	before := ComponentVersion[Position](w, b)
+	MarkChanged[Position](w, b)
+	if got := ComponentVersion[Position](w, b); got != before+1 {
+		t.Errorf("expected MarkChanged to advance the version to %d, got %d", before+1, got)
+	}
+	if got := ComponentVersion[Velocity](w, b); got != 0 {
+		t.Errorf("expected 0 for an untracked component, got %d", got)
+	}
+	single := NewFilter[Position](w)
+	for single.Next() {
+		if single.Entity() == b && single.Version() != before+1 {
+			t.Errorf("Filter.Version: expected %d, got %d", before+1, single.Version())
+		}
+	}
+}
+
+func TestSetAllAdvancesVersions(t *testing.T) {
+	w := NewWorld(4)
+	TrackVersions[Position](w)
+	e := w.CreateEntity()
+	SetComponent2(w, e, Position{}, Velocity{})
+	before := ComponentVersion[Position](w, e)
+	NewFilter[Position](w).SetAll(Position{X: 1})
+	if got := ComponentVersion[Position](w, e); got != before+1 {
+		t.Errorf("Filter.SetAll: expected version %d, got %d", before+1, got)
+	}
+	NewFilter2[Position, Velocity](w).SetAll(Position{X: 2}, Velocity{})
+	if got := ComponentVersion[Position](w, e); got != before+2 {
+		t.Errorf("Filter2.SetAll: expected version %d, got %d", before+2, got)
+	}
+}
@@ -0,0 +1,382 @@
+package teishoku
+
+// View2 bundles a Builder2 and a Filter2 for the component types
+// T1, T2 behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View2[T1 any, T2 any] struct {
+	builder *Builder2[T1, T2]
+	filter  *Filter2[T1, T2]
+}
+
+// NewView2 creates a new `View2` for entities with the components
+// T1, T2.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View2`.
+func NewView2[T1 any, T2 any](w *World) *View2[T1, T2] {
+	return &View2[T1, T2]{
+		builder: NewBuilder2[T1, T2](w),
+		filter:  NewFilter2[T1, T2](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components T1, T2.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View2[T1, T2]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View2[T1, T2]) SpawnWith(v1 T1, v2 T2) Entity {
+	return v.builder.NewEntityWith(v1, v2)
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View2[T1, T2]) Get(e Entity) (*T1, *T2) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View2[T1, T2]) Set(e Entity, v1 T1, v2 T2) {
+	v.builder.Set(e, v1, v2)
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View2[T1, T2]) Each(fn func(Entity, *T1, *T2)) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		c1, c2 := f.Get()
+		fn(f.Entity(), c1, c2)
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View2[T1, T2]) Filter() *Filter2[T1, T2] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View2[T1, T2]) Builder() *Builder2[T1, T2] {
+	return v.builder
+}
+
+// View3 bundles a Builder3 and a Filter3 for the component types
+// T1, T2, T3 behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View3[T1 any, T2 any, T3 any] struct {
+	builder *Builder3[T1, T2, T3]
+	filter  *Filter3[T1, T2, T3]
+}
+
+// NewView3 creates a new `View3` for entities with the components
+// T1, T2, T3.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View3`.
+func NewView3[T1 any, T2 any, T3 any](w *World) *View3[T1, T2, T3] {
+	return &View3[T1, T2, T3]{
+		builder: NewBuilder3[T1, T2, T3](w),
+		filter:  NewFilter3[T1, T2, T3](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components T1, T2, T3.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View3[T1, T2, T3]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View3[T1, T2, T3]) SpawnWith(v1 T1, v2 T2, v3 T3) Entity {
+	return v.builder.NewEntityWith(v1, v2, v3)
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View3[T1, T2, T3]) Get(e Entity) (*T1, *T2, *T3) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View3[T1, T2, T3]) Set(e Entity, v1 T1, v2 T2, v3 T3) {
+	v.builder.Set(e, v1, v2, v3)
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View3[T1, T2, T3]) Each(fn func(Entity, *T1, *T2, *T3)) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		c1, c2, c3 := f.Get()
+		fn(f.Entity(), c1, c2, c3)
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View3[T1, T2, T3]) Filter() *Filter3[T1, T2, T3] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View3[T1, T2, T3]) Builder() *Builder3[T1, T2, T3] {
+	return v.builder
+}
+
+// View4 bundles a Builder4 and a Filter4 for the component types
+// T1, T2, T3, T4 behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View4[T1 any, T2 any, T3 any, T4 any] struct {
+	builder *Builder4[T1, T2, T3, T4]
+	filter  *Filter4[T1, T2, T3, T4]
+}
+
+// NewView4 creates a new `View4` for entities with the components
+// T1, T2, T3, T4.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View4`.
+func NewView4[T1 any, T2 any, T3 any, T4 any](w *World) *View4[T1, T2, T3, T4] {
+	return &View4[T1, T2, T3, T4]{
+		builder: NewBuilder4[T1, T2, T3, T4](w),
+		filter:  NewFilter4[T1, T2, T3, T4](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components T1, T2, T3, T4.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View4[T1, T2, T3, T4]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View4[T1, T2, T3, T4]) SpawnWith(v1 T1, v2 T2, v3 T3, v4 T4) Entity {
+	return v.builder.NewEntityWith(v1, v2, v3, v4)
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View4[T1, T2, T3, T4]) Get(e Entity) (*T1, *T2, *T3, *T4) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View4[T1, T2, T3, T4]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4) {
+	v.builder.Set(e, v1, v2, v3, v4)
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View4[T1, T2, T3, T4]) Each(fn func(Entity, *T1, *T2, *T3, *T4)) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		c1, c2, c3, c4 := f.Get()
+		fn(f.Entity(), c1, c2, c3, c4)
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View4[T1, T2, T3, T4]) Filter() *Filter4[T1, T2, T3, T4] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View4[T1, T2, T3, T4]) Builder() *Builder4[T1, T2, T3, T4] {
+	return v.builder
+}
+
+// View5 bundles a Builder5 and a Filter5 for the component types
+// T1, T2, T3, T4, T5 behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View5[T1 any, T2 any, T3 any, T4 any, T5 any] struct {
+	builder *Builder5[T1, T2, T3, T4, T5]
+	filter  *Filter5[T1, T2, T3, T4, T5]
+}
+
+// NewView5 creates a new `View5` for entities with the components
+// T1, T2, T3, T4, T5.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View5`.
+func NewView5[T1 any, T2 any, T3 any, T4 any, T5 any](w *World) *View5[T1, T2, T3, T4, T5] {
+	return &View5[T1, T2, T3, T4, T5]{
+		builder: NewBuilder5[T1, T2, T3, T4, T5](w),
+		filter:  NewFilter5[T1, T2, T3, T4, T5](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components T1, T2, T3, T4, T5.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View5[T1, T2, T3, T4, T5]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View5[T1, T2, T3, T4, T5]) SpawnWith(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) Entity {
+	return v.builder.NewEntityWith(v1, v2, v3, v4, v5)
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View5[T1, T2, T3, T4, T5]) Get(e Entity) (*T1, *T2, *T3, *T4, *T5) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View5[T1, T2, T3, T4, T5]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) {
+	v.builder.Set(e, v1, v2, v3, v4, v5)
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View5[T1, T2, T3, T4, T5]) Each(fn func(Entity, *T1, *T2, *T3, *T4, *T5)) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		c1, c2, c3, c4, c5 := f.Get()
+		fn(f.Entity(), c1, c2, c3, c4, c5)
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View5[T1, T2, T3, T4, T5]) Filter() *Filter5[T1, T2, T3, T4, T5] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View5[T1, T2, T3, T4, T5]) Builder() *Builder5[T1, T2, T3, T4, T5] {
+	return v.builder
+}
+
+// View6 bundles a Builder6 and a Filter6 for the component types
+// T1, T2, T3, T4, T5, T6 behind a single handle. The component IDs are resolved once, when the
+// view is created, so a system can spawn, read, write and iterate entities of
+// this shape without repeating type lookups.
+type View6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any] struct {
+	builder *Builder6[T1, T2, T3, T4, T5, T6]
+	filter  *Filter6[T1, T2, T3, T4, T5, T6]
+}
+
+// NewView6 creates a new `View6` for entities with the components
+// T1, T2, T3, T4, T5, T6.
+//
+// Parameters:
+//   - w: The World the view operates on.
+//
+// Returns:
+//   - A pointer to the configured `View6`.
+func NewView6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](w *World) *View6[T1, T2, T3, T4, T5, T6] {
+	return &View6[T1, T2, T3, T4, T5, T6]{
+		builder: NewBuilder6[T1, T2, T3, T4, T5, T6](w),
+		filter:  NewFilter6[T1, T2, T3, T4, T5, T6](w),
+	}
+}
+
+// Spawn creates a new entity with zero-valued components T1, T2, T3, T4, T5, T6.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Spawn() Entity {
+	return v.builder.NewEntity()
+}
+
+// SpawnWith creates a new entity and initializes its components to the given
+// values.
+//
+// Returns:
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     World.SetMaxEntities is reached.
+func (v *View6[T1, T2, T3, T4, T5, T6]) SpawnWith(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) Entity {
+	return v.builder.NewEntityWith(v1, v2, v3, v4, v5, v6)
+}
+
+// Get retrieves pointers to the view's components of e, or nils if e is
+// invalid or lacks any of them.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Get(e Entity) (*T1, *T2, *T3, *T4, *T5, *T6) {
+	return v.builder.Get(e)
+}
+
+// Set adds or updates the view's components on e. Invalid entities are
+// ignored.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Set(e Entity, v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) {
+	v.builder.Set(e, v1, v2, v3, v4, v5, v6)
+}
+
+// Each calls fn for every entity that has all of the view's components,
+// passing pointers to them. fn must not perform structural changes.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Each(fn func(Entity, *T1, *T2, *T3, *T4, *T5, *T6)) {
+	f := v.filter
+	f.Reset()
+	for f.Next() {
+		c1, c2, c3, c4, c5, c6 := f.Get()
+		fn(f.Entity(), c1, c2, c3, c4, c5, c6)
+	}
+}
+
+// Filter returns the view's underlying filter.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Filter() *Filter6[T1, T2, T3, T4, T5, T6] {
+	return v.filter
+}
+
+// Builder returns the view's underlying builder.
+func (v *View6[T1, T2, T3, T4, T5, T6]) Builder() *Builder6[T1, T2, T3, T4, T5, T6] {
+	return v.builder
+}
+
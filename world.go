@@ -1,7 +1,12 @@
 package teishoku
 
 import (
+	"fmt"
+	"math"
+	"math/bits"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -22,11 +27,64 @@ type Entity struct {
 	Version uint32
 }
 
+// EntityID is an Entity packed into a single integer, with the ID in the high
+// 32 bits and the version in the low 32 bits. It is a convenient, collision-safe
+// handle for external systems such as scripting or networking, and a safe map
+// key: unlike a bare ID, it never matches a later entity that reuses the ID.
+type EntityID uint64
+
+// Packed returns the entity packed into an EntityID.
+func (e Entity) Packed() EntityID {
+	return EntityID(uint64(e.ID)<<32 | uint64(e.Version))
+}
+
+// UnpackEntity converts an EntityID produced by Entity.Packed back into an
+// Entity. It does not check that the entity is alive; see World.Lookup.
+//
+// Parameters:
+//   - id: The packed entity.
+//
+// Returns:
+//   - The unpacked Entity.
+func UnpackEntity(id EntityID) Entity {
+	return Entity{ID: uint32(id >> 32), Version: uint32(id)}
+}
+
+// EntityState describes the lifecycle state of an Entity handle, as reported
+// by World.EntityState.
+type EntityState uint8
+
+const (
+	// EntityInvalid means the handle was never issued by the world, e.g. the
+	// zero Entity or a handle forged from arbitrary values.
+	EntityInvalid EntityState = iota
+	// EntityAlive means the handle refers to a live entity.
+	EntityAlive
+	// EntityRecycled means the handle was valid once, but its entity has been
+	// removed; its ID may since have been reassigned to another entity.
+	EntityRecycled
+)
+
+// String returns the name of the state.
+func (s EntityState) String() string {
+	switch s {
+	case EntityAlive:
+		return "Alive"
+	case EntityRecycled:
+		return "Recycled"
+	default:
+		return "Invalid"
+	}
+}
+
 // entityMeta holds the internal location and state of an entity.
 type entityMeta struct {
 	archetypeIndex int    // index in World.archetypes
 	index          int    // position inside the archetype's component arrays
 	version        uint32 // current version, 0 if the entity is dead
+	issued         uint32 // latest version handed out for the ID, kept once it dies
+	userData       uint64 // user-defined value attached via SetUserData
+	owner          Entity // owning entity set via SetOwner, zero if none
 }
 
 // compSpec bundles a component type’s ID and reflect.Type.
@@ -36,20 +94,225 @@ type compSpec struct {
 	id   uint8
 }
 
+// Defaulter can be implemented by a component type (with a pointer receiver)
+// to provide its own initial value. Whenever the world places the component
+// into an entity without an explicit value, such as through Builder.NewEntity
+// or Builder.NewEntities, it calls DefaultComponent on the new slot.
+//
+// Example:
+//
+//	type Scale struct{ X, Y, Z float32 }
+//
+//	func (s *Scale) DefaultComponent() { *s = Scale{1, 1, 1} }
+type Defaulter interface {
+	DefaultComponent()
+}
+
+var defaulterType = reflect.TypeFor[Defaulter]()
+
+// Recycler can be implemented by a component type (with a pointer receiver)
+// that owns reusable memory, such as a slice or map buffer. When an entity
+// holding the component is destroyed, its slot keeps the component's value
+// instead of being zeroed, and the next entity created in that slot gets
+// Reset called on it first. Reset can thus truncate and keep its buffers, or
+// hand them back to a pool, instead of the new entity allocating fresh ones.
+//
+// Reset is also called on slots that never held an entity, i.e. on the zero
+// value. Components that merely move between archetypes are not reset.
+//
+// Example:
+//
+//	type Path struct{ Points []Vec2 }
+//
+//	func (p *Path) Reset() { p.Points = p.Points[:0] }
+type Recycler interface {
+	Reset()
+}
+
+var recyclerType = reflect.TypeFor[Recycler]()
+
+// compColumn identifies an archetype column by component ID and type.
+type compColumn struct {
+	typ      reflect.Type
+	id       uint8
+	recycler bool // the type implements Recycler
+}
+
 // archetype holds storage for one unique component-set mask.
 type archetype struct {
 	compPointers [MaxComponentTypes]unsafe.Pointer
-	entityIDs    []Entity // prealloc len=cap
-	compOrder    []uint8  // list of component IDs in this arch
+	entityIDs    []Entity       // prealloc len=cap
+	compOrder    []uint8        // list of component IDs in this arch
+	compTypes    []reflect.Type // column types, parallel to compOrder
+	defaulters   []compColumn   // columns initialized through Defaulter
+	pointerCols  []compColumn   // columns whose type contains pointers
+	recyclers    []compColumn   // columns reset through Recycler
+	allocated    bool           // entityIDs and columns exist, see WithLazyColumns
 	compSizes    [MaxComponentTypes]uintptr
-	mask         bitmask256 // which component bits this arch uses
-	index        int        // position in world.archetypes
-	size         int        // current entity count
+	mask         bitmask256      // which component bits this arch uses
+	index        int             // position in world.archetypes
+	size         int             // current entity count
+	edges        []archetypeEdge // recently used transitions, see transitionNoLock
+}
+
+// maxArchetypeEdges bounds the number of transitions cached per archetype, so
+// the linear scan over them stays cheaper than a map lookup.
+const maxArchetypeEdges = 8
+
+// archetypeEdge caches the archetype reached by switching to mask.
+type archetypeEdge struct {
+	mask bitmask256
+	to   *archetype
+}
+
+// addColumn registers the component described by sp in the archetype's
+// column bookkeeping. The caller is responsible for allocating the column.
+func (a *archetype) addColumn(sp compSpec) {
+	a.compSizes[sp.id] = sp.size
+	a.compOrder = append(a.compOrder, sp.id)
+	a.compTypes = append(a.compTypes, sp.typ)
+	if reflect.PointerTo(sp.typ).Implements(defaulterType) {
+		a.defaulters = append(a.defaulters, compColumn{typ: sp.typ, id: sp.id})
+	}
+	recycler := reflect.PointerTo(sp.typ).Implements(recyclerType)
+	if recycler {
+		a.recyclers = append(a.recyclers, compColumn{typ: sp.typ, id: sp.id, recycler: true})
+	}
+	if typeHasPointers(sp.typ) {
+		a.pointerCols = append(a.pointerCols, compColumn{typ: sp.typ, id: sp.id, recycler: recycler})
+	}
+}
+
+// clearSlots zeroes the count slots starting at index start in every column
+// whose component type contains pointers. It is called on slots that no longer
+// hold a live entity, so dead component data neither keeps heap objects alive
+// nor leaks into entities that later reuse the slot. Pointer-free columns are
+// left as is.
+func (a *archetype) clearSlots(start, count int) {
+	if count <= 0 {
+		return
+	}
+	for _, c := range a.pointerCols {
+		ptr := unsafe.Add(a.compPointers[c.id], uintptr(start)*a.compSizes[c.id])
+		reflect.SliceAt(c.typ, ptr, count).Clear()
+	}
+}
+
+// zeroSlot zeroes component cid in slot i. A slot freed by a removal keeps
+// the bytes of its pointer-free components and the values of its recyclers.
+func (a *archetype) zeroSlot(cid uint8, i int) {
+	size := a.compSizes[cid]
+	if size == 0 {
+		return
+	}
+	ptr := unsafe.Add(a.compPointers[cid], uintptr(i)*size)
+	for _, c := range a.pointerCols {
+		if c.id == cid {
+			if c.recycler {
+				reflect.NewAt(c.typ, ptr).Elem().SetZero()
+			}
+			return // other pointer columns are cleared on removal
+		}
+	}
+	clear(unsafe.Slice((*byte)(ptr), size))
+}
+
+// clearDeadSlots is like clearSlots for slots whose entities were destroyed.
+// Recycler columns keep their values, so the buffers they own can be reused
+// by the next entity created in the slot.
+func (a *archetype) clearDeadSlots(start, count int) {
+	if len(a.recyclers) == 0 {
+		a.clearSlots(start, count)
+		return
+	}
+	if count <= 0 {
+		return
+	}
+	for _, c := range a.pointerCols {
+		if c.recycler {
+			continue
+		}
+		ptr := unsafe.Add(a.compPointers[c.id], uintptr(start)*a.compSizes[c.id])
+		reflect.SliceAt(c.typ, ptr, count).Clear()
+	}
+}
+
+// recycle calls Reset on the Recycler components of the count slots starting
+// at index start, before new entities are placed there.
+func (a *archetype) recycle(start, count int) {
+	for _, r := range a.recyclers {
+		for i := start; i < start+count; i++ {
+			ptr := unsafe.Add(a.compPointers[r.id], uintptr(i)*a.compSizes[r.id])
+			reflect.NewAt(r.typ, ptr).Interface().(Recycler).Reset()
+		}
+	}
+}
+
+// typeHasPointers reports whether values of type t contain pointers that the
+// garbage collector has to trace.
+func typeHasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Slice,
+		reflect.String, reflect.Interface, reflect.Chan, reflect.Func:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && typeHasPointers(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if typeHasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyDefaults initializes the Defaulter components of count entities
+// starting at index start.
+func (a *archetype) applyDefaults(start, count int) {
+	for _, d := range a.defaulters {
+		for i := start; i < start+count; i++ {
+			ptr := unsafe.Add(a.compPointers[d.id], uintptr(i)*a.compSizes[d.id])
+			reflect.NewAt(d.typ, ptr).Interface().(Defaulter).DefaultComponent()
+		}
+	}
+}
+
+// allocStorage allocates the archetype's entity and component arrays at the
+// world's capacity. With WithLazyColumns it runs when the archetype receives
+// its first entity instead of when the archetype is created.
+func (w *World) allocStorage(a *archetype) {
+	n := w.entities.capacity
+	a.entityIDs = make([]Entity, n)
+	for i, cid := range a.compOrder {
+		if a.compSizes[cid] == 0 {
+			a.compPointers[cid] = tagColumn
+			continue
+		}
+		// allocate []T of length=cap; the runtime aligns the backing array to
+		// the type's alignment and Go sizes are multiples of their alignment,
+		// so base + index*size stays aligned for every element.
+		a.compPointers[cid] = reflect.MakeSlice(reflect.SliceOf(a.compTypes[i]), n, n).UnsafePointer()
+	}
+	a.allocated = true
+}
+
+// tagColumn is the column base shared by every zero-size (tag) component.
+// Every element of such a column has the same address, so no storage is
+// allocated for it.
+var tagColumn = unsafe.Pointer(new(struct{}))
+
+// ensureStorage allocates the archetype's storage if it was deferred by
+// WithLazyColumns. It must be called before placing an entity in a.
+func (w *World) ensureStorage(a *archetype) {
+	if !a.allocated {
+		w.allocStorage(a)
+	}
 }
 
 // resizeTo resizes the archetype's storage to newCap, copying existing data.
 func (a *archetype) resizeTo(newCap int, w *World) {
-	if cap(a.entityIDs) >= newCap {
+	if !a.allocated || cap(a.entityIDs) >= newCap {
 		return
 	}
 	// resize entityIDs
@@ -59,6 +322,9 @@ func (a *archetype) resizeTo(newCap int, w *World) {
 	// resize comps
 	w.components.mu.RLock()
 	for _, cid := range a.compOrder {
+		if a.compSizes[cid] == 0 {
+			continue // tag columns share tagColumn
+		}
 		typ := w.components.compIDToType[cid]
 		newSlice := reflect.MakeSlice(reflect.SliceOf(typ), newCap, newCap)
 		newPtr := newSlice.UnsafePointer()
@@ -72,8 +338,16 @@ func (a *archetype) resizeTo(newCap int, w *World) {
 	w.components.mu.RUnlock()
 }
 
-type componentRegistry struct {
-	mu             sync.RWMutex
+// ComponentRegistry assigns the numeric IDs that identify component types
+// inside a World. By default every World owns a private registry, so the same
+// Go type may receive a different ID in each World depending on registration
+// order. Worlds created with NewWorldWithRegistry and the same registry are
+// guaranteed to agree on every component ID, which keeps masks, archetype
+// layouts and serialized data portable between them.
+//
+// A ComponentRegistry is safe for concurrent use by multiple Worlds.
+type ComponentRegistry struct {
+	mu             registryLock
 	compIDToType   [MaxComponentTypes]reflect.Type
 	compTypeMap    map[reflect.Type]uint8
 	compIDToSize   [MaxComponentTypes]uintptr
@@ -81,11 +355,14 @@ type componentRegistry struct {
 }
 
 type entityRegistry struct {
-	freeIDs         []uint32     // stack of recycled entity IDs
+	freeIDs         []uint32     // stack of recycled entity IDs, or a min-heap if deterministic
+	deterministic   bool         // always hand out the lowest free ID, see WithDeterministicIDs
+	popScratch      []uint32     // IDs popped by a deterministic batch creation
 	metas           []entityMeta // stores metadata for each entity, indexed by entity ID
 	capacity        int          // current maximum number of entities
 	initialCapacity int          // initial capacity, used for expansion
 	nextEntityVer   uint32       // version for the next created entity
+	versionsWrapped bool         // nextEntityVer has wrapped around at least once
 }
 
 type archetypeRegistry struct {
@@ -106,9 +383,41 @@ type World struct {
 	resources       *Resources
 	archetypes      archetypeRegistry
 	entities        entityRegistry
-	components      componentRegistry
+	components      *ComponentRegistry
 	mutationVersion atomic.Uint32 // incremented on entity mutations
-	mu              sync.RWMutex
+	mu              worldLock
+	closed          atomic.Bool            // set by Close
+	scratch         []byte                 // temporary row storage used by swapRows
+	compPriority    [MaxComponentTypes]int // layout priority per component ID, see SetComponentPriority
+	hasPriority     bool
+	changes         [MaxComponentTypes]*changeSet         // per component ID, nil unless tracked
+	trackedIDs      []uint8                               // component IDs with change tracking
+	versions        [MaxComponentTypes]*versionSet        // per component ID, nil unless versioned
+	versionedIDs    []uint8                               // component IDs with write counters
+	maxEntities     int                                   // live entity limit, 0 if unlimited
+	owned           map[uint32][]Entity                   // owner ID -> entities it owns, see SetOwner
+	pendingOwned    []Entity                              // owned entities queued for cascade removal
+	relations       map[uint8]relationIndex               // per Relation component ID, see Link
+	events          map[reflect.Type]eventBuffer          // queued events per type, see Emit
+	eventsMu        sync.Mutex                            // guards events independently of mu
+	onArchetype     func(mask Mask, types []reflect.Type) // see OnArchetypeCreated
+	systems         scheduler                             // see RegisterSystem
+	matchSets       map[matchKey]*matchSet                // archetype lists shared by identical filters
+	matchMu         sync.Mutex                            // guards matchSets
+	stats           worldStats                            // see Stats
+	asyncOps        []func()                              // creation requests queued by async builders
+	asyncMu         sync.Mutex                            // guards asyncOps independently of mu
+	lazyColumns     bool                                  // see WithLazyColumns
+	growth          func(current, needed int) int         // see SetGrowthStrategy, nil doubles
+	access          *accessCounters                       // see AccessProfile, nil without the ecsprofile tag
+	touched         []atomic.Uint32                       // frame of each entity ID's last access, see AuditLeaks
+	frame           atomic.Uint32                         // see AdvanceFrame
+	leakFrames      int                                   // see SetLeakThreshold, 0 for the default
+	lifetimes       *Filter[Lifetime]                     // built by the first Tick
+	lifetimeOnce    sync.Once                             // guards lifetimes
+	expired         []Entity                              // scratch list reused by Tick
+	transients      [MaxComponentTypes]any                // *transientStore[T] per component ID, see RegisterTransient
+	transientMask   bitmask256                            // component IDs stored in transients
 }
 
 // NewWorld creates and initializes a new World with a specified initial
@@ -118,15 +427,91 @@ type World struct {
 // Parameters:
 //   - initialCapacity: The number of entities to pre-allocate memory for.
 //     Choosing a suitable capacity can prevent re-allocations during runtime.
+//   - opts: Optional settings, such as WithSingleThreaded.
 //
 // Returns:
 //   - The newly created World.
-func NewWorld(initialCapacity int) *World {
+func NewWorld(initialCapacity int, opts ...WorldOption) *World {
+	return NewWorldWithRegistry(NewComponentRegistry(), initialCapacity, opts...)
+}
+
+// WorldOption configures optional World behaviour at creation time. Options
+// are passed to NewWorld or NewWorldWithRegistry.
+type WorldOption func(w *World)
+
+// WithSingleThreaded disables the World's internal lock. Every method then
+// skips mutex operations, which removes their overhead from hot paths such as
+// GetComponent and filter resets in a single-threaded game loop.
+//
+// A single-threaded World must only ever be used from one goroutine at a
+// time; concurrent access is a data race. Worlds are locked by default.
+func WithSingleThreaded() WorldOption {
+	return func(w *World) {
+		w.mu.disabled = true
+	}
+}
+
+// WithDeterministicIDs makes the World always hand out the lowest free entity
+// ID, so the same sequence of creations and removals yields identical entity
+// handles on every run. This simplifies test assertions and replays.
+//
+// By default freed IDs are reused last-in first-out, which favors cache
+// locality; the deterministic mode keeps free IDs in a min-heap instead and
+// pays O(log n) per allocation and removal.
+func WithDeterministicIDs() WorldOption {
+	return func(w *World) {
+		w.entities.deterministic = true
+		// An ascending list is already a valid min-heap.
+		slices.Sort(w.entities.freeIDs)
+	}
+}
+
+// WithLazyColumns defers allocating an archetype's storage until the
+// archetype receives its first entity. Worlds whose entities pass through many
+// short-lived component combinations, or that prewarm archetypes they may never
+// populate, then only pay memory for the archetypes actually holding entities.
+// EnsureCapacity still allocates immediately, since its purpose is to pre-grow.
+//
+// Storage is allocated per archetype rather than per column: accessors such
+// as GetComponent and a filter's Get hand out writable pointers, so there is
+// no write the World could observe to allocate a single column on.
+func WithLazyColumns() WorldOption {
+	return func(w *World) {
+		w.lazyColumns = true
+	}
+}
+
+// NewComponentRegistry creates an empty ComponentRegistry that can be shared
+// between several Worlds through NewWorldWithRegistry.
+//
+// Returns:
+//   - A pointer to the new ComponentRegistry.
+func NewComponentRegistry() *ComponentRegistry {
+	return &ComponentRegistry{
+		compTypeMap: make(map[reflect.Type]uint8, 16),
+	}
+}
+
+// NewWorldWithRegistry creates a new World that assigns component IDs from the
+// given registry instead of a private one. All Worlds sharing a registry see
+// identical IDs for the same component types, which is required when copying
+// entities or serialized data between Worlds, for example between a client
+// prediction World and a server authoritative World.
+//
+// Parameters:
+//   - reg: The shared component registry. It must not be nil.
+//   - initialCapacity: The number of entities to pre-allocate memory for.
+//   - opts: Optional settings, such as WithSingleThreaded.
+//
+// Returns:
+//   - A pointer to the newly created World.
+func NewWorldWithRegistry(reg *ComponentRegistry, initialCapacity int, opts ...WorldOption) *World {
+	if reg == nil {
+		panic("ecs: nil ComponentRegistry")
+	}
 	w := &World{
-		resources: &Resources{},
-		components: componentRegistry{
-			compTypeMap: make(map[reflect.Type]uint8, 16),
-		},
+		resources:  &Resources{},
+		components: reg,
 		entities: entityRegistry{
 			capacity:        initialCapacity,
 			initialCapacity: initialCapacity,
@@ -144,6 +529,13 @@ func NewWorld(initialCapacity int) *World {
 		w.entities.metas[i].index = -1
 		w.entities.metas[i].version = 0
 	}
+	if accessProfiling {
+		w.access = new(accessCounters)
+		w.growTouched()
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
 	w.entities.nextEntityVer = 1
 	var mask bitmask256
 	w.getOrCreateArchetype(mask, []compSpec{})
@@ -155,7 +547,8 @@ func NewWorld(initialCapacity int) *World {
 // adding components.
 //
 // Returns:
-//   - The newly created Entity.
+//   - The newly created Entity, or the zero Entity if the limit set by
+//     SetMaxEntities is reached.
 func (w *World) CreateEntity() Entity {
 	var mask bitmask256
 	a := w.getOrCreateArchetype(mask, []compSpec{})
@@ -168,54 +561,102 @@ func (w *World) CreateEntity() Entity {
 //
 // Parameters:
 //   - count: The number of entities to create.
-func (w *World) CreateEntities(count int) {
+//
+// Returns:
+//   - The number of entities created, which is less than count when the
+//     limit set by SetMaxEntities is reached.
+func (w *World) CreateEntities(count int) int {
 	if count == 0 {
-		return
+		return 0
 	}
 	var mask bitmask256
 	a := w.getOrCreateArchetype(mask, []compSpec{})
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	for len(w.entities.freeIDs) < count {
-		w.expand()
+	_, n := w.createEntitiesNoLock(a, count)
+	if n > 0 {
+		w.mutationVersion.Add(1)
 	}
-	startSize := a.size
-	a.size += count
-	popped := w.entities.freeIDs[len(w.entities.freeIDs)-count:]
-	w.entities.freeIDs = w.entities.freeIDs[:len(w.entities.freeIDs)-count]
-	for k := 0; k < count; k++ {
-		id := popped[k]
-		meta := &w.entities.metas[id]
-		meta.archetypeIndex = a.index
-		meta.index = startSize + k
-		meta.version = w.entities.nextEntityVer
-		ent := Entity{ID: id, Version: meta.version}
-		a.entityIDs[startSize+k] = ent
-		w.entities.nextEntityVer++
+	return n
+}
+
+// SpawnGroup describes one group of entities created by World.SpawnBatch:
+// Count entities holding exactly the component types in Types.
+type SpawnGroup struct {
+	Types []reflect.Type
+	Count int
+}
+
+// SpawnBatch creates entities of several different archetypes in a single
+// locked operation. The world grows once for the total number of entities and
+// each group is appended to its archetype in one pass, which makes it well
+// suited to level loading, where thousands of entities of varied shapes are
+// created at once. Components start at their zero value, or are initialized
+// by DefaultComponent for types implementing Defaulter; use the filters or
+// SetComponent to fill in data afterwards.
+//
+// Parameters:
+//   - groups: The component sets and counts of the entities to create. Groups
+//     with a count of zero or less create nothing.
+//
+// Returns:
+//   - The created entities, group after group, in the order of groups. When
+//     the limit set by SetMaxEntities is reached, the remaining entities are
+//     not created.
+func (w *World) SpawnBatch(groups []SpawnGroup) []Entity {
+	type resolved struct {
+		mask  bitmask256
+		specs []compSpec
 	}
-	w.mutationVersion.Add(1)
+	shapes := make([]resolved, len(groups))
+	total := 0
+	for i, g := range groups {
+		r := &shapes[i]
+		for _, t := range g.Types {
+			id := w.getCompTypeID(t)
+			if r.mask.has(id) {
+				continue
+			}
+			r.mask.set(id)
+			r.specs = append(r.specs, compSpec{id: id, typ: t, size: t.Size()})
+		}
+		total += max(g.Count, 0)
+	}
+	if total == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkOpen()
+	w.reserveNoLock(total)
+	ents := make([]Entity, 0, total)
+	for i, g := range groups {
+		if g.Count <= 0 {
+			continue
+		}
+		a := w.getOrCreateArchetypeNoLock(shapes[i].mask, shapes[i].specs)
+		start, n := w.createEntitiesNoLock(a, g.Count)
+		ents = append(ents, a.entityIDs[start:start+n]...)
+	}
+	if len(ents) > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return ents
 }
 
 // RemoveEntity marks the entity as invalid and recycles its ID for future use.
 // All components associated with the entity are discarded. If the entity is
-// already invalid, this operation does nothing.
+// already invalid, this operation does nothing. Entities it owns (see
+// SetOwner) are removed along with it, in the same structural change.
 //
 // Parameters:
 //   - e: The Entity to remove.
 func (w *World) RemoveEntity(e Entity) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	if !w.IsValidNoLock(e) {
-		return
+	if w.removeEntityNoLock(e) {
+		w.mutationVersion.Add(1)
 	}
-	meta := &w.entities.metas[e.ID]
-	a := w.archetypes.archetypes[meta.archetypeIndex]
-	w.removeFromArchetype(a, meta)
-	meta.archetypeIndex = -1
-	meta.index = -1
-	meta.version = 0
-	w.entities.freeIDs = append(w.entities.freeIDs, e.ID)
-	w.mutationVersion.Add(1)
 }
 
 // RemoveEntities removes a list of entities from the world in a single batch
@@ -228,16 +669,7 @@ func (w *World) RemoveEntities(ents []Entity) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	for _, e := range ents {
-		if !w.IsValidNoLock(e) {
-			continue
-		}
-		meta := &w.entities.metas[e.ID]
-		a := w.archetypes.archetypes[meta.archetypeIndex]
-		w.removeFromArchetype(a, meta)
-		meta.archetypeIndex = -1
-		meta.index = -1
-		meta.version = 0
-		w.entities.freeIDs = append(w.entities.freeIDs, e.ID)
+		w.removeEntityNoLock(e)
 	}
 	w.mutationVersion.Add(1)
 }
@@ -252,93 +684,867 @@ func (w *World) ClearEntities() {
 		if a.size > 0 {
 			for i := 0; i < a.size; i++ {
 				ent := a.entityIDs[i]
-				meta := &w.entities.metas[ent.ID]
-				meta.archetypeIndex = -1
-				meta.index = -1
-				meta.version = 0
-				w.entities.freeIDs = append(w.entities.freeIDs, ent.ID)
+				w.releaseEntityNoLock(ent.ID)
 			}
+			a.clearDeadSlots(0, a.size)
 			a.size = 0
 		}
 	}
+	w.removeOwnedNoLock()
 	w.mutationVersion.Add(1)
 }
 
-// IsValid checks if the given entity is currently alive by verifying that its
-// version matches the world's current version for that ID. This prevents
-// "stale" entity references from accessing incorrect data after an entity has
-// been deleted and its ID recycled.
+// Merge imports every entity of other, together with its components, into
+// the world. Entities receive fresh IDs in the receiver, so they never collide
+// with existing ones; the returned map translates each entity of other to its
+// counterpart in the receiver. Component data is bulk-copied column by column
+// into the archetype with the same mask, which is created if needed. other is
+// left unchanged.
+//
+// Both worlds must have been created with the same ComponentRegistry (see
+// NewWorldWithRegistry), otherwise component IDs would not line up and Merge
+// panics. It also panics if other is the receiver itself. The receiver's lock
+// is acquired before other's, so two goroutines must not merge two worlds into
+// each other concurrently.
 //
 // Parameters:
-//   - e: The Entity to validate.
+//   - other: The world whose entities are copied into the receiver.
 //
 // Returns:
-//   - true if the entity is valid, false otherwise.
-func (w *World) IsValid(e Entity) bool {
-	w.mu.RLock()
-	defer w.mu.RUnlock()
-	return w.IsValidNoLock(e)
+//   - A map from each entity of other to the entity created for it. Entities
+//     beyond the limit set by SetMaxEntities are not imported and are absent
+//     from the map.
+func (w *World) Merge(other *World) map[Entity]Entity {
+	if other == w {
+		panic("ecs: cannot merge a World into itself")
+	}
+	if other.components != w.components {
+		panic("ecs: Merge requires both worlds to share a ComponentRegistry")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	w.checkOpen()
+	other.checkOpen()
+
+	remap := make(map[Entity]Entity)
+	var tempSpecs [MaxComponentTypes]compSpec
+	for _, src := range other.archetypes.archetypes {
+		if src.size == 0 {
+			continue
+		}
+		w.components.mu.RLock()
+		for i, cid := range src.compOrder {
+			tempSpecs[i] = compSpec{id: cid, typ: w.components.compIDToType[cid], size: w.components.compIDToSize[cid]}
+		}
+		w.components.mu.RUnlock()
+		dst := w.getOrCreateArchetypeNoLock(src.mask, tempSpecs[:len(src.compOrder)])
+		start, n := w.createEntitiesNoLock(dst, src.size)
+		for _, cid := range src.compOrder {
+			size := src.compSizes[cid]
+			memCopy(unsafe.Add(dst.compPointers[cid], uintptr(start)*size), src.compPointers[cid], uintptr(n)*size)
+		}
+		for i := 0; i < n; i++ {
+			old := src.entityIDs[i]
+			ent := dst.entityIDs[start+i]
+			w.entities.metas[ent.ID].userData = other.entities.metas[old.ID].userData
+			remap[old] = ent
+		}
+	}
+	if len(remap) > 0 {
+		w.mutationVersion.Add(1)
+	}
+	return remap
 }
 
-// IsValidNoLock checks if the given entity is currently alive by verifying
-// that its version matches the world's current version for that ID. This
-// prevents "stale" entity references from accessing incorrect data after an
-// entity has been deleted and its ID recycled.
+// SwapComponents exchanges the storage slots of two entities that belong to
+// the same archetype: each entity keeps its own component values but takes the
+// other's position in the archetype's columns. This is a building block for
+// manual ordering, such as sorting entities by depth or grouping hot entities
+// together for better cache locality.
 //
-// This is the lock-free version of IsValid and should only be called when the
-// world's mutex is already held.
+// Component pointers obtained before the swap refer to the other entity
+// afterwards, so the swap bumps the world's mutation version like any other
+// change that moves component data.
 //
 // Parameters:
-//   - e: The Entity to validate.
+//   - a: The first Entity.
+//   - b: The second Entity.
 //
 // Returns:
-//   - true if the entity is valid, false otherwise.
-func (w *World) IsValidNoLock(e Entity) bool {
-	if int(e.ID) >= len(w.entities.metas) {
+//   - true if the entities were swapped, false if either is invalid or they
+//     are in different archetypes.
+func (w *World) SwapComponents(a, b Entity) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(a) || !w.IsValidNoLock(b) {
 		return false
 	}
-	meta := w.entities.metas[e.ID]
-	return meta.version != 0 && meta.version == e.Version
+	ma := &w.entities.metas[a.ID]
+	mb := &w.entities.metas[b.ID]
+	if ma.archetypeIndex != mb.archetypeIndex {
+		return false
+	}
+	if ma.index == mb.index {
+		return true
+	}
+	w.swapRows(w.archetypes.archetypes[ma.archetypeIndex], ma.index, mb.index)
+	w.mutationVersion.Add(1)
+	return true
 }
 
-// Resources returns the world's resource manager. It provides a thread-safe,
-// generic key-value store for global data that needs to be accessible from
-// anywhere in the application, such as configuration objects, resource managers,
-// or event buses.
+// swapRows exchanges rows i and j of the archetype with no-lock, including
+// the entity IDs and their metadata indices. Component bytes are exchanged
+// through the world's scratch buffer.
+func (w *World) swapRows(a *archetype, i, j int) {
+	w.checkStructural()
+	for _, cid := range a.compOrder {
+		size := a.compSizes[cid]
+		if size == 0 {
+			continue
+		}
+		if uintptr(len(w.scratch)) < size {
+			w.scratch = make([]byte, size)
+		}
+		tmp := unsafe.Pointer(&w.scratch[0])
+		pi := unsafe.Add(a.compPointers[cid], uintptr(i)*size)
+		pj := unsafe.Add(a.compPointers[cid], uintptr(j)*size)
+		memCopy(tmp, pi, size)
+		memCopy(pi, pj, size)
+		memCopy(pj, tmp, size)
+	}
+	a.entityIDs[i], a.entityIDs[j] = a.entityIDs[j], a.entityIDs[i]
+	w.entities.metas[a.entityIDs[i].ID].index = i
+	w.entities.metas[a.entityIDs[j].ID].index = j
+}
+
+// IsTag reports whether the component type t is a tag, i.e. has a size of
+// zero. Tags carry no data: they only mark entities through the archetype
+// mask and get no column storage. Editors and generic serializers can use it
+// to skip them. t does not need to be registered.
+//
+// Parameters:
+//   - t: The component type to check.
 //
 // Returns:
-//   - A pointer to the Resources object.
-func (w *World) Resources() *Resources {
-	return w.resources
+//   - true if t has a size of zero, false otherwise.
+func (w *World) IsTag(t reflect.Type) bool {
+	_, size, _ := w.ComponentInfo(t)
+	return size == 0
 }
 
-// register or fetch a component type ID for T.
-func (w *World) getCompTypeID(t reflect.Type) uint8 {
+// ComponentInfo reports how the world knows the component type t, without
+// registering it. Editors, inspectors and save formats can use it to map
+// types to the IDs used in masks and storage.
+//
+// Parameters:
+//   - t: The component type to look up.
+//
+// Returns:
+//   - id: The component's ID, valid only if registered is true.
+//   - size: The component's size in bytes.
+//   - registered: Whether t has been registered with the world's registry.
+func (w *World) ComponentInfo(t reflect.Type) (id uint8, size uintptr, registered bool) {
 	w.components.mu.RLock()
-	if id, ok := w.components.compTypeMap[t]; ok {
-		w.components.mu.RUnlock()
-		return id
+	defer w.components.mu.RUnlock()
+	id, registered = w.components.compTypeMap[t]
+	if !registered {
+		return 0, t.Size(), false
 	}
-	w.components.mu.RUnlock()
-	w.components.mu.Lock()
-	defer w.components.mu.Unlock()
-	if id, ok := w.components.compTypeMap[t]; ok {
-		return id
+	return id, w.components.compIDToSize[id], true
+}
+
+// RegisteredComponents lists every component type registered with the world's
+// registry, indexed by component ID: the type at position i has ID i.
+//
+// Returns:
+//   - A new slice of the registered component types.
+func (w *World) RegisteredComponents() []reflect.Type {
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	return slices.Clone(w.components.compIDToType[:w.components.nextCompTypeID])
+}
+
+// ComponentSchema describes the layout of every component type registered in
+// the world, mapping each type's name (as printed by reflect.Type.String) to
+// its size in bytes. Persisting the schema next to saved component data lets
+// a later load detect that a component struct changed layout, e.g. because a
+// field was added, before misreading raw bytes. See CheckComponentSchema.
+//
+// Returns:
+//   - A map from component type name to size in bytes.
+func (w *World) ComponentSchema() map[string]int {
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	schema := make(map[string]int, w.components.nextCompTypeID)
+	for id := 0; id < int(w.components.nextCompTypeID); id++ {
+		t := w.components.compIDToType[id]
+		schema[t.String()] = int(t.Size())
 	}
-	if w.components.nextCompTypeID >= MaxComponentTypes {
-		panic("ecs: too many component types")
+	return schema
+}
+
+// CheckComponentSchema compares a schema previously obtained from
+// ComponentSchema with the component types currently registered in the world.
+// Types present in only one of the two are ignored, since a loading world may
+// not have registered every type yet.
+//
+// Parameters:
+//   - schema: The saved schema to validate.
+//
+// Returns:
+//   - An error listing every component whose size differs, or nil if the
+//     layouts are compatible.
+func (w *World) CheckComponentSchema(schema map[string]int) error {
+	current := w.ComponentSchema()
+	var mismatches []string
+	for name, size := range schema {
+		if cur, ok := current[name]; ok && cur != size {
+			mismatches = append(mismatches, fmt.Sprintf("%s (saved %d bytes, registered %d bytes)", name, size, cur))
+		}
 	}
-	id := uint8(w.components.nextCompTypeID)
-	w.components.compTypeMap[t] = id
-	w.components.compIDToType[id] = t
-	w.components.compIDToSize[id] = t.Size()
-	w.components.nextCompTypeID++
-	return id
+	if len(mismatches) == 0 {
+		return nil
+	}
+	slices.Sort(mismatches)
+	return fmt.Errorf("ecs: component layout changed: %s", strings.Join(mismatches, ", "))
 }
 
-// getOrCreateArchetype returns an archetype for the given mask;
-// if missing, allocates component storage arrays of length cap.
+// Diff compares the components of two entities, e.g. to show how a prefab
+// instance differs from its template in an editor. An invalid entity is
+// treated as having no components. Each result lists types in component ID
+// order.
+//
+// Parameters:
+//   - a: The first entity.
+//   - b: The second entity.
+//
+// Returns:
+//   - onlyA: The component types a has and b lacks.
+//   - onlyB: The component types b has and a lacks.
+//   - common: The component types both entities have.
+func (w *World) Diff(a, b Entity) (onlyA, onlyB, common []reflect.Type) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	ma, mb := w.entityMaskNoLock(a), w.entityMaskNoLock(b)
+	var xa, xb, both bitmask256
+	for i := range both {
+		diff := ma[i] ^ mb[i]
+		xa[i] = diff & ma[i]
+		xb[i] = diff & mb[i]
+		both[i] = ma[i] & mb[i]
+	}
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	return w.maskTypesNoLock(xa), w.maskTypesNoLock(xb), w.maskTypesNoLock(both)
+}
+
+// ComponentsOf returns the component types an entity currently has: its
+// archetype's columns, in storage order, followed by its transient components
+// in ID order. It is the programmatic form of the entity's schema, for
+// scripting bridges and inspectors that offer typed editing of whatever an
+// entity holds. The returned slice is a copy and may be modified freely.
+//
+// Parameters:
+//   - e: The entity to inspect.
+//
+// Returns:
+//   - The entity's component types, or nil if the entity is invalid.
+func (w *World) ComponentsOf(e Entity) []reflect.Type {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return nil
+	}
+	a := w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex]
+	types := append(make([]reflect.Type, 0, len(a.compTypes)), a.compTypes...)
+	if w.transientMask == (bitmask256{}) {
+		return types
+	}
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	for _, t := range w.maskTypesNoLock(w.transientMask) {
+		id := w.components.compTypeMap[t]
+		if w.transients[id].(transientSlot).slot(w, e) >= 0 {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// entityMaskNoLock returns the component mask of the entity with no-lock, or
+// an empty mask if the entity is invalid.
+func (w *World) entityMaskNoLock(e Entity) bitmask256 {
+	if !w.IsValidNoLock(e) {
+		return bitmask256{}
+	}
+	return w.archetypes.archetypes[w.entities.metas[e.ID].archetypeIndex].mask
+}
+
+// maskTypesNoLock maps the bits of m to their component types, in ID order.
+// The caller must hold the component registry's read lock.
+func (w *World) maskTypesNoLock(m bitmask256) []reflect.Type {
+	var types []reflect.Type
+	for i, word := range m {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			types = append(types, w.components.compIDToType[i*64+bit])
+			word &= word - 1
+		}
+	}
+	return types
+}
+
+// SetComponentPriority declares which component types should be laid out
+// first in archetypes. Columns of archetypes are allocated, and entity data is
+// copied, in archetype column order; listing the components a hot loop reads
+// together (e.g. Position then Velocity) places their columns first and next
+// to each other, which helps allocation locality and hardware prefetching.
+//
+// The types are given from highest to lowest priority; types not listed keep
+// their relative order after them. Each call replaces the previous priority
+// list. Only archetypes created afterwards are affected; existing archetypes
+// keep their layout.
+//
+// Parameters:
+//   - types: The component types in decreasing order of priority.
+func (w *World) SetComponentPriority(types ...reflect.Type) {
+	ids := make([]uint8, len(types))
+	for i, t := range types {
+		ids[i] = w.getCompTypeID(t)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.compPriority = [MaxComponentTypes]int{}
+	for i, id := range ids {
+		if w.compPriority[id] == 0 {
+			w.compPriority[id] = len(ids) - i
+		}
+	}
+	w.hasPriority = len(ids) > 0
+}
+
+// prioritizeSpecs returns specs ordered by the priorities set with
+// SetComponentPriority. The input is left untouched.
+func (w *World) prioritizeSpecs(specs []compSpec) []compSpec {
+	if !w.hasPriority || len(specs) < 2 {
+		return specs
+	}
+	sorted := slices.Clone(specs)
+	slices.SortStableFunc(sorted, func(a, b compSpec) int {
+		return w.compPriority[b.id] - w.compPriority[a.id]
+	})
+	return sorted
+}
+
+// PrewarmArchetype creates the archetype for the given combination of
+// component types without creating any entity in it. Calling it at load time
+// moves the cost of allocating the archetype's storage out of the first frame
+// that spawns an entity of that shape. Duplicate types are ignored, and
+// prewarming an existing archetype is a no-op.
+//
+// Parameters:
+//   - types: The component types making up the archetype.
+func (w *World) PrewarmArchetype(types ...reflect.Type) {
+	var mask bitmask256
+	specs := make([]compSpec, 0, len(types))
+	for _, t := range types {
+		id := w.getCompTypeID(t)
+		if mask.has(id) {
+			continue
+		}
+		mask.set(id)
+		specs = append(specs, compSpec{id: id, typ: t, size: t.Size()})
+	}
+	w.getOrCreateArchetype(mask, specs)
+}
+
+// MaskOf returns the archetype mask for the given combination of component
+// types, registering types the world has not seen yet. The mask identifies the
+// same archetype as the one PrewarmArchetype creates for these types.
+//
+// Parameters:
+//   - types: The component types making up the archetype.
+//
+// Returns:
+//   - The mask of the archetype.
+func (w *World) MaskOf(types ...reflect.Type) Mask {
+	var mask bitmask256
+	for _, t := range types {
+		mask.set(w.getCompTypeID(t))
+	}
+	return mask
+}
+
+// HasAll reports, for each of the given entities, whether it is alive and has
+// every listed component. It is the batched form of a per-entity membership
+// check: the component IDs are resolved and the read lock is taken once for
+// the whole list, which suits network reconciliation or UI list building over
+// many entities.
+//
+// Parameters:
+//   - entities: The entities to check.
+//   - types: The component types the entities must have. With no types, the
+//     result only reports which entities are alive.
+//
+// Returns:
+//   - A slice parallel to entities. Every element is false if one of the types
+//     is not a registered component.
+func (w *World) HasAll(entities []Entity, types ...reflect.Type) []bool {
+	result := make([]bool, len(entities))
+	var mask bitmask256
+	for _, t := range types {
+		id, ok := w.lookupCompTypeID(t)
+		if !ok {
+			return result
+		}
+		mask.set(id)
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var transient bitmask256
+	for i := range mask {
+		transient[i] = mask[i] & w.transientMask[i]
+		mask[i] &^= transient[i]
+	}
+	for i, e := range entities {
+		result[i] = w.IsValidNoLock(e) && w.entityMaskNoLock(e).contains(mask) && w.hasTransientsNoLock(e, transient)
+	}
+	return result
+}
+
+// MoveEntities applies the same structural change to every listed entity:
+// the components in add are added, zero-valued, and those in remove are
+// removed. The entities are grouped by source archetype first, so each
+// distinct source resolves its destination archetype once instead of once per
+// entity and per component; this is the batched form of "apply this state
+// change to the selected units". Components the entity already has are kept
+// with their values, a type listed in both add and remove is removed, and
+// unregistered types in remove are ignored. Invalid entities and duplicates
+// are ignored. The world's mutation version is bumped at most once.
+//
+// Parameters:
+//   - entities: The entities to modify.
+//   - add: The component types to add to each entity.
+//   - remove: The component types to remove from each entity.
+//
+// It panics if a type is a transient component (see RegisterTransient).
+func (w *World) MoveEntities(entities []Entity, add []reflect.Type, remove []reflect.Type) {
+	var addMask, removeMask bitmask256
+	addIDs := make([]uint8, 0, len(add))
+	for _, t := range add {
+		id := w.getCompTypeID(t)
+		addMask.set(id)
+		addIDs = append(addIDs, id)
+	}
+	for _, t := range remove {
+		if id, ok := w.lookupCompTypeID(t); ok {
+			removeMask.set(id)
+		}
+	}
+	w.checkOpen()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.transientMask {
+		if (addMask[i]|removeMask[i])&w.transientMask[i] != 0 {
+			panic("ecs: MoveEntities cannot add or remove a transient component")
+		}
+	}
+	targets := make(map[int]*archetype) // source archetype index -> destination
+	moved := false
+	for _, e := range entities {
+		if !w.IsValidNoLock(e) {
+			continue
+		}
+		meta := &w.entities.metas[e.ID]
+		dst, ok := targets[meta.archetypeIndex]
+		if !ok {
+			src := w.archetypes.archetypes[meta.archetypeIndex]
+			mask := src.mask
+			for i := range mask {
+				mask[i] = (mask[i] | addMask[i]) &^ removeMask[i]
+			}
+			dst = w.transitionNoLock(src, mask)
+			if dst == nil {
+				specs, _ := w.maskSpecs(mask)
+				dst = w.getOrCreateArchetypeNoLock(mask, specs)
+			}
+			targets[meta.archetypeIndex] = dst
+			targets[dst.index] = dst // entities listed twice stay put
+		}
+		if dst.index == meta.archetypeIndex {
+			continue
+		}
+		src := w.archetypes.archetypes[meta.archetypeIndex]
+		w.moveEntityNoLock(meta, dst)
+		for _, id := range addIDs {
+			if dst.mask.has(id) && !src.mask.has(id) {
+				w.markAdded(id, e.ID)
+			}
+		}
+		moved = true
+	}
+	if moved {
+		w.mutationVersion.Add(1)
+	}
+}
+
+// EnsureCapacity prepares the world to hold count more entities in the
+// archetype identified by mask, creating the archetype if needed. Growing once
+// up front, e.g. before spawning a burst of bullets, replaces the repeated
+// doublings that would otherwise reallocate every archetype's storage while
+// the entities are created. It panics if mask contains a component ID that is
+// not registered. See MaskOf to build a mask from component types.
+//
+// Parameters:
+//   - mask: The mask of the archetype the entities will be created in.
+//   - count: The number of entities to make room for.
+func (w *World) EnsureCapacity(mask Mask, count int) {
+	w.checkOpen()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.archetypes.maskToArcIndex[mask]; !ok {
+		specs, bad := w.maskSpecs(mask)
+		if bad >= 0 {
+			panic(fmt.Sprintf("ecs: EnsureCapacity mask contains unregistered component ID %d", bad))
+		}
+		w.getOrCreateArchetypeNoLock(mask, specs)
+	}
+	w.ensureStorage(w.archetypes.archetypes[w.archetypes.maskToArcIndex[mask]])
+	if count > 0 {
+		oldCap := w.entities.capacity
+		w.reserveNoLock(count)
+		if w.entities.capacity != oldCap {
+			// columns were reallocated: make filters drop their cached pointers
+			w.mutationVersion.Add(1)
+		}
+	}
+}
+
+// maskSpecs builds the component specs of every ID set in mask, in ID order.
+// If the mask contains an unregistered ID, it returns that ID as bad, and -1
+// otherwise.
+func (w *World) maskSpecs(mask bitmask256) (specs []compSpec, bad int) {
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	for id := range MaxComponentTypes {
+		if !mask.has(uint8(id)) {
+			continue
+		}
+		if id >= int(w.components.nextCompTypeID) {
+			return nil, id
+		}
+		specs = append(specs, compSpec{id: uint8(id), typ: w.components.compIDToType[id], size: w.components.compIDToSize[id]})
+	}
+	return specs, -1
+}
+
+// PrewarmFilter computes the filter's set of matching archetypes up front, so
+// its first Reset or Query does not have to scan the world. It is typically
+// called after the archetypes the filter will see have been prewarmed.
+//
+// Parameters:
+//   - f: A filter created for this world.
+func (w *World) PrewarmFilter(f AnyFilter) {
+	c := f.cache()
+	if c.world != w {
+		panic("ecs: PrewarmFilter called with a filter bound to another World")
+	}
+	c.checkWorld()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if c.isArchetypeStale() {
+		c.updateMatching()
+	}
+}
+
+// IsValid checks if the given entity is currently alive by verifying that its
+// version matches the world's current version for that ID. This prevents
+// "stale" entity references from accessing incorrect data after an entity has
+// been deleted and its ID recycled.
+//
+// Parameters:
+//   - e: The Entity to validate.
+//
+// Returns:
+//   - true if the entity is valid, false otherwise.
+func (w *World) IsValid(e Entity) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.IsValidNoLock(e)
+}
+
+// IsValidNoLock checks if the given entity is currently alive by verifying
+// that its version matches the world's current version for that ID. This
+// prevents "stale" entity references from accessing incorrect data after an
+// entity has been deleted and its ID recycled.
+//
+// This is the lock-free version of IsValid and should only be called when the
+// world's mutex is already held.
+//
+// Parameters:
+//   - e: The Entity to validate.
+//
+// Returns:
+//   - true if the entity is valid, false otherwise.
+func (w *World) IsValidNoLock(e Entity) bool {
+	if int(e.ID) >= len(w.entities.metas) {
+		return false
+	}
+	meta := w.entities.metas[e.ID]
+	return meta.version != 0 && meta.version == e.Version
+}
+
+// EntityState reports whether the handle refers to a live entity, to an
+// entity that has since been removed (a stale handle), or was never issued by
+// the world at all. Unlike IsValid, it tells the last two cases apart, which
+// helps when chasing use-after-free bugs in code holding on to old handles.
+// Once the world's version counter has wrapped around, every dead handle of an
+// ID that was used is reported as recycled.
+//
+// Parameters:
+//   - e: The Entity handle to inspect.
+//
+// Returns:
+//   - EntityAlive, EntityRecycled or EntityInvalid.
+func (w *World) EntityState(e Entity) EntityState {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.IsValidNoLock(e) {
+		return EntityAlive
+	}
+	// Versions come from a single increasing counter, so a dead handle was
+	// issued only if its ID was used and, until the counter wraps around, its
+	// version is not above the latest one issued for that ID.
+	if int(e.ID) >= len(w.entities.metas) || e.Version == 0 {
+		return EntityInvalid
+	}
+	issued := w.entities.metas[e.ID].issued
+	if issued == 0 || (e.Version > issued && !w.entities.versionsWrapped) {
+		return EntityInvalid
+	}
+	return EntityRecycled
+}
+
+// Lookup resolves a packed entity received from outside the world, such as
+// from a script or over the network, and checks that it is still alive.
+//
+// Parameters:
+//   - id: The packed entity, as returned by Entity.Packed.
+//
+// Returns:
+//   - The Entity and true if it is alive, or the zero Entity and false.
+func (w *World) Lookup(id EntityID) (Entity, bool) {
+	e := UnpackEntity(id)
+	if !w.IsValid(e) {
+		return Entity{}, false
+	}
+	return e, true
+}
+
+// SetUserData attaches an arbitrary 64-bit value to the entity, such as a
+// network ID or a scene-graph index. The value lives in the entity's metadata
+// rather than in a component, so it does not change the entity's archetype and
+// is not a structural change. It is reset to zero when the entity is removed.
+// Invalid entities are ignored.
+//
+// Parameters:
+//   - e: The Entity to tag.
+//   - v: The value to store.
+func (w *World) SetUserData(e Entity, v uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.IsValidNoLock(e) {
+		return
+	}
+	w.entities.metas[e.ID].userData = v
+}
+
+// GetUserData returns the value previously attached to the entity with
+// SetUserData.
+//
+// Parameters:
+//   - e: The Entity to read.
+//
+// Returns:
+//   - The stored value, or 0 if none was set or the entity is invalid.
+func (w *World) GetUserData(e Entity) uint64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.IsValidNoLock(e) {
+		return 0
+	}
+	return w.entities.metas[e.ID].userData
+}
+
+// Close releases all storage owned by the world: entity metadata, archetype
+// component columns and resources. Column pointers are cleared and archetype
+// sizes are set to zero, so that the memory can be reclaimed by the garbage
+// collector and any filter still referring to the world yields no entities.
+//
+// Using a World after Close is undefined. Obvious misuse, such as creating
+// entities, builders or filters, or resetting an existing filter, panics.
+// Calling Close more than once is a no-op.
+func (w *World) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed.Load() {
+		return
+	}
+	w.closed.Store(true)
+	for _, a := range w.archetypes.archetypes {
+		for _, cid := range a.compOrder {
+			a.compPointers[cid] = nil
+		}
+		a.entityIDs = nil
+		a.size = 0
+	}
+	w.archetypes.archetypes = nil
+	clear(w.archetypes.maskToArcIndex)
+	w.entities.metas = nil
+	w.entities.freeIDs = nil
+	w.entities.capacity = 0
+	w.resources.Clear()
+	w.changes = [MaxComponentTypes]*changeSet{}
+	w.trackedIDs = nil
+	w.versions = [MaxComponentTypes]*versionSet{}
+	w.versionedIDs = nil
+	w.transients = [MaxComponentTypes]any{}
+	w.transientMask = bitmask256{}
+	w.owned = nil
+	w.pendingOwned = nil
+	w.relations = nil
+	w.matchMu.Lock()
+	w.matchSets = nil
+	w.matchMu.Unlock()
+	w.resetEvents()
+	w.asyncMu.Lock()
+	w.asyncOps = nil
+	w.asyncMu.Unlock()
+	w.archetypes.archetypeVersion.Add(1)
+	w.mutationVersion.Add(1)
+}
+
+// checkOpen panics if the world has been closed.
+func (w *World) checkOpen() {
+	if w.closed.Load() {
+		panic("ecs: use of a closed World")
+	}
+}
+
+// Resources returns the world's resource manager. It provides a thread-safe,
+// generic key-value store for global data that needs to be accessible from
+// anywhere in the application, such as configuration objects, resource managers,
+// or event buses.
+//
+// Returns:
+//   - A pointer to the Resources object.
+func (w *World) Resources() *Resources {
+	return w.resources
+}
+
+// register or fetch a component type ID for T.
+func (w *World) getCompTypeID(t reflect.Type) uint8 {
+	w.components.mu.RLock()
+	if id, ok := w.components.compTypeMap[t]; ok {
+		w.components.mu.RUnlock()
+		return id
+	}
+	w.components.mu.RUnlock()
+	w.components.mu.Lock()
+	defer w.components.mu.Unlock()
+	if id, ok := w.components.compTypeMap[t]; ok {
+		return id
+	}
+	if w.components.mu.frozen.Load() {
+		panic(fmt.Sprintf("ecs: component type %v registered after FreezeComponents", t))
+	}
+	return w.registerCompTypeNoLock(t)
+}
+
+// RegisterComponent registers the component type `T` in the world's
+// component registry without creating any archetype, so that every type can
+// be known up front, before FreezeComponents. Registering a type twice is a
+// no-op.
+//
+// Parameters:
+//   - w: The World in which to register the component.
+func RegisterComponent[T any](w *World) {
+	w.getCompTypeID(reflect.TypeFor[T]())
+}
+
+// FreezeComponents marks the world's component registry as immutable. Every
+// Builder, Filter and generic function looks component types up in the
+// registry, under its read lock; once frozen, those lookups skip the lock
+// entirely, which removes its cost for worlds whose component types are all
+// known at startup. Call it after registering every type, with
+// RegisterComponent or by using them. Freezing is permanent, and applies to
+// every World sharing the registry (see NewWorldWithRegistry). Freezing twice
+// is a no-op.
+//
+// Any later attempt to use a component type that is not registered panics.
+func (w *World) FreezeComponents() {
+	w.components.mu.Lock()
+	defer w.components.mu.Unlock()
+	w.components.mu.frozen.Store(true)
+}
+
+// registerCompTypeNoLock assigns the next free component type ID to t with
+// no-lock. Component IDs are stored as uint8 and index the bitmask256 masks,
+// so registering more than MaxComponentTypes types panics instead of
+// silently wrapping around and corrupting archetype masks.
+func (w *World) registerCompTypeNoLock(t reflect.Type) uint8 {
+	if w.components.nextCompTypeID >= MaxComponentTypes {
+		panic(fmt.Sprintf("ecs: exceeded MaxComponentTypes (%d) registering component type %v", MaxComponentTypes, t))
+	}
+	id := uint8(w.components.nextCompTypeID)
+	w.components.compTypeMap[t] = id
+	w.components.compIDToType[id] = t
+	w.components.compIDToSize[id] = t.Size()
+	w.components.nextCompTypeID++
+	return id
+}
+
+// lookupCompTypeID returns the component type ID for t without registering it.
+func (w *World) lookupCompTypeID(t reflect.Type) (uint8, bool) {
+	w.components.mu.RLock()
+	defer w.components.mu.RUnlock()
+	id, ok := w.components.compTypeMap[t]
+	return id, ok
+}
+
+// OnArchetypeCreated registers fn to be called whenever the world builds a new
+// archetype, i.e. the first time an entity shape is used. Profilers and tools
+// can use it to watch the archetype set grow and catch accidental archetype
+// explosions early. Passing nil removes the callback.
+//
+// fn receives the archetype's mask and its component types in storage order.
+// It runs while the world's write lock is held, so it must not call back into
+// the World.
+//
+// Parameters:
+//   - fn: The function to call for each new archetype.
+func (w *World) OnArchetypeCreated(fn func(mask Mask, types []reflect.Type)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onArchetype = fn
+}
+
+// notifyArchetypeCreated reports the new archetype a, built from specs, to the
+// OnArchetypeCreated callback.
+func (w *World) notifyArchetypeCreated(a *archetype, specs []compSpec) {
+	types := make([]reflect.Type, len(specs))
+	for i, sp := range specs {
+		types[i] = sp.typ
+	}
+	w.onArchetype(a.mask, types)
+}
+
+// getOrCreateArchetype returns an archetype for the given mask;
+// if missing, allocates component storage arrays of length cap.
 func (w *World) getOrCreateArchetype(mask bitmask256, specs []compSpec) *archetype {
+	w.checkOpen()
 	w.mu.RLock()
 	if idx, ok := w.archetypes.maskToArcIndex[mask]; ok {
 		a := w.archetypes.archetypes[idx]
@@ -351,36 +1557,156 @@ func (w *World) getOrCreateArchetype(mask bitmask256, specs []compSpec) *archety
 	if idx, ok := w.archetypes.maskToArcIndex[mask]; ok {
 		return w.archetypes.archetypes[idx]
 	}
+	if t := w.transientInMaskNoLock(mask); t != nil {
+		panic(fmt.Sprintf("ecs: transient component %v cannot be stored in an archetype; use SetComponent", t))
+	}
 	// build new archetype
 	a := &archetype{
 		index:     len(w.archetypes.archetypes),
 		mask:      mask,
 		size:      0,
-		entityIDs: make([]Entity, w.entities.capacity),
 		compOrder: make([]uint8, 0, len(specs)),
 	}
-	w.components.mu.RLock()
+	specs = w.prioritizeSpecs(specs)
 	for _, sp := range specs {
-		// allocate []T of length=cap
-		slice := reflect.MakeSlice(reflect.SliceOf(sp.typ), w.entities.capacity, w.entities.capacity)
-		a.compPointers[sp.id] = slice.UnsafePointer()
-		a.compSizes[sp.id] = sp.size
-		a.compOrder = append(a.compOrder, sp.id)
+		a.addColumn(sp)
+	}
+	if !w.lazyColumns {
+		w.allocStorage(a)
 	}
-	w.components.mu.RUnlock()
 	w.archetypes.archetypes = append(w.archetypes.archetypes, a)
 	w.archetypes.maskToArcIndex[mask] = a.index
 	w.archetypes.archetypeVersion.Add(1)
+	w.stats.archetypeCreations.Add(1)
+	if w.onArchetype != nil {
+		w.notifyArchetypeCreated(a, specs)
+	}
 	return a
 }
 
+// SetMaxEntities sets a hard limit on the number of live entities in the
+// world. Once it is reached, entity creation fails instead of growing the
+// world: single-entity constructors return the zero Entity (or false, for
+// Builder.TryNewEntity) and batch constructors create only as many entities as
+// the limit allows and report how many they created. The world's capacity
+// never grows past the limit. This protects servers from unbounded memory use
+// caused, for instance, by a spawning bug.
+//
+// A limit below the current number of live entities prevents any creation
+// until enough entities are removed. n <= 0 removes the limit.
+//
+// Parameters:
+//   - n: The maximum number of live entities.
+func (w *World) SetMaxEntities(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.maxEntities = max(n, 0)
+}
+
+// allowedNoLock returns how many of count entities may be created without
+// exceeding the limit set by SetMaxEntities.
+func (w *World) allowedNoLock(count int) int {
+	if w.maxEntities == 0 {
+		return count
+	}
+	live := w.entities.capacity - len(w.entities.freeIDs)
+	return min(count, max(w.maxEntities-live, 0))
+}
+
+// capToLimit clamps a new capacity so the world does not grow past the limit
+// set by SetMaxEntities.
+func (w *World) capToLimit(newCap int) int {
+	if w.maxEntities == 0 {
+		return newCap
+	}
+	return min(newCap, max(w.maxEntities, w.entities.capacity))
+}
+
+// maxEntityIDs is the largest entity capacity a world can reach: IDs are
+// uint32, and the capacity must also fit an int on 32-bit platforms.
+const maxEntityIDs = math.MaxUint32 & math.MaxInt
+
+// expand grows the world's entity capacity by at least one, following the
+// growth strategy. It panics if the capacity cannot grow any further, instead
+// of leaving callers without a free ID.
 func (w *World) expand() {
 	oldCap := w.entities.capacity
-	newCap := oldCap * 2
-	if newCap == 0 {
-		newCap = 1
+	w.expandTo(w.grownCapNoLock(oldCap + 1))
+	if w.entities.capacity <= oldCap {
+		panic(fmt.Sprintf("ecs: cannot grow the world beyond %d entities", oldCap))
+	}
+}
+
+// reserveNoLock makes sure at least count entity IDs are free with no-lock,
+// or as many as the limit set by SetMaxEntities still allows. The capacity is
+// grown once to the size chosen by the growth strategy, so storage is
+// reallocated once instead of once per doubling. It panics if the IDs cannot
+// be made available, so callers never pop more IDs than exist.
+func (w *World) reserveNoLock(count int) {
+	count = w.allowedNoLock(count)
+	free := len(w.entities.freeIDs)
+	if free >= count {
+		return
+	}
+	oldCap := w.entities.capacity
+	if count-free > maxEntityIDs-oldCap {
+		panic(fmt.Sprintf("ecs: cannot create %d entities: the world is limited to %d entity IDs", count, maxEntityIDs))
+	}
+	w.expandTo(w.grownCapNoLock(oldCap + count - free))
+	if len(w.entities.freeIDs) < count {
+		panic(fmt.Sprintf("ecs: cannot grow the world to hold %d more entities", count))
+	}
+}
+
+// SetGrowthStrategy chooses how the world's entity capacity grows when it runs
+// out of room. The capacity sizes the entity metadata and every archetype's
+// columns, so each growth reallocates and copies them; the strategy trades
+// the number of reallocations against the memory held in reserve. The default
+// doubles the capacity until it fits, which amortizes bursty spawning; a
+// server with predictable steady growth may prefer fixed chunks to bound
+// memory, for instance:
+//
+//	w.SetGrowthStrategy(func(current, needed int) int {
+//		return (needed + 4095) / 4096 * 4096
+//	})
+//
+// The result is raised to needed if it is smaller, and clamped to the limit
+// set by SetMaxEntities and to the entity ID space. Growing the world is a
+// structural change: it bumps the mutation version, once per operation, so
+// filters refresh their column pointers.
+//
+// Parameters:
+//   - grow: Returns the new capacity given the current one and the minimum
+//     capacity needed. nil restores doubling.
+func (w *World) SetGrowthStrategy(grow func(current, needed int) int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.growth = grow
+}
+
+// grownCapNoLock returns the capacity to grow to so the world holds at least
+// needed entities, following the growth strategy and clamped to the entity
+// limit and the ID space.
+func (w *World) grownCapNoLock(needed int) int {
+	var newCap int
+	if w.growth != nil {
+		newCap = w.growth(w.entities.capacity, needed)
+	} else {
+		newCap = max(w.entities.capacity, 1)
+		for newCap < needed && newCap <= maxEntityIDs/2 {
+			newCap *= 2
+		}
 	}
+	return w.capToLimit(min(max(newCap, needed), maxEntityIDs))
+}
+
+// expandTo grows the entity metadata, free list and storage to newCap IDs.
+func (w *World) expandTo(newCap int) {
+	oldCap := w.entities.capacity
 	delta := newCap - oldCap
+	if delta > 0 {
+		w.stats.expansions.Add(1)
+	}
 	// extend metas
 	newMetas := make([]entityMeta, delta)
 	for i := range newMetas {
@@ -389,13 +1715,21 @@ func (w *World) expand() {
 		newMetas[i].version = 0
 	}
 	w.entities.metas = append(w.entities.metas, newMetas...)
-	// extend freeIDs with new IDs in reverse order
+	// extend freeIDs with new IDs in reverse order, or in ascending order for
+	// the min-heap: the new IDs exceed every free one, so the heap stays valid
 	newFree := make([]uint32, delta)
 	for i := range delta {
-		newFree[i] = uint32(newCap - 1 - i)
+		if w.entities.deterministic {
+			newFree[i] = uint32(oldCap + i)
+		} else {
+			newFree[i] = uint32(newCap - 1 - i)
+		}
 	}
 	w.entities.freeIDs = append(w.entities.freeIDs, newFree...)
 	w.entities.capacity = newCap
+	w.growChangeSets()
+	w.growVersionSets()
+	w.growTouched()
 	// resize all archetypes
 	for _, a := range w.archetypes.archetypes {
 		a.resizeTo(newCap, w)
@@ -407,28 +1741,220 @@ func (w *World) expand() {
 func (w *World) createEntity(a *archetype) Entity {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	ent := w.createEntityNoLock(a)
+	if ent.Version != 0 {
+		w.mutationVersion.Add(1)
+	}
+	return ent
+}
+
+// createEntityNoLock places a new entity into the given archetype with
+// no-lock and without bumping the mutation version. It returns the zero Entity
+// if the limit set by SetMaxEntities is reached.
+func (w *World) createEntityNoLock(a *archetype) Entity {
+	w.checkOpen()
+	w.checkStructural()
+	if w.allowedNoLock(1) == 0 {
+		return Entity{}
+	}
 	if len(w.entities.freeIDs) == 0 {
 		w.expand()
 	}
-	// pop an ID
-	last := len(w.entities.freeIDs) - 1
-	id := w.entities.freeIDs[last]
-	w.entities.freeIDs = w.entities.freeIDs[:last]
+	id := w.popFreeIDNoLock()
 	meta := &w.entities.metas[id]
 	meta.archetypeIndex = a.index
 	meta.index = a.size
-	meta.version = w.entities.nextEntityVer
+	meta.version = w.nextVersionNoLock()
+	meta.issued = meta.version
 	ent := Entity{ID: id, Version: meta.version}
+	w.touchEntity(id)
 	// place into archetype
+	w.ensureStorage(a)
 	a.entityIDs[a.size] = ent
 	a.size++
-	w.entities.nextEntityVer++
-	w.mutationVersion.Add(1)
+	if len(a.recyclers) > 0 {
+		a.recycle(a.size-1, 1)
+	}
+	if len(a.defaulters) > 0 {
+		a.applyDefaults(a.size-1, 1)
+	}
+	if len(w.trackedIDs) > 0 {
+		w.markCreated(a, a.size-1, 1)
+	}
 	return ent
 }
 
-// removeFromArchetype removes the entity with no-lock from the archetype without freeing the ID or invalidating version.
+// createEntitiesNoLock appends up to count new entities to the archetype with
+// no-lock and without bumping the mutation version, expanding the world if
+// needed. Fewer entities are created when the limit set by SetMaxEntities is
+// reached. It returns the index of the first new entity inside the archetype
+// and the number of entities created.
+func (w *World) createEntitiesNoLock(a *archetype, count int) (int, int) {
+	w.checkOpen()
+	w.checkStructural()
+	count = w.allowedNoLock(count)
+	if count == 0 {
+		return a.size, 0
+	}
+	w.reserveNoLock(count)
+	w.ensureStorage(a)
+	startSize := a.size
+	a.size += count
+	popped := w.popFreeIDsNoLock(count)
+	for k := 0; k < count; k++ {
+		id := popped[k]
+		meta := &w.entities.metas[id]
+		meta.archetypeIndex = a.index
+		meta.index = startSize + k
+		meta.version = w.nextVersionNoLock()
+		meta.issued = meta.version
+		ent := Entity{ID: id, Version: meta.version}
+		a.entityIDs[startSize+k] = ent
+		w.touchEntity(id)
+	}
+	if len(a.recyclers) > 0 {
+		a.recycle(startSize, count)
+	}
+	if len(a.defaulters) > 0 {
+		a.applyDefaults(startSize, count)
+	}
+	if len(w.trackedIDs) > 0 {
+		w.markCreated(a, startSize, count)
+	}
+	return startSize, count
+}
+
+// nextVersionNoLock returns the version for a newly created entity. Versions
+// come from a single world-wide counter, so an ID that is reused always gets a
+// version above that of every handle issued for it before, and a freed handle
+// never equals a later one. The counter skips 0 when it wraps around, since
+// version 0 marks a dead slot and the zero Entity must never be alive.
+func (w *World) nextVersionNoLock() uint32 {
+	v := w.entities.nextEntityVer
+	w.entities.nextEntityVer++
+	if w.entities.nextEntityVer == 0 {
+		w.entities.nextEntityVer = 1
+		w.entities.versionsWrapped = true
+	}
+	return v
+}
+
+// removeEntityNoLock invalidates the entity and recycles its ID with no-lock
+// and without bumping the mutation version. It reports whether the entity was
+// valid.
+func (w *World) removeEntityNoLock(e Entity) bool {
+	if !w.IsValidNoLock(e) {
+		return false
+	}
+	meta := &w.entities.metas[e.ID]
+	a := w.archetypes.archetypes[meta.archetypeIndex]
+	w.destroyInArchetype(a, meta)
+	w.releaseEntityNoLock(e.ID)
+	w.removeOwnedNoLock()
+	return true
+}
+
+// releaseEntityNoLock invalidates the metadata of the entity ID and pushes it
+// onto the free list with no-lock. Every code path that destroys an entity goes
+// through here, so per-entity state stored in the metadata is reset in one
+// place.
+func (w *World) releaseEntityNoLock(id uint32) {
+	w.checkStructural()
+	meta := &w.entities.metas[id]
+	if meta.owner != (Entity{}) || len(w.owned) > 0 {
+		w.releaseOwnershipNoLock(meta, id)
+	}
+	if len(w.relations) > 0 {
+		w.releaseRelationsNoLock(Entity{ID: id, Version: meta.version})
+	}
+	meta.archetypeIndex = -1
+	meta.index = -1
+	meta.version = 0
+	meta.userData = 0
+	if len(w.trackedIDs) > 0 {
+		w.forgetChanges(id)
+	}
+	w.pushFreeIDNoLock(id)
+}
+
+// popFreeIDNoLock takes a free entity ID: the most recently freed one, or the
+// lowest one in deterministic mode. The free list must not be empty.
+func (w *World) popFreeIDNoLock() uint32 {
+	free := w.entities.freeIDs
+	last := len(free) - 1
+	if !w.entities.deterministic {
+		id := free[last]
+		w.entities.freeIDs = free[:last]
+		return id
+	}
+	id := free[0]
+	free[0] = free[last]
+	free = free[:last]
+	// sift down
+	for i := 0; ; {
+		c := 2*i + 1
+		if c >= len(free) {
+			break
+		}
+		if c+1 < len(free) && free[c+1] < free[c] {
+			c++
+		}
+		if free[i] <= free[c] {
+			break
+		}
+		free[i], free[c] = free[c], free[i]
+		i = c
+	}
+	w.entities.freeIDs = free
+	return id
+}
+
+// popFreeIDsNoLock takes count free entity IDs at once. The returned slice is
+// only valid until the free list changes again.
+func (w *World) popFreeIDsNoLock(count int) []uint32 {
+	if !w.entities.deterministic {
+		n := len(w.entities.freeIDs) - count
+		popped := w.entities.freeIDs[n:]
+		w.entities.freeIDs = w.entities.freeIDs[:n]
+		return popped
+	}
+	popped := w.entities.popScratch[:0]
+	for range count {
+		popped = append(popped, w.popFreeIDNoLock())
+	}
+	w.entities.popScratch = popped
+	return popped
+}
+
+// pushFreeIDNoLock returns an entity ID to the free list.
+func (w *World) pushFreeIDNoLock(id uint32) {
+	free := append(w.entities.freeIDs, id)
+	if w.entities.deterministic {
+		// sift up
+		for i := len(free) - 1; i > 0; {
+			p := (i - 1) / 2
+			if free[p] <= free[i] {
+				break
+			}
+			free[i], free[p] = free[p], free[i]
+			i = p
+		}
+	}
+	w.entities.freeIDs = free
+}
+
+// removeFromArchetype removes the entity with no-lock from the archetype it is
+// moving out of, without freeing the ID or invalidating version. Callers are
+// responsible for bumping the mutation version.
 func (w *World) removeFromArchetype(a *archetype, meta *entityMeta) {
+	w.stats.entityMoves.Add(1)
+	w.removeRow(a, meta)
+}
+
+// removeRow removes the entity's row with no-lock from the archetype by moving
+// the last row into its place.
+func (w *World) removeRow(a *archetype, meta *entityMeta) {
+	w.checkStructural()
 	idx := meta.index
 	lastIdx := a.size - 1
 	if idx < lastIdx {
@@ -442,7 +1968,108 @@ func (w *World) removeFromArchetype(a *archetype, meta *entityMeta) {
 		w.entities.metas[lastEnt.ID].index = idx
 	}
 	a.size--
-	w.mutationVersion.Add(1)
+	a.clearSlots(a.size, 1)
+}
+
+// destroyInArchetype removes the entity being destroyed with no-lock from the
+// archetype, like removeRow. With Recycler columns the entity's row
+// is swapped with the last one instead of overwritten, so the dead components
+// end up in the freed slot with their buffers intact.
+func (w *World) destroyInArchetype(a *archetype, meta *entityMeta) {
+	w.checkStructural()
+	if len(a.recyclers) == 0 {
+		w.removeRow(a, meta)
+		return
+	}
+	if lastIdx := a.size - 1; meta.index < lastIdx {
+		w.swapRows(a, meta.index, lastIdx)
+	}
+	a.size--
+	a.clearDeadSlots(a.size, 1)
+}
+
+// transitionNoLock returns, with no-lock, the existing archetype with the
+// given mask that entities of a move to when components are added or removed,
+// or nil if it does not exist yet. Entities usually move along the same few
+// transitions over and over, so a short per-archetype list of recent targets
+// is checked before the world's mask index, and the resolved target is
+// recorded in it.
+func (w *World) transitionNoLock(a *archetype, mask bitmask256) *archetype {
+	for i := range a.edges {
+		if a.edges[i].mask == mask {
+			return a.edges[i].to
+		}
+	}
+	idx, ok := w.archetypes.maskToArcIndex[mask]
+	if !ok {
+		return nil
+	}
+	to := w.archetypes.archetypes[idx]
+	if len(a.edges) < maxArchetypeEdges {
+		a.edges = append(a.edges, archetypeEdge{mask: mask, to: to})
+	}
+	return to
+}
+
+// neighbourArchetypeNoLock returns, with no-lock, the archetype whose mask is
+// a's mask with component id added (add is true) or removed, creating it if
+// needed.
+func (w *World) neighbourArchetypeNoLock(a *archetype, id uint8, add bool) *archetype {
+	mask := a.mask
+	if add {
+		mask.set(id)
+	} else {
+		mask.unset(id)
+	}
+	if t := w.transitionNoLock(a, mask); t != nil {
+		return t
+	}
+	var tempSpecs [MaxComponentTypes]compSpec
+	count := 0
+	w.components.mu.RLock()
+	for _, cid := range a.compOrder {
+		if cid == id {
+			continue
+		}
+		tempSpecs[count] = compSpec{id: cid, typ: w.components.compIDToType[cid], size: w.components.compIDToSize[cid]}
+		count++
+	}
+	if add {
+		tempSpecs[count] = compSpec{id: id, typ: w.components.compIDToType[id], size: w.components.compIDToSize[id]}
+		count++
+	}
+	w.components.mu.RUnlock()
+	return w.getOrCreateArchetypeNoLock(mask, tempSpecs[:count])
+}
+
+// moveAllNoLock moves every entity of src to the end of dst with no-lock and
+// without bumping the mutation version. Columns present in both archetypes are
+// bulk-copied; columns only in dst are left for the caller to fill, and
+// columns only in src are dropped. It returns the index of the first moved
+// entity inside dst.
+func (w *World) moveAllNoLock(src, dst *archetype) int {
+	w.checkStructural()
+	w.ensureStorage(dst)
+	n := src.size
+	start := dst.size
+	for _, cid := range dst.compOrder {
+		if !src.mask.has(cid) {
+			continue
+		}
+		size := dst.compSizes[cid]
+		memCopy(unsafe.Add(dst.compPointers[cid], uintptr(start)*size), src.compPointers[cid], uintptr(n)*size)
+	}
+	copy(dst.entityIDs[start:start+n], src.entityIDs[:n])
+	for i := 0; i < n; i++ {
+		meta := &w.entities.metas[dst.entityIDs[start+i].ID]
+		meta.archetypeIndex = dst.index
+		meta.index = start + i
+	}
+	dst.size += n
+	w.stats.entityMoves.Add(uint64(n))
+	src.clearSlots(0, n)
+	src.size = 0
+	return start
 }
 
 // memCopy copies size bytes from src to dst using built-in copy for performance.
@@ -455,47 +2082,36 @@ func memCopy(dst, src unsafe.Pointer, size uintptr) {
 	copy(dstBytes, srcBytes)
 }
 
-// getCompTypeIDNoLock returns component type's id with no-lock
-func (w *World) getCompTypeIDNoLock(t reflect.Type) uint8 {
-	if id, ok := w.components.compTypeMap[t]; ok {
-		return id
-	}
-	if id, ok := w.components.compTypeMap[t]; ok {
-		return id
-	}
-	if w.components.nextCompTypeID >= MaxComponentTypes {
-		panic("ecs: too many component types")
-	}
-	id := uint8(w.components.nextCompTypeID)
-	w.components.compTypeMap[t] = id
-	w.components.compIDToType[id] = t
-	w.components.compIDToSize[id] = t.Size()
-	w.components.nextCompTypeID++
-	return id
-}
-
 // getOrCreateArchetypeNoLock returns an archetype for the given mask with no-lock;
 // if missing, allocates component storage arrays of length cap.
 func (w *World) getOrCreateArchetypeNoLock(mask bitmask256, specs []compSpec) *archetype {
+	w.checkOpen()
 	if idx, ok := w.archetypes.maskToArcIndex[mask]; ok {
 		return w.archetypes.archetypes[idx]
 	}
+	if t := w.transientInMaskNoLock(mask); t != nil {
+		panic(fmt.Sprintf("ecs: transient component %v cannot be stored in an archetype; use SetComponent", t))
+	}
 	// build new archetype
 	a := &archetype{
 		index:     len(w.archetypes.archetypes),
 		mask:      mask,
 		size:      0,
-		entityIDs: make([]Entity, w.entities.capacity),
 		compOrder: make([]uint8, 0, len(specs)),
 	}
+	specs = w.prioritizeSpecs(specs)
 	for _, sp := range specs {
-		slice := reflect.MakeSlice(reflect.SliceOf(sp.typ), w.entities.capacity, w.entities.capacity)
-		a.compPointers[sp.id] = slice.UnsafePointer()
-		a.compSizes[sp.id] = sp.size
-		a.compOrder = append(a.compOrder, sp.id)
+		a.addColumn(sp)
+	}
+	if !w.lazyColumns {
+		w.allocStorage(a)
 	}
 	w.archetypes.archetypes = append(w.archetypes.archetypes, a)
 	w.archetypes.maskToArcIndex[mask] = a.index
 	w.archetypes.archetypeVersion.Add(1)
+	w.stats.archetypeCreations.Add(1)
+	if w.onArchetype != nil {
+		w.notifyArchetypeCreated(a, specs)
+	}
 	return a
 }